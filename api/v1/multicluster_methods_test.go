@@ -62,5 +62,32 @@ var _ = Describe("V1 API Methods", func() {
 			Expect(mce.ComponentPresent(api.Discovery)).To(BeTrue())
 			Expect(mce.Enabled(api.Discovery)).To(BeFalse())
 		})
+
+		It("has no unknown components", func() {
+			Expect(mce.UnknownComponents()).To(BeEmpty())
+		})
+	})
+
+	Context("when the target namespace is protected", func() {
+		It("rejects it unless allowed by annotation", func() {
+			mce := makeMCE()
+			mce.Spec.TargetNamespace = "kube-system"
+			Expect(mce.ProtectedTargetNamespace()).To(BeTrue())
+			mce.SetAnnotations(map[string]string{api.AnnotationAllowProtectedNamespace: "true"})
+			Expect(mce.ProtectedTargetNamespace()).To(BeFalse())
+		})
+
+		It("accepts a normal namespace", func() {
+			mce := makeMCE()
+			mce.Spec.TargetNamespace = "multicluster-engine"
+			Expect(mce.ProtectedTargetNamespace()).To(BeFalse())
+		})
+	})
+
+	Context("when the overrides name an unknown component", func() {
+		It("lists the unknown component names", func() {
+			mce := makeMCE(config(api.Discovery, true), config("discvery", true))
+			Expect(mce.UnknownComponents()).To(Equal([]string{"discvery"}))
+		})
 	})
 })
@@ -0,0 +1,51 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// componentConflict is a pair of components that cannot be enabled together
+type componentConflict struct {
+	components [2]string
+	// reason explains why the components cannot run side by side
+	reason string
+}
+
+// componentConflicts is the matrix of mutually exclusive components, such as two controllers
+// reconciling the same resources. Enabling both components of an entry is rejected by the webhook.
+var componentConflicts = []componentConflict{
+	{
+		components: [2]string{ImageBasedInstallOperatorPreview, ImageBasedInstallOperator},
+		reason:     "both name the image-based install operator, enable only " + ImageBasedInstallOperator,
+	},
+}
+
+// ConflictingComponents describes each pair of enabled components that cannot be enabled together
+func (mce *MultiClusterEngine) ConflictingComponents() []string {
+	return conflictingComponents(mce, componentConflicts)
+}
+
+// conflictingComponents describes each of the given conflicts whose components are both enabled
+func conflictingComponents(mce *MultiClusterEngine, matrix []componentConflict) []string {
+	conflicts := []string{}
+	for _, c := range matrix {
+		if mce.Enabled(c.components[0]) && mce.Enabled(c.components[1]) {
+			conflicts = append(conflicts, fmt.Sprintf("%s and %s (%s)", c.components[0], c.components[1], c.reason))
+		}
+	}
+	return conflicts
+}
+
+// validateComponentConflicts returns an error listing the enabled components that cannot be
+// enabled together
+func validateComponentConflicts(r *MultiClusterEngine) error {
+	conflicts := r.ConflictingComponents()
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: these components cannot be enabled together, disable one of each pair: %s",
+		ErrConflictingComponents, strings.Join(conflicts, "; "))
+}
@@ -0,0 +1,6 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+// Hub marks v1 as the version other MultiClusterEngine versions convert through
+func (*MultiClusterEngine) Hub() {}
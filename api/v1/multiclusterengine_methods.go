@@ -18,18 +18,28 @@ limitations under the License.
 
 package v1
 
+import (
+	"regexp"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
 const (
-	ManagedServiceAccount = "managedserviceaccount-preview"
-	ConsoleMCE            = "console-mce"
-	Discovery             = "discovery"
-	Hive                  = "hive"
-	AssistedService       = "assisted-service"
-	ClusterLifecycle      = "cluster-lifecycle"
-	ClusterManager        = "cluster-manager"
-	ServerFoundation      = "server-foundation"
-	HyperShift            = "hypershift-preview"
-	ClusterProxyAddon     = "cluster-proxy-addon"
-	LocalCluster          = "local-cluster"
+	ManagedServiceAccount     = "managedserviceaccount-preview"
+	ConsoleMCE                = "console-mce"
+	Discovery                 = "discovery"
+	Hive                      = "hive"
+	AssistedService           = "assisted-service"
+	ClusterLifecycle          = "cluster-lifecycle"
+	ClusterManager            = "cluster-manager"
+	ServerFoundation          = "server-foundation"
+	ServerFoundationProxy     = "server-foundation-proxy"
+	HyperShift                = "hypershift-preview"
+	ClusterProxyAddon         = "cluster-proxy-addon"
+	LocalCluster              = "local-cluster"
+	ImageBasedInstallOperator = "image-based-install-operator"
 )
 
 var allComponents = []string{
@@ -39,11 +49,13 @@ var allComponents = []string{
 	Discovery,
 	Hive,
 	ServerFoundation,
+	ServerFoundationProxy,
 	ConsoleMCE,
 	ManagedServiceAccount,
 	HyperShift,
 	ClusterProxyAddon,
 	LocalCluster,
+	ImageBasedInstallOperator,
 }
 
 func (mce *MultiClusterEngine) ComponentPresent(s string) bool {
@@ -58,6 +70,302 @@ func (mce *MultiClusterEngine) ComponentPresent(s string) bool {
 	return false
 }
 
+// ComponentArgs returns the container args configured for the component, if any
+func (mce *MultiClusterEngine) ComponentArgs(s string) []string {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.Args
+		}
+	}
+	return nil
+}
+
+// ComponentUpdateStrategy returns the deployment strategy configured for the component, if any
+func (mce *MultiClusterEngine) ComponentUpdateStrategy(s string) *appsv1.DeploymentStrategy {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.UpdateStrategy
+		}
+	}
+	return nil
+}
+
+// ComponentConfigValues returns the config values set for the component, if any
+func (mce *MultiClusterEngine) ComponentConfigValues(s string) map[string]string {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.ConfigValues
+		}
+	}
+	return nil
+}
+
+// ComponentAutomountServiceAccountToken returns the automountServiceAccountToken configured for the
+// component itself, if any
+func (mce *MultiClusterEngine) ComponentAutomountServiceAccountToken(s string) *bool {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.AutomountServiceAccountToken
+		}
+	}
+	return nil
+}
+
+// ComponentPriorityClassName returns the priority class for the component's pods, falling back to
+// the priority class set for all components
+func (mce *MultiClusterEngine) ComponentPriorityClassName(s string) string {
+	if mce.Spec.Overrides == nil {
+		return ""
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s && c.PriorityClassName != "" {
+			return c.PriorityClassName
+		}
+	}
+	return mce.Spec.Overrides.PriorityClassName
+}
+
+// ComponentTerminationGracePeriodSeconds returns the termination grace period for the component's
+// pods, falling back to the grace period set for all components
+func (mce *MultiClusterEngine) ComponentTerminationGracePeriodSeconds(s string) *int64 {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s && c.TerminationGracePeriodSeconds != nil {
+			return c.TerminationGracePeriodSeconds
+		}
+	}
+	return mce.Spec.Overrides.TerminationGracePeriodSeconds
+}
+
+// ComponentReadOnlyRootFilesystem returns true if the containers of the component are to run with a
+// read-only root filesystem
+func (mce *MultiClusterEngine) ComponentReadOnlyRootFilesystem(s string) bool {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.SecurityContext == nil || !mce.Spec.Overrides.SecurityContext.ReadOnlyRootFilesystem {
+		return false
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s && c.WritableRootFilesystem {
+			return false
+		}
+	}
+	return true
+}
+
+// ComponentHostNamespacesDisabled returns true if the pods of the component are to run without
+// host networking, PID and IPC namespaces
+func (mce *MultiClusterEngine) ComponentHostNamespacesDisabled(s string) bool {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.SecurityContext == nil || !mce.Spec.Overrides.SecurityContext.DisableHostNamespaces {
+		return false
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s && c.AllowHostNamespaces {
+			return false
+		}
+	}
+	return true
+}
+
+// ComponentForceEnabled returns true if the component is to be deployed regardless of the platform
+// and capabilities of the cluster
+func (mce *MultiClusterEngine) ComponentForceEnabled(s string) bool {
+	if mce.Spec.Overrides == nil {
+		return false
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.ForceEnable
+		}
+	}
+	return false
+}
+
+// ComponentNodeSelector returns the node selector configured for the component's pods, if any
+func (mce *MultiClusterEngine) ComponentNodeSelector(s string) map[string]string {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.NodeSelector
+		}
+	}
+	return nil
+}
+
+// AnnotationAllowCommandOverride set to "true" allows components to override the entrypoint of
+// their containers. Replacing an entrypoint is only meant for debugging.
+const AnnotationAllowCommandOverride = "multicluster.openshift.io/allow-command-override"
+
+// CommandOverrideAllowed returns true if the allow-command-override annotation is set
+func (mce *MultiClusterEngine) CommandOverrideAllowed() bool {
+	return strings.EqualFold(mce.GetAnnotations()[AnnotationAllowCommandOverride], "true")
+}
+
+// ComponentCommand returns the command replacing the entrypoint of the component's container and
+// the name of that container, empty for the primary container. The command is nil unless it is
+// configured and command overrides are allowed.
+func (mce *MultiClusterEngine) ComponentCommand(s string) (string, []string) {
+	if mce.Spec.Overrides == nil || !mce.CommandOverrideAllowed() {
+		return "", nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.CommandContainer, c.Command
+		}
+	}
+	return "", nil
+}
+
+// ComponentServiceAccountAnnotations returns the annotations configured for the ServiceAccounts of
+// the component, if any
+func (mce *MultiClusterEngine) ComponentServiceAccountAnnotations(s string) map[string]string {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.ServiceAccountAnnotations
+		}
+	}
+	return nil
+}
+
+// ComponentSidecars returns the sidecar containers configured for the component's pods, if any
+func (mce *MultiClusterEngine) ComponentSidecars(s string) []corev1.Container {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.Sidecars
+		}
+	}
+	return nil
+}
+
+// ComponentProbes returns the probe overrides configured for the component, if any
+func (mce *MultiClusterEngine) ComponentProbes(s string) *ProbeOverrides {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.Probes
+		}
+	}
+	return nil
+}
+
+// ComponentImage returns the image reference pinned for the component's primary containers, if any
+func (mce *MultiClusterEngine) ComponentImage(s string) string {
+	if mce.Spec.Overrides == nil {
+		return ""
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+// imageReferenceRegexp matches a container image reference in the [registry/]repository[:tag][@digest]
+// form accepted by the container runtime
+var imageReferenceRegexp = regexp.MustCompile(`^` +
+	`(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[\w][\w.-]{0,127})?` +
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9A-Fa-f]{32,})?$`)
+
+// ValidImageReference returns true if image parses as a container image reference
+func ValidImageReference(image string) bool {
+	return imageReferenceRegexp.MatchString(image)
+}
+
+const (
+	// MinLogLevel and MaxLogLevel bound the log verbosity that can be set for components
+	MinLogLevel int32 = 0
+	MaxLogLevel int32 = 10
+)
+
+// ValidLogLevel returns true if level is within the supported log verbosity range
+func ValidLogLevel(level int32) bool {
+	return level >= MinLogLevel && level <= MaxLogLevel
+}
+
+// MaxFSGroup is the highest valid group ID. 4294967295 is reserved as the invalid ID (-1).
+const MaxFSGroup int64 = 4294967294
+
+// ValidFSGroup returns true if gid is a valid group ID
+func ValidFSGroup(gid int64) bool {
+	return gid >= 0 && gid <= MaxFSGroup
+}
+
+// ComponentFSGroup returns the fsGroup for the component's pods, falling back to the fsGroup in the
+// securityContext override. Returns nil if neither is set.
+func (mce *MultiClusterEngine) ComponentFSGroup(s string) *int64 {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s && c.FSGroup != nil {
+			return c.FSGroup
+		}
+	}
+	if mce.Spec.Overrides.SecurityContext == nil {
+		return nil
+	}
+	return mce.Spec.Overrides.SecurityContext.FSGroup
+}
+
+// ComponentLogLevel returns the log verbosity for the component's containers, falling back to the
+// log level set for all components. Returns nil if neither is set.
+func (mce *MultiClusterEngine) ComponentLogLevel(s string) *int32 {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if c.Name == s && c.LogLevel != nil {
+			return c.LogLevel
+		}
+	}
+	return mce.Spec.Overrides.LogLevel
+}
+
+// PriorityClassNames returns every priority class referenced by the overrides
+func (mce *MultiClusterEngine) PriorityClassNames() []string {
+	if mce.Spec.Overrides == nil {
+		return nil
+	}
+	names := []string{}
+	seen := map[string]bool{}
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	add(mce.Spec.Overrides.PriorityClassName)
+	for _, c := range mce.Spec.Overrides.Components {
+		add(c.PriorityClassName)
+	}
+	return names
+}
+
 func (mce *MultiClusterEngine) Enabled(s string) bool {
 	if mce.Spec.Overrides == nil {
 		return false
@@ -103,10 +411,69 @@ func (mce *MultiClusterEngine) Disable(s string) {
 	})
 }
 
-// a component is valid if its name matches a known component
+// ImageBasedInstallOperatorPreview is the name the image-based-install-operator component had
+// while it was in technology preview
+const ImageBasedInstallOperatorPreview = "image-based-install-operator-preview"
+
+// DeprecatedComponents maps the names of renamed or removed components to a note on what replaced
+// them. Overrides may still name them so that resources created by an older release remain valid.
+var DeprecatedComponents = map[string]string{
+	ImageBasedInstallOperatorPreview: "renamed to " + ImageBasedInstallOperator,
+}
+
+// a component is valid if its name matches a known or deprecated component
 func validComponent(c ComponentConfig) bool {
-	for _, name := range allComponents {
-		if c.Name == name {
+	if _, ok := DeprecatedComponents[c.Name]; ok {
+		return true
+	}
+	return IsKnownComponent(c.Name)
+}
+
+// DeprecatedComponentNames returns the names in the component overrides that match a deprecated
+// component, in the order they are listed
+func (mce *MultiClusterEngine) DeprecatedComponentNames() []string {
+	deprecated := []string{}
+	if mce.Spec.Overrides == nil {
+		return deprecated
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if _, ok := DeprecatedComponents[c.Name]; ok {
+			deprecated = append(deprecated, c.Name)
+		}
+	}
+	return deprecated
+}
+
+// UnknownComponents returns the names in the component overrides that do not match a known
+// component, in the order they are listed
+func (mce *MultiClusterEngine) UnknownComponents() []string {
+	unknown := []string{}
+	if mce.Spec.Overrides == nil {
+		return unknown
+	}
+	for _, c := range mce.Spec.Overrides.Components {
+		if !validComponent(c) {
+			unknown = append(unknown, c.Name)
+		}
+	}
+	return unknown
+}
+
+// EnabledComponentNames returns the known components that are enabled, in a stable order
+func (mce *MultiClusterEngine) EnabledComponentNames() []string {
+	enabled := []string{}
+	for _, c := range allComponents {
+		if mce.Enabled(c) {
+			enabled = append(enabled, c)
+		}
+	}
+	return enabled
+}
+
+// IsKnownComponent returns true if name matches a component managed by the operator
+func IsKnownComponent(name string) bool {
+	for _, c := range allComponents {
+		if name == c {
 			return true
 		}
 	}
@@ -123,3 +490,92 @@ func IsInHostedMode(mce *MultiClusterEngine) bool {
 	}
 	return false
 }
+
+// DefaultWebhookPort is the port the ocm-webhook service listens on unless overridden
+const DefaultWebhookPort int32 = 443
+
+// WebhookPort returns the port the ocm-webhook service listens on
+func (mce *MultiClusterEngine) WebhookPort() int32 {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.Webhook == nil || mce.Spec.Overrides.Webhook.Port == 0 {
+		return DefaultWebhookPort
+	}
+	return mce.Spec.Overrides.Webhook.Port
+}
+
+// WebhookTLSSecretName returns the name of the externally managed secret the ocm-webhook serves
+// with, or an empty string if it uses the certificate generated by the service CA
+func (mce *MultiClusterEngine) WebhookTLSSecretName() string {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.Webhook == nil {
+		return ""
+	}
+	return mce.Spec.Overrides.Webhook.TLSSecretName
+}
+
+// DefaultMonitoringNamespace is the namespace of the platform monitoring stack
+const DefaultMonitoringNamespace = "openshift-monitoring"
+
+// MonitoringNamespace returns the namespace the ServiceMonitors of the components are created in
+func (mce *MultiClusterEngine) MonitoringNamespace() string {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.ServiceMonitor == nil || mce.Spec.Overrides.ServiceMonitor.Namespace == "" {
+		return DefaultMonitoringNamespace
+	}
+	return mce.Spec.Overrides.ServiceMonitor.Namespace
+}
+
+// ProxyServerServiceType returns the type of the ocm-proxyserver Service
+func (mce *MultiClusterEngine) ProxyServerServiceType() corev1.ServiceType {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.ProxyServer == nil || mce.Spec.Overrides.ProxyServer.ServiceType == "" {
+		return corev1.ServiceTypeClusterIP
+	}
+	return mce.Spec.Overrides.ProxyServer.ServiceType
+}
+
+// ProxyServerServiceAnnotations returns the annotations to add to the ocm-proxyserver Service
+func (mce *MultiClusterEngine) ProxyServerServiceAnnotations() map[string]string {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.ProxyServer == nil {
+		return nil
+	}
+	return mce.Spec.Overrides.ProxyServer.ServiceAnnotations
+}
+
+// DiscoveryCacheVolume returns the volume source of the discovery-operator cache, or nil to keep the
+// default
+func (mce *MultiClusterEngine) DiscoveryCacheVolume() *corev1.VolumeSource {
+	if mce.Spec.Overrides == nil || mce.Spec.Overrides.DiscoveryCache == nil {
+		return nil
+	}
+	cache := mce.Spec.Overrides.DiscoveryCache
+	if cache.PersistentVolumeClaim != "" {
+		return &corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: cache.PersistentVolumeClaim},
+		}
+	}
+	if cache.SizeLimit != nil {
+		return &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: cache.SizeLimit}}
+	}
+	return nil
+}
+
+// AnnotationAllowProtectedNamespace set to "true" allows the TargetNamespace to be a protected
+// namespace
+const AnnotationAllowProtectedNamespace = "allowProtectedNamespace"
+
+// protectedNamespaces are the namespaces components are not deployed to unless explicitly allowed
+var protectedNamespaces = []string{"default", "kube-system", "kube-public"}
+
+// protectedNamespacePrefix marks the namespaces reserved for the platform
+const protectedNamespacePrefix = "openshift-"
+
+// IsProtectedNamespace returns true if ns is reserved for the platform
+func IsProtectedNamespace(ns string) bool {
+	return contains(protectedNamespaces, ns) || strings.HasPrefix(ns, protectedNamespacePrefix)
+}
+
+// ProtectedTargetNamespace returns true if the TargetNamespace is a protected namespace and using it
+// has not been allowed through the allowProtectedNamespace annotation
+func (mce *MultiClusterEngine) ProtectedTargetNamespace() bool {
+	if strings.EqualFold(mce.GetAnnotations()[AnnotationAllowProtectedNamespace], "true") {
+		return false
+	}
+	return IsProtectedNamespace(mce.Spec.TargetNamespace)
+}
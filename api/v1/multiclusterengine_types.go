@@ -19,7 +19,9 @@ limitations under the License.
 package v1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -50,7 +52,8 @@ type MultiClusterEngineSpec struct {
 	// Set the nodeselectors
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
-	// Override pull secret for accessing MultiClusterEngine operand and endpoint images
+	// Override pull secret for accessing MultiClusterEngine operand and endpoint images. $(VAR)
+	// references are expanded from the environment of the operator.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Pull Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ImagePullSecret string `json:"imagePullSecret,omitempty"`
 
@@ -70,11 +73,145 @@ type MultiClusterEngineSpec struct {
 type ComponentConfig struct {
 	Name    string `json:"name"`
 	Enabled bool   `json:"enabled"`
+
+	// Args are merged into the args of the component's primary container, the one running the
+	// component's own controller. Each arg must be a flag, with any value given in the
+	// --flag=value form. An arg replaces an existing arg for the same flag (e.g.
+	// --feature-gates=...) rather than being duplicated.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// UpdateStrategy replaces the strategy of the component's deployments. When unset, deployments
+	// with Basic availability roll out with maxUnavailable=0 so that a new pod is ready before
+	// the old one terminates. Singleton components that must not overlap, such as controllers
+	// holding a lease, can set the type to Recreate so the old pod stops before the new one starts.
+	// +optional
+	UpdateStrategy *appsv1.DeploymentStrategy `json:"updateStrategy,omitempty"`
+
+	// PriorityClassName is set on the pods of the component's deployments, taking precedence over
+	// the PriorityClassName in Overrides
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// LogLevel sets the log verbosity (--v) of the component's containers, taking precedence over
+	// the LogLevel in Overrides. Must be between 0 and 10.
+	// +optional
+	LogLevel *int32 `json:"logLevel,omitempty"`
+
+	// ConfigValues tunes component specific settings, which the operator maps to the matching
+	// container args, environment variables or replicas. Keys not supported by the component are
+	// ignored.
+	// Supported keys:
+	// discovery: workers
+	// server-foundation: importControllerReplicas, importControllerWorkers (1 to 100),
+	// importControllerLeaseDuration, importControllerRenewDeadline, importControllerRetryPeriod
+	// (durations such as 137s)
+	// +optional
+	ConfigValues map[string]string `json:"configValues,omitempty"`
+
+	// AutomountServiceAccountToken is set on the pods of the component's deployments, taking
+	// precedence over the AutomountServiceAccountToken in Overrides
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// TerminationGracePeriodSeconds is set on the pods of the component's deployments, taking
+	// precedence over the TerminationGracePeriodSeconds in Overrides
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// Probes overrides the timings of the probes of the component's containers
+	// +optional
+	Probes *ProbeOverrides `json:"probes,omitempty"`
+
+	// WritableRootFilesystem opts the component out of the ReadOnlyRootFilesystem set in the
+	// securityContext of Overrides, for components that need to write to their root filesystem
+	// +optional
+	WritableRootFilesystem bool `json:"writableRootFilesystem,omitempty"`
+
+	// AllowHostNamespaces exempts the component from the DisableHostNamespaces set in the
+	// securityContext of Overrides, leaving host networking, PID and IPC as set by its templates
+	// +optional
+	AllowHostNamespaces bool `json:"allowHostNamespaces,omitempty"`
+
+	// Image is the full image reference (e.g. quay.io/org/image@sha256:...) used for the
+	// component's primary container, the one running the component's own controller, taking
+	// precedence over the images resolved from the image manifest, the image overrides ConfigMap
+	// and the image repository annotation. The other containers of the component keep their images.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ServiceAccountAnnotations are added to the component's ServiceAccounts, e.g. to bind a cloud
+	// IAM role with eks.amazonaws.com/role-arn. Annotations set by the operator are kept.
+	// +optional
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+
+	// Sidecars are added after the containers of the pods of the component's deployments, daemonsets
+	// and statefulsets, e.g. to run a logging or metrics agent alongside the component. A sidecar
+	// must not be named like a container of the pod.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// ForceEnable deploys the enabled component even on clusters whose platform or capabilities it
+	// is known not to support, where it is otherwise skipped
+	// +optional
+	ForceEnable bool `json:"forceEnable,omitempty"`
+
+	// FSGroup is set on the pod security context of the component's pods, taking precedence over
+	// the fsGroup in the securityContext of Overrides, e.g. to match the group ownership of the
+	// volumes provided by a storage class
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967294
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// NodeSelector is set on the pods of the component, replacing the nodeSelector of the
+	// MultiClusterEngine spec for this component only
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Command replaces the entrypoint of the container named by commandContainer, e.g. with a sleep
+	// to exec into a debugging build. The args of the container are dropped, as they were meant for
+	// the replaced entrypoint. It is only accepted while the
+	// multicluster.openshift.io/allow-command-override annotation is set to 'true'.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// CommandContainer is the name of the container whose entrypoint Command replaces, in every
+	// deployment of the component that has it. Defaults to the component's primary container, the
+	// one running the component's own controller.
+	// +optional
+	CommandContainer string `json:"commandContainer,omitempty"`
+}
+
+// ProbeOverrides overrides the timings of container probes. Only the probes a container defines in
+// its template are changed.
+type ProbeOverrides struct {
+	// +optional
+	Startup *ProbeTimings `json:"startup,omitempty"`
+	// +optional
+	Liveness *ProbeTimings `json:"liveness,omitempty"`
+	// +optional
+	Readiness *ProbeTimings `json:"readiness,omitempty"`
+}
+
+// ProbeTimings are merged into a probe. Timings from the template are kept for anything left unset.
+type ProbeTimings struct {
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
 }
 
 // Overrides provides developer overrides for MCE installation
 type Overrides struct {
-	// Pull policy for the MCE images
+	// Pull policy for the MCE images. Never requires the images to be preloaded on every node.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 
 	// Provides optional configuration for components
@@ -86,6 +223,221 @@ type Overrides struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Custom Infrastructure Operator Namespace",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
 	// +optional
 	InfrastructureCustomNamespace string `json:"infrastructureCustomNamespace,omitempty"`
+
+	// Name of a ConfigMap holding additional CA certificates trusted by the assisted installer, e.g.
+	// for a mirror registry signed by a private CA. It must be in the namespace the assisted
+	// installer is deployed to. Unlike the trusted CA bundle, it only applies to the assisted installer.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Infrastructure CA ConfigMap",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	InfrastructureCAConfigMap string `json:"infrastructureCAConfigMap,omitempty"`
+
+	// Name of a ConfigMap holding a registries.conf, under the registries.conf key, that is mounted
+	// into the assisted installer so its internal image pulls honor the configured mirrors. It must
+	// be in the namespace the assisted installer is deployed to.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Registries Conf ConfigMap",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	RegistriesConfConfigMap string `json:"registriesConfConfigMap,omitempty"`
+
+	// Name of a ConfigMap in the target namespace listing the external endpoints the discovery
+	// operator may call. It is mounted into the discovery operator only, which restricts its
+	// outbound calls to the listed endpoints.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Discovery Egress Allowlist ConfigMap",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	DiscoveryEgressAllowlistConfigMap string `json:"discoveryEgressAllowlistConfigMap,omitempty"`
+
+	// Disables creation of the trusted CA bundle configmap and its mounting into components. Any
+	// bundle configmap previously created by the operator is removed.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable Trust Bundle",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	DisableTrustBundle bool `json:"disableTrustBundle,omitempty"`
+
+	// Deploys NetworkPolicies in the target namespace that deny ingress to components by default
+	// and only allow the traffic each component needs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Deploy Network Policies",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	DeployNetworkPolicies bool `json:"deployNetworkPolicies,omitempty"`
+
+	// Scales every Deployment managed by the operator to zero replicas while keeping all resources
+	// and configuration in place. Clearing it restores the normal replica counts.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Mode",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	MaintenanceMode bool `json:"maintenanceMode,omitempty"`
+
+	// Extra metadata added to the ServiceMonitors created for components, e.g. to have them
+	// scraped by user workload monitoring
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ServiceMonitor Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	ServiceMonitor *ServiceMonitorConfig `json:"serviceMonitor,omitempty"`
+
+	// Stops the operator from creating, recreating and removing the ServiceMonitors of components,
+	// leaving them to external tooling such as a GitOps process. The metrics endpoints are still
+	// deployed.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable ServiceMonitor Management",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	DisableServiceMonitorManagement bool `json:"disableServiceMonitorManagement,omitempty"`
+
+	// PriorityClassName is set on the pods of every component's deployments unless the component
+	// configures its own
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Priority Class Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// TopologySpreadConstraints are added to the pods of component deployments running more than
+	// one replica, alongside the pod anti-affinity components set by default. A constraint without
+	// a labelSelector spreads the pods of the deployment it is added to.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Topology Spread Constraints",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// LogLevel sets the log verbosity (--v) of the containers of components that support it, such as
+	// the foundation ocm-controller and ocm-proxyserver, unless the component configures its own.
+	// Must be between 0 and 10.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Log Level",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	LogLevel *int32 `json:"logLevel,omitempty"`
+
+	// DNSPolicy is set on the pods of every component. The policy in the component templates is
+	// kept when unset.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DNS Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNSConfig is set on the pods of every component, e.g. to add nameservers or search domains
+	// for resolving a private registry. It is merged with the DNS configuration generated from
+	// DNSPolicy.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DNS Config",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// HostAliases are added to the /etc/hosts file of the pods of every component, e.g. for a
+	// private registry hostname that only resolves through a hosts entry
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Host Aliases",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// AutomountServiceAccountToken is set on the pods of every component that does not need its
+	// service account token to reach the API server, unless the component configures its own
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Automount Service Account Token",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// SecurityContext overrides the user and group IDs set on the pods and containers of every
+	// component, e.g. to fit the UID range allocated to the target namespace
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Security Context",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	SecurityContext *SecurityContextOverride `json:"securityContext,omitempty"`
+
+	// TerminationGracePeriodSeconds is set on the pods of every component, unless the component
+	// configures its own. The grace period in the component templates is kept when unset.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Termination Grace Period Seconds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// Webhook configures how the ocm-webhook admission webhook is served
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Webhook Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+
+	// ProxyServer configures how the ocm-proxyserver Service is exposed
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Proxy Server Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	ProxyServer *ProxyServerConfig `json:"proxyServer,omitempty"`
+
+	// DiscoveryCache configures the volume the discovery-operator keeps its work cache in
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Discovery Cache Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:hidden"}
+	// +optional
+	DiscoveryCache *DiscoveryCacheConfig `json:"discoveryCache,omitempty"`
+}
+
+// ServiceMonitorConfig provides metadata to add to ServiceMonitors. Labels and annotations set by
+// the operator take precedence over those given here.
+type ServiceMonitorConfig struct {
+	// Labels to add to ServiceMonitors
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations to add to ServiceMonitors
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Namespace the ServiceMonitors are created in, for a monitoring stack running outside of
+	// openshift-monitoring. Defaults to openshift-monitoring.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// SecurityContextOverride sets or clears the user and group IDs of component pods. Values from the
+// component templates are kept for anything left unset.
+type SecurityContextOverride struct {
+	// ClearUserIDs removes runAsUser and fsGroup from the pod and container security contexts so
+	// that the values assigned by the namespace's SecurityContextConstraints are used. RunAsUser
+	// and FSGroup are applied afterwards.
+	// +optional
+	ClearUserIDs bool `json:"clearUserIDs,omitempty"`
+
+	// RunAsUser is set on the pod security context, and replaces the runAsUser of containers
+	// that set their own
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	// FSGroup is set on the pod security context, unless the component configures its own. Volumes
+	// supporting ownership management are made writable by this group.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967294
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// ReadOnlyRootFilesystem sets readOnlyRootFilesystem on every container of the components that
+	// do not opt out with writableRootFilesystem. An emptyDir is mounted at /tmp in each container
+	// that does not already mount a volume there, to give it writable scratch space.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// DisableHostNamespaces sets hostNetwork, hostPID and hostIPC to false on the pods of every
+	// component that is not exempted with allowHostNamespaces, whatever their templates set
+	// +optional
+	DisableHostNamespaces bool `json:"disableHostNamespaces,omitempty"`
+}
+
+// WebhookConfig overrides the port and serving certificate of the ocm-webhook
+type WebhookConfig struct {
+	// Port the ocm-webhook service listens on. Defaults to 443.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// TLSSecretName is the name of an externally managed secret in the target namespace holding the
+	// tls.crt and tls.key the ocm-webhook serves with, replacing the certificate generated by the
+	// service CA. The webhook configurations trust the ca.crt of the secret when it has one.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+}
+
+// ProxyServerConfig configures the ocm-proxyserver Service
+type ProxyServerConfig struct {
+	// ServiceType of the ocm-proxyserver Service. Defaults to ClusterIP.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ServiceAnnotations to add to the ocm-proxyserver Service, e.g. to configure the load
+	// balancer. Annotations set by the operator take precedence over those given here.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+}
+
+// DiscoveryCacheConfig configures the volume mounted at /tmp in the discovery-operator, where it
+// keeps its work cache. Without it the cache is lost when the pod restarts, forcing a full rescan.
+type DiscoveryCacheConfig struct {
+	// PersistentVolumeClaim is the name of a claim in the target namespace holding the cache, so
+	// that it is kept across restarts. A ReadWriteOnce claim needs the Recreate update strategy for
+	// the discovery component, as the old and new pods cannot mount it at once.
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// SizeLimit of the emptyDir holding the cache. Cannot be set with PersistentVolumeClaim.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
 }
 
 // MultiClusterEngineStatus defines the observed state of MultiClusterEngine
@@ -93,6 +445,34 @@ type MultiClusterEngineStatus struct {
 	// Latest observed overall state
 	Phase PhaseType `json:"phase,omitempty"`
 
+	// AvailableComponents summarizes how many tracked components are available, e.g. "5/7"
+	AvailableComponents string `json:"availableComponents,omitempty"`
+
+	// Progress is the rollout progress of the components as a percentage, averaged over the
+	// components reporting their own progress
+	Progress *int32 `json:"progress,omitempty"`
+
+	// TotalReplicas is the sum of the desired replicas of the managed Deployments
+	TotalReplicas int32 `json:"totalReplicas,omitempty"`
+
+	// ReadyReplicas is the sum of the ready replicas of the managed Deployments
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// ComponentImages maps each managed Deployment to the image of its primary container, as
+	// resolved after image overrides are applied
+	ComponentImages map[string]string `json:"componentImages,omitempty"`
+
+	// DeployedImages maps each image key to the image the components were last deployed with.
+	// Outside the upgrade window, image changes are held back to these images.
+	DeployedImages map[string]string `json:"deployedImages,omitempty"`
+
+	// DeprecatedComponents lists the component overrides naming a component that has been renamed
+	// or removed. These entries have no effect and can be removed.
+	DeprecatedComponents []string `json:"deprecatedComponents,omitempty"`
+
+	// AppliedOverrides summarizes the image overrides in effect for the current state
+	AppliedOverrides *AppliedOverrides `json:"appliedOverrides,omitempty"`
+
 	Components []ComponentCondition `json:"components,omitempty"`
 
 	Conditions []MultiClusterEngineCondition `json:"conditions,omitempty"`
@@ -102,6 +482,48 @@ type MultiClusterEngineStatus struct {
 
 	// DesiredVersion is the version the operator is reconciling towards
 	DesiredVersion string `json:"desiredVersion,omitempty"`
+
+	// Platform is the infrastructure platform type of the hub cluster, e.g. AWS or BareMetal, as
+	// reported by its Infrastructure config. Empty on clusters without one.
+	Platform string `json:"platform,omitempty"`
+
+	// OCPVersion is the OpenShift version of the hub cluster, as last read from its ClusterVersion
+	OCPVersion string `json:"ocpVersion,omitempty"`
+
+	// LastReconcileTime is when the operator last completed a reconcile without error
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// DesiredStateHash identifies the spec, annotations and resolved images last fully applied.
+	// While it is unchanged and the components are available, resources are only re-applied when
+	// they no longer match their desired state.
+	DesiredStateHash string `json:"desiredStateHash,omitempty"`
+
+	// EnabledComponents lists the components enabled as of the last reconcile. Components enabled
+	// or disabled since are reported with an event. It is kept when empty, so that a list that was
+	// never recorded can be told apart from one where every component is disabled.
+	// +optional
+	EnabledComponents []string `json:"enabledComponents"`
+
+	// InstalledCRDs lists the cluster-scoped CustomResourceDefinitions installed by the operator for
+	// this multiclusterengine. CRDs removed along with a disabled component are dropped from it.
+	InstalledCRDs []string `json:"installedCRDs,omitempty"`
+}
+
+// AppliedOverrides summarizes the overrides changing the images the components are deployed with
+type AppliedOverrides struct {
+	// ImageRepository is the repository the images are pulled from, as set by the imageRepository
+	// annotation
+	ImageRepository string `json:"imageRepository,omitempty"`
+
+	// ImagePullPolicy is the pull policy set in the overrides
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImageOverridesConfigMap is the configmap overriding individual images, as set by the
+	// imageOverridesCM annotation
+	ImageOverridesConfigMap string `json:"imageOverridesConfigMap,omitempty"`
+
+	// ComponentImages is the number of components pinned to an image in the overrides
+	ComponentImages int32 `json:"componentImages,omitempty"`
 }
 
 // ComponentCondition contains condition information for tracked components
@@ -115,6 +537,11 @@ type ComponentCondition struct {
 	// Available indicates whether this component is considered properly running
 	Available bool `json:"-"`
 
+	// DesiredReplicas and ReadyReplicas are the replica counts of a Deployment, summed up in the
+	// status of the multiclusterengine
+	DesiredReplicas int32 `json:"-"`
+	ReadyReplicas   int32 `json:"-"`
+
 	// Type is the type of the cluster condition.
 	// +required
 	Type string `json:"type,omitempty"`
@@ -136,15 +563,34 @@ type ComponentCondition struct {
 	// Message is a human-readable message indicating details about the last status change.
 	// +required
 	Message string `json:"message,omitempty"`
+
+	// Progress is the percentage of a Deployment's desired replicas running its latest pod template
+	// +optional
+	Progress *int32 `json:"progress,omitempty"`
+
+	// UpToDate is whether a Deployment has observed the latest generation of its spec, i.e. whether
+	// the applied configuration has propagated to its controller
+	// +optional
+	UpToDate *bool `json:"upToDate,omitempty"`
 }
 
 // PhaseType is a summary of the current state of the MultiClusterEngine in its lifecycle
 type PhaseType string
 
 const (
-	MultiClusterEnginePhaseProgressing   PhaseType = "Progressing"
-	MultiClusterEnginePhaseAvailable     PhaseType = "Available"
-	MultiClusterEnginePhaseUninstalling  PhaseType = "Uninstalling"
+	// Installing means components are being deployed and the MultiClusterEngine has not yet been available
+	MultiClusterEnginePhaseInstalling PhaseType = "Installing"
+	// Failed means the install did not complete within the install timeout. Installing is still
+	// retried, and the phase becomes Available once every component is.
+	MultiClusterEnginePhaseFailed PhaseType = "Failed"
+	// Progressing means components are rolling out a new version
+	MultiClusterEnginePhaseProgressing PhaseType = "Progressing"
+	// Degraded means the current version was installed but components are no longer all available
+	MultiClusterEnginePhaseDegraded     PhaseType = "Degraded"
+	MultiClusterEnginePhaseAvailable    PhaseType = "Available"
+	MultiClusterEnginePhaseUninstalling PhaseType = "Uninstalling"
+	// Maintenance means components have been scaled to zero by maintenance mode
+	MultiClusterEnginePhaseMaintenance   PhaseType = "Maintenance"
 	MultiClusterEnginePhaseError         PhaseType = "Error"
 	MultiClusterEnginePhaseUnimplemented PhaseType = "Unimplemented"
 )
@@ -161,9 +607,50 @@ const (
 	// up or old pods scale down. Progress is not estimated for paused deployments or
 	// when progressDeadlineSeconds is not specified.
 	MultiClusterEngineProgressing MultiClusterEngineConditionType = "Progressing"
+	// Degraded means the multiclusterengine is in the Error or Degraded phase. Together with
+	// Available and Progressing it follows the conventions of the ClusterOperator conditions.
+	MultiClusterEngineDegraded MultiClusterEngineConditionType = "Degraded"
 	// Failure is added in a deployment when one of its pods fails to be created
 	// or deleted.
 	MultiClusterEngineFailure MultiClusterEngineConditionType = "MultiClusterEngineFailure"
+	// MonitoringUnavailable is added when the Prometheus operator APIs are not installed on the
+	// cluster and monitoring resources such as ServiceMonitors are being skipped.
+	MultiClusterEngineMonitoringUnavailable MultiClusterEngineConditionType = "MonitoringUnavailable"
+	// DriftCorrected is added when a managed resource was modified outside the operator and
+	// restored to its desired state. It is removed after an hour without further corrections.
+	MultiClusterEngineDriftCorrected MultiClusterEngineConditionType = "DriftCorrected"
+	// CRDsEstablished reports whether the CRDs the enabled components depend on are established.
+	// Components wait for their CRDs to be established before they are applied.
+	MultiClusterEngineCRDsEstablished MultiClusterEngineConditionType = "CRDsEstablished"
+	// WaitingForUpgradeWindow is added while image upgrades of the components are deferred until
+	// the upgrade window set on the multiclusterengine opens
+	MultiClusterEngineWaitingForUpgradeWindow MultiClusterEngineConditionType = "WaitingForUpgradeWindow"
+	// InsufficientPermissions reports the resources the operator was forbidden from applying, so
+	// that missing RBAC permissions can be granted
+	MultiClusterEngineInsufficientPermissions MultiClusterEngineConditionType = "InsufficientPermissions"
+	// PriorityClassUnavailable is added when a priority class set in the overrides does not exist
+	MultiClusterEnginePriorityClassUnavailable MultiClusterEngineConditionType = "PriorityClassUnavailable"
+	// ImageOverrideConfigMapMissing is added when the configmap named by the imageOverridesCM annotation
+	// does not exist. The last image overrides read from it stay in use until it is restored.
+	MultiClusterEngineImageOverrideConfigMapMissing MultiClusterEngineConditionType = "ImageOverrideConfigMapMissing"
+	// InvalidComponentConfig is added when the component overrides name a component that does not
+	// exist. Configuration for unknown components has no effect.
+	MultiClusterEngineInvalidComponentConfig MultiClusterEngineConditionType = "InvalidComponentConfig"
+	// Conflicting is added when another multiclusterengine already targets the same namespace. The
+	// multiclusterengine created later is not reconciled until the conflict is resolved.
+	MultiClusterEngineConflicting MultiClusterEngineConditionType = "Conflicting"
+	// ClusterVersionUnavailable is added when the OpenShift ClusterVersion cannot be read. The last
+	// detected version stays in use until it can be read again.
+	MultiClusterEngineClusterVersionUnavailable MultiClusterEngineConditionType = "ClusterVersionUnavailable"
+	// DowngradeBlocked is added when the operator version is lower than the version the components
+	// were last deployed with. Nothing is applied unless the downgrade is explicitly allowed.
+	MultiClusterEngineDowngradeBlocked MultiClusterEngineConditionType = "DowngradeBlocked"
+	// ComponentCrashLooping is true while a container of a component is in CrashLoopBackOff. Its
+	// message names the components and containers crash looping.
+	MultiClusterEngineComponentCrashLooping MultiClusterEngineConditionType = "ComponentCrashLooping"
+	// UnsupportedOnPlatform is true while enabled components are skipped because the platform or
+	// capabilities of the cluster cannot support them. Its message names the skipped components.
+	MultiClusterEngineUnsupportedOnPlatform MultiClusterEngineConditionType = "UnsupportedOnPlatform"
 )
 
 type MultiClusterEngineCondition struct {
@@ -193,9 +680,11 @@ type MultiClusterEngineCondition struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster,shortName=mce
+//+kubebuilder:storageversion
 
 // MultiClusterEngine is the Schema for the multiclusterengines API
-// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="The overall state of the MultiClusterEngine"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The overall state of the MultiClusterEngine"
+// +kubebuilder:printcolumn:name="Available Components",type="string",JSONPath=".status.availableComponents",description="Number of available components out of those tracked"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +operator-sdk:csv:customresourcedefinitions:displayName="MultiCluster Engine"
 type MultiClusterEngine struct {
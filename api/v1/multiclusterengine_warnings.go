@@ -0,0 +1,62 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// deprecationWarningPath is the path the deprecation warning webhook is served at
+const deprecationWarningPath = "/warn-multicluster-openshift-io-v1-multiclusterengine"
+
+// deprecatedAnnotations maps the legacy multiclusterengine annotations to the spec field replacing
+// them, or to an empty string while the replacement does not exist yet
+var deprecatedAnnotations = map[string]string{
+	"imageRepository":  "",
+	"imageOverridesCM": "",
+}
+
+// deprecatedAnnotationWarnings returns a warning for each deprecated annotation set on the
+// multiclusterengine, sorted by annotation
+func deprecatedAnnotationWarnings(annotations map[string]string) []string {
+	warnings := []string{}
+	for annotation, replacement := range deprecatedAnnotations {
+		if _, ok := annotations[annotation]; !ok {
+			continue
+		}
+		warning := fmt.Sprintf("annotation %s is deprecated and will be removed in a future release", annotation)
+		if replacement != "" {
+			warning = fmt.Sprintf("%s; use %s instead", warning, replacement)
+		}
+		warnings = append(warnings, warning)
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// deprecationWarner admits every multiclusterengine, adding an admission warning for each deprecated
+// annotation it carries. It never denies a request.
+type deprecationWarner struct{}
+
+var _ admission.Handler = &deprecationWarner{}
+
+// Handle implements admission.Handler
+func (w *deprecationWarner) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		backplaneconfiglog.Error(err, "failed to decode multiclusterengine for deprecation warnings")
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	return admission.Allowed("").WithWarnings(deprecatedAnnotationWarnings(obj.GetAnnotations())...)
+}
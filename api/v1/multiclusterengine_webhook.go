@@ -21,7 +21,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -47,6 +50,20 @@ var (
 	ErrInvalidDeployMode   = errors.New("invalid DeploymentMode")
 	ErrInvalidAvailability = errors.New("invalid AvailabilityConfig")
 	ErrInvalidInfraNS      = errors.New("invalid InfrastructureCustomNamespace")
+	ErrInvalidLogLevel     = errors.New("invalid LogLevel")
+	ErrInvalidWebhook      = errors.New("invalid Webhook configuration")
+	ErrInvalidPullPolicy   = errors.New("invalid ImagePullPolicy")
+	ErrInvalidGracePeriod  = errors.New("invalid TerminationGracePeriodSeconds")
+	ErrInvalidImage        = errors.New("invalid Image")
+	ErrInvalidServiceType  = errors.New("invalid ServiceType")
+	ErrInvalidStrategy     = errors.New("invalid UpdateStrategy")
+	ErrInvalidSidecar      = errors.New("invalid Sidecars")
+	ErrInvalidCache        = errors.New("invalid DiscoveryCache")
+	ErrInvalidFSGroup      = errors.New("invalid FSGroup")
+	ErrInvalidCommand      = errors.New("invalid Command")
+	ErrInvalidArgs         = errors.New("invalid Args")
+
+	ErrConflictingComponents = errors.New("conflicting components")
 
 	blockDeletionResources = []struct {
 		Name       string
@@ -83,6 +100,7 @@ var (
 
 func (r *MultiClusterEngine) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	Client = mgr.GetClient()
+	mgr.GetWebhookServer().Register(deprecationWarningPath, &webhook.Admission{Handler: &deprecationWarner{}})
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -119,6 +137,62 @@ func (r *MultiClusterEngine) ValidateCreate() error {
 		}
 	}
 
+	if err := validateLogLevels(r); err != nil {
+		return err
+	}
+
+	if err := validateWebhookConfig(r); err != nil {
+		return err
+	}
+
+	if err := validateImagePullPolicy(r); err != nil {
+		return err
+	}
+
+	if err := validateTerminationGracePeriods(r); err != nil {
+		return err
+	}
+
+	if err := validateComponentImages(r); err != nil {
+		return err
+	}
+
+	if err := validateUpdateStrategies(r); err != nil {
+		return err
+	}
+
+	if err := validateSidecars(r); err != nil {
+		return err
+	}
+
+	if err := validateProxyServer(r); err != nil {
+		return err
+	}
+
+	if err := validateDiscoveryCache(r); err != nil {
+		return err
+	}
+
+	if err := validateFSGroups(r); err != nil {
+		return err
+	}
+
+	if err := validateArgs(r); err != nil {
+		return err
+	}
+
+	if err := validateCommands(r); err != nil {
+		return err
+	}
+
+	if err := validateComponentConflicts(r); err != nil {
+		return err
+	}
+
+	if r.ProtectedTargetNamespace() {
+		return fmt.Errorf("%w: '%s' is a protected namespace. Set the %s annotation to 'true' to use it anyway", ErrInvalidNamespace, r.Spec.TargetNamespace, AnnotationAllowProtectedNamespace)
+	}
+
 	mceList := &MultiClusterEngineList{}
 	if err := Client.List(ctx, mceList); err != nil {
 		return fmt.Errorf("unable to list BackplaneConfigs: %s", err)
@@ -179,6 +253,58 @@ func (r *MultiClusterEngine) ValidateUpdate(old runtime.Object) error {
 		}
 	}
 
+	if err := validateLogLevels(r); err != nil {
+		return err
+	}
+
+	if err := validateWebhookConfig(r); err != nil {
+		return err
+	}
+
+	if err := validateImagePullPolicy(r); err != nil {
+		return err
+	}
+
+	if err := validateTerminationGracePeriods(r); err != nil {
+		return err
+	}
+
+	if err := validateComponentImages(r); err != nil {
+		return err
+	}
+
+	if err := validateUpdateStrategies(r); err != nil {
+		return err
+	}
+
+	if err := validateSidecars(r); err != nil {
+		return err
+	}
+
+	if err := validateProxyServer(r); err != nil {
+		return err
+	}
+
+	if err := validateDiscoveryCache(r); err != nil {
+		return err
+	}
+
+	if err := validateFSGroups(r); err != nil {
+		return err
+	}
+
+	if err := validateArgs(r); err != nil {
+		return err
+	}
+
+	if err := validateCommands(r); err != nil {
+		return err
+	}
+
+	if err := validateComponentConflicts(r); err != nil {
+		return err
+	}
+
 	// Block disable if relevant resources present
 	if r.ComponentPresent(Discovery) && !r.Enabled(Discovery) {
 		cfg, err := config.GetConfig()
@@ -247,6 +373,209 @@ func (r *MultiClusterEngine) ValidateDelete() error {
 	return nil
 }
 
+// validateLogLevels returns an error if a log level in the overrides is out of range
+func validateLogLevels(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	if l := r.Spec.Overrides.LogLevel; l != nil && !ValidLogLevel(*l) {
+		return fmt.Errorf("%w: %d must be between %d and %d", ErrInvalidLogLevel, *l, MinLogLevel, MaxLogLevel)
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		if c.LogLevel != nil && !ValidLogLevel(*c.LogLevel) {
+			return fmt.Errorf("%w: %d for %s must be between %d and %d", ErrInvalidLogLevel, *c.LogLevel, c.Name, MinLogLevel, MaxLogLevel)
+		}
+	}
+	return nil
+}
+
+// validateFSGroups returns an error if an fsGroup in the overrides is not a valid group ID
+func validateFSGroups(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	if sc := r.Spec.Overrides.SecurityContext; sc != nil && sc.FSGroup != nil && !ValidFSGroup(*sc.FSGroup) {
+		return fmt.Errorf("%w: %d must be between 0 and %d", ErrInvalidFSGroup, *sc.FSGroup, MaxFSGroup)
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		if c.FSGroup != nil && !ValidFSGroup(*c.FSGroup) {
+			return fmt.Errorf("%w: %d for %s must be between 0 and %d", ErrInvalidFSGroup, *c.FSGroup, c.Name, MaxFSGroup)
+		}
+	}
+	return nil
+}
+
+// validateArgs returns an error if a component arg is not a flag. Flags must be given in the
+// --flag=value form, so that they replace the same flag in the args of the container.
+func validateArgs(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		for _, a := range c.Args {
+			if !strings.HasPrefix(a, "-") {
+				return fmt.Errorf("%w: %q of %s is not a flag. Set flag values in the --flag=value form", ErrInvalidArgs, a, c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCommands returns an error if a component overrides the entrypoint of a container without
+// the allow-command-override annotation, or with an empty command
+func validateCommands(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		if len(c.Command) == 0 {
+			if c.CommandContainer != "" {
+				return fmt.Errorf("%w: commandContainer of %s is set without a command", ErrInvalidCommand, c.Name)
+			}
+			continue
+		}
+		if !r.CommandOverrideAllowed() {
+			return fmt.Errorf("%w: overriding the command of %s is a debugging feature. Set the %s annotation to 'true' to use it",
+				ErrInvalidCommand, c.Name, AnnotationAllowCommandOverride)
+		}
+		if c.Command[0] == "" {
+			return fmt.Errorf("%w: the command of %s must start with an executable", ErrInvalidCommand, c.Name)
+		}
+	}
+	return nil
+}
+
+// validateWebhookConfig returns an error if the webhook port in the overrides is not a valid port
+func validateWebhookConfig(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil || r.Spec.Overrides.Webhook == nil {
+		return nil
+	}
+	if p := r.Spec.Overrides.Webhook.Port; p < 0 || p > 65535 {
+		return fmt.Errorf("%w: port %d must be between 1 and 65535", ErrInvalidWebhook, p)
+	}
+	return nil
+}
+
+// validateImagePullPolicy returns an error if the image pull policy in the overrides is not one
+// of the policies known to kubelet
+func validateImagePullPolicy(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	switch p := r.Spec.Overrides.ImagePullPolicy; p {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s must be one of %s, %s or %s", ErrInvalidPullPolicy, p, corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever)
+	}
+}
+
+// validateTerminationGracePeriods returns an error if a termination grace period in the overrides
+// is negative
+func validateTerminationGracePeriods(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	if p := r.Spec.Overrides.TerminationGracePeriodSeconds; p != nil && *p < 0 {
+		return fmt.Errorf("%w: %d must not be negative", ErrInvalidGracePeriod, *p)
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		if p := c.TerminationGracePeriodSeconds; p != nil && *p < 0 {
+			return fmt.Errorf("%w: %d for %s must not be negative", ErrInvalidGracePeriod, *p, c.Name)
+		}
+	}
+	return nil
+}
+
+// validateComponentImages returns an error if an image pinned for a component is not a valid image
+// reference
+func validateComponentImages(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		if c.Image != "" && !ValidImageReference(c.Image) {
+			return fmt.Errorf("%w: %s for %s is not a valid image reference", ErrInvalidImage, c.Image, c.Name)
+		}
+	}
+	return nil
+}
+
+// validateUpdateStrategies returns an error if a component's update strategy is not RollingUpdate or
+// Recreate, or sets rollingUpdate parameters for a Recreate deployment
+func validateUpdateStrategies(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		if c.UpdateStrategy == nil {
+			continue
+		}
+		switch c.UpdateStrategy.Type {
+		case "", appsv1.RollingUpdateDeploymentStrategyType:
+		case appsv1.RecreateDeploymentStrategyType:
+			if c.UpdateStrategy.RollingUpdate != nil {
+				return fmt.Errorf("%w: rollingUpdate may not be set for %s when the type is %s", ErrInvalidStrategy, c.Name, appsv1.RecreateDeploymentStrategyType)
+			}
+		default:
+			return fmt.Errorf("%w: %s for %s must be %s or %s", ErrInvalidStrategy, c.UpdateStrategy.Type, c.Name, appsv1.RollingUpdateDeploymentStrategyType, appsv1.RecreateDeploymentStrategyType)
+		}
+	}
+	return nil
+}
+
+// validateSidecars returns an error if a component's sidecar has no name or image, or shares its
+// name with another sidecar of the component. Collisions with the component's own containers are
+// reported when the component is rendered.
+func validateSidecars(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil {
+		return nil
+	}
+	for _, c := range r.Spec.Overrides.Components {
+		names := map[string]bool{}
+		for _, s := range c.Sidecars {
+			if s.Name == "" || s.Image == "" {
+				return fmt.Errorf("%w: sidecars for %s must set a name and an image", ErrInvalidSidecar, c.Name)
+			}
+			if names[s.Name] {
+				return fmt.Errorf("%w: sidecar %s for %s is defined more than once", ErrInvalidSidecar, s.Name, c.Name)
+			}
+			names[s.Name] = true
+		}
+	}
+	return nil
+}
+
+// validateProxyServer returns an error if the ocm-proxyserver Service type in the overrides is not
+// one the Service can be exposed with
+func validateProxyServer(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil || r.Spec.Overrides.ProxyServer == nil {
+		return nil
+	}
+	switch t := r.Spec.Overrides.ProxyServer.ServiceType; t {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s must be one of %s, %s or %s", ErrInvalidServiceType, t, corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer)
+	}
+}
+
+// validateDiscoveryCache returns an error if the discovery cache sets both a claim and a size limit,
+// or a size limit that is not positive
+func validateDiscoveryCache(r *MultiClusterEngine) error {
+	if r.Spec.Overrides == nil || r.Spec.Overrides.DiscoveryCache == nil {
+		return nil
+	}
+	cache := r.Spec.Overrides.DiscoveryCache
+	if cache.PersistentVolumeClaim != "" && cache.SizeLimit != nil {
+		return fmt.Errorf("%w: persistentVolumeClaim and sizeLimit cannot both be set", ErrInvalidCache)
+	}
+	if cache.SizeLimit != nil && cache.SizeLimit.Sign() <= 0 {
+		return fmt.Errorf("%w: sizeLimit %s must be positive", ErrInvalidCache, cache.SizeLimit.String())
+	}
+	return nil
+}
+
 func contains(s []string, v string) bool {
 	for _, vs := range s {
 		if vs == v {
@@ -7,8 +7,13 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
@@ -101,6 +106,220 @@ var _ = Describe("Multiclusterengine webhook", func() {
 				}
 				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Invalid components not allowed in config")
 			})
+			By("because of an out of range log level", func() {
+				logLevel := int32(11)
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides:       &Overrides{LogLevel: &logLevel},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Log levels above the maximum are not allowed")
+			})
+			By("because of an unknown image pull policy", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides:       &Overrides{ImagePullPolicy: "Sometimes"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Only the image pull policies known to kubelet are allowed")
+			})
+			By("because of a negative termination grace period", func() {
+				gracePeriod := int64(-1)
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{
+								{Name: ClusterManager, Enabled: true, TerminationGracePeriodSeconds: &gracePeriod},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Negative termination grace periods are not allowed")
+			})
+			By("because of an invalid component image", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{
+								{Name: ClusterManager, Enabled: true, Image: "quay.io/Invalid Image@sha256:abc"},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Component images must be valid image references")
+			})
+			By("because of a Recreate update strategy with rollingUpdate parameters", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{
+								{Name: ClusterManager, Enabled: true, UpdateStrategy: &appsv1.DeploymentStrategy{
+									Type:          appsv1.RecreateDeploymentStrategyType,
+									RollingUpdate: &appsv1.RollingUpdateDeployment{},
+								}},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Recreate deployments cannot have rollingUpdate parameters")
+			})
+			By("because of duplicate sidecar names", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{
+								{Name: ClusterManager, Enabled: true, Sidecars: []corev1.Container{
+									{Name: "log-agent", Image: "quay.io/org/log-agent:1.0"},
+									{Name: "log-agent", Image: "quay.io/org/log-agent:1.1"},
+								}},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Sidecar names must be unique within a component")
+			})
+			By("because of a discovery cache with both a claim and a size limit", func() {
+				sizeLimit := resource.MustParse("5Gi")
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							DiscoveryCache: &DiscoveryCacheConfig{PersistentVolumeClaim: "discovery-cache", SizeLimit: &sizeLimit},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "The discovery cache is either a claim or an emptyDir")
+			})
+			By("because of an invalid fsGroup", func() {
+				gid := int64(-1)
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{{Name: Discovery, Enabled: true, FSGroup: &gid}},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "The fsGroup must be a valid group ID")
+			})
+			By("because of a command override without the allow-command-override annotation", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{{Name: Discovery, Enabled: true, Command: []string{"sleep", "infinity"}}},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Command overrides must be explicitly allowed")
+			})
+			By("because of an arg that is not a flag", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{{Name: Hive, Enabled: true, Args: []string{"--log-level", "debug"}}},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Flag values must use the --flag=value form")
+			})
+			By("because of conflicting components", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							Components: []ComponentConfig{{Name: Discovery, Enabled: true}, {Name: Hive, Enabled: true}},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce, client.DryRunAll)).To(Succeed(), "Components that do not conflict are accepted")
+
+				mce.Spec.Overrides.Components = []ComponentConfig{
+					{Name: ImageBasedInstallOperatorPreview, Enabled: true},
+					{Name: ImageBasedInstallOperator, Enabled: true},
+				}
+				err := k8sClient.Create(ctx, mce)
+				Expect(err).NotTo(BeNil(), "Conflicting components cannot be enabled together")
+				Expect(err.Error()).To(ContainSubstring(ImageBasedInstallOperatorPreview + " and " + ImageBasedInstallOperator))
+			})
+			By("because of an invalid proxy server service type", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "new",
+						Overrides: &Overrides{
+							ProxyServer: &ProxyServerConfig{ServiceType: "ExternalName"},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Only ClusterIP, NodePort and LoadBalancer services are allowed")
+			})
+			By("because of a protected TargetNamespace", func() {
+				mce := &MultiClusterEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        fmt.Sprintf("%s-2", multiClusterEngineName),
+						Annotations: map[string]string{"deploymentmode": string(ModeHosted)},
+					},
+					Spec: MultiClusterEngineSpec{
+						TargetNamespace: "kube-system",
+					},
+				}
+				Expect(k8sClient.Create(ctx, mce)).NotTo(BeNil(), "Protected namespaces are not allowed")
+			})
 		})
 
 		It("Should fail to update multiclusterengine", func() {
@@ -128,8 +347,49 @@ var _ = Describe("Multiclusterengine webhook", func() {
 				}
 				Expect(k8sClient.Update(ctx, mce)).NotTo(BeNil(), "invalid components should not be permitted")
 			})
+			By("because of conflicting components", func() {
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: multiClusterEngineName}, mce)).To(Succeed())
+				mce.Spec.Overrides = &Overrides{
+					Components: []ComponentConfig{
+						{Name: ImageBasedInstallOperatorPreview, Enabled: true},
+						{Name: ImageBasedInstallOperator, Enabled: true},
+					},
+				}
+				Expect(k8sClient.Update(ctx, mce)).NotTo(BeNil(), "conflicting components should not be permitted")
+			})
 		})
 
+		It("Should warn about deprecated annotations without blocking the request", func() {
+			recorder := &warningRecorder{}
+			warnCfg := rest.CopyConfig(cfg)
+			warnCfg.WarningHandler = recorder
+			warnClient, err := client.New(warnCfg, client.Options{Scheme: k8sClient.Scheme()})
+			Expect(err).NotTo(HaveOccurred())
+
+			mce := &MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "legacy-annotations",
+					Annotations: map[string]string{
+						"deploymentmode":  string(ModeHosted),
+						"imageRepository": "quay.io/mirror",
+					},
+				},
+				Spec: MultiClusterEngineSpec{
+					TargetNamespace: "legacy",
+				},
+			}
+			Expect(warnClient.Create(ctx, mce)).To(Succeed())
+			Expect(recorder.warnings).To(ContainElement(ContainSubstring("annotation imageRepository is deprecated")))
+		})
 	})
 
 })
+
+// warningRecorder collects the warnings returned by the API server
+type warningRecorder struct {
+	warnings []string
+}
+
+func (w *warningRecorder) HandleWarningHeader(_ int, _ string, text string) {
+	w.warnings = append(w.warnings, text)
+}
@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1beta1
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &MultiClusterEngine{}
+
+// ConvertTo converts this MultiClusterEngine to the hub version (v1)
+func (src *MultiClusterEngine) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1.MultiClusterEngine)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", dstRaw)
+	}
+
+	// The converted object must not share maps, slices or pointers with the source
+	src = src.DeepCopy()
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.AvailabilityConfig = src.Spec.AvailabilityConfig
+	dst.Spec.NodeSelector = src.Spec.NodeSelector
+	dst.Spec.ImagePullSecret = src.Spec.ImagePullSecret
+	dst.Spec.Overrides = src.Spec.Overrides
+	dst.Spec.Tolerations = src.Spec.Tolerations
+	dst.Spec.TargetNamespace = src.Spec.TargetNamespace
+
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1) to this version
+func (dst *MultiClusterEngine) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1.MultiClusterEngine)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", srcRaw)
+	}
+
+	// The converted object must not share maps, slices or pointers with the source
+	src = src.DeepCopy()
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.AvailabilityConfig = src.Spec.AvailabilityConfig
+	dst.Spec.NodeSelector = src.Spec.NodeSelector
+	dst.Spec.ImagePullSecret = src.Spec.ImagePullSecret
+	dst.Spec.Overrides = src.Spec.Overrides
+	dst.Spec.Tolerations = src.Spec.Tolerations
+	dst.Spec.TargetNamespace = src.Spec.TargetNamespace
+
+	dst.Status = src.Status
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testHub() *v1.MultiClusterEngine {
+	return &v1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine", Labels: map[string]string{"a": "b"}},
+		Spec: v1.MultiClusterEngineSpec{
+			AvailabilityConfig: v1.HABasic,
+			NodeSelector:       map[string]string{"node-role.kubernetes.io/infra": ""},
+			ImagePullSecret:    "pull-secret",
+			Overrides: &v1.Overrides{
+				ImagePullPolicy: corev1.PullAlways,
+				Components: []v1.ComponentConfig{
+					{Name: v1.ClusterManager, Enabled: true, Args: []string{"--feature-gates=Foo=true"}},
+					{Name: v1.Discovery, Enabled: false},
+				},
+				InfrastructureCustomNamespace: "assisted",
+			},
+			Tolerations:     []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+			TargetNamespace: "multicluster-engine",
+		},
+		Status: v1.MultiClusterEngineStatus{Phase: v1.MultiClusterEnginePhaseAvailable},
+	}
+}
+
+func TestConvertHubRoundTrip(t *testing.T) {
+	hub := testHub()
+
+	spoke := &MultiClusterEngine{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if spoke.Spec.TargetNamespace != hub.Spec.TargetNamespace {
+		t.Errorf("ConvertFrom() targetNamespace = %q, want %q", spoke.Spec.TargetNamespace, hub.Spec.TargetNamespace)
+	}
+	if spoke.Spec.ImagePullSecret != hub.Spec.ImagePullSecret {
+		t.Errorf("ConvertFrom() imagePullSecret = %q, want %q", spoke.Spec.ImagePullSecret, hub.Spec.ImagePullSecret)
+	}
+	if !reflect.DeepEqual(spoke.Spec.Overrides, hub.Spec.Overrides) {
+		t.Errorf("ConvertFrom() overrides = %+v, want %+v", spoke.Spec.Overrides, hub.Spec.Overrides)
+	}
+
+	got := &v1.MultiClusterEngine{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, hub) {
+		t.Errorf("round trip through v1beta1 = %+v, want %+v", got, hub)
+	}
+}
+
+func TestConvertSpokeRoundTrip(t *testing.T) {
+	spoke := &MultiClusterEngine{}
+	if err := spoke.ConvertFrom(testHub()); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+
+	hub := &v1.MultiClusterEngine{}
+	if err := spoke.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	got := &MultiClusterEngine{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, spoke) {
+		t.Errorf("round trip through v1 = %+v, want %+v", got, spoke)
+	}
+}
+
+func TestConvertDoesNotShareFields(t *testing.T) {
+	hub := testHub()
+	spoke := &MultiClusterEngine{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	spoke.Labels["a"] = "changed"
+	spoke.Spec.NodeSelector["node-role.kubernetes.io/infra"] = "changed"
+	spoke.Spec.Overrides.Components[0].Args[0] = "--changed"
+	spoke.Spec.Tolerations[0].Key = "changed"
+	if !reflect.DeepEqual(hub, testHub()) {
+		t.Errorf("changing the result of ConvertFrom() changed the source: %+v", hub)
+	}
+
+	converted := &v1.MultiClusterEngine{}
+	if err := spoke.ConvertTo(converted); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	converted.Spec.Overrides.Components[0].Enabled = false
+	converted.Spec.NodeSelector["node-role.kubernetes.io/infra"] = "changed again"
+	if !spoke.Spec.Overrides.Components[0].Enabled || spoke.Spec.NodeSelector["node-role.kubernetes.io/infra"] != "changed" {
+		t.Errorf("changing the result of ConvertTo() changed the source: %+v", spoke)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MultiClusterEngineSpec defines the desired state of MultiClusterEngine. Nested types are shared
+// with v1 until this version diverges from it.
+type MultiClusterEngineSpec struct {
+	// Specifies deployment replication for improved availability. Options are: Basic and High (default)
+	AvailabilityConfig v1.AvailabilityType `json:"availabilityConfig,omitempty"`
+
+	// Set the nodeselectors
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Override pull secret for accessing MultiClusterEngine operand and endpoint images
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+
+	// Developer Overrides
+	Overrides *v1.Overrides `json:"overrides,omitempty"`
+
+	// Tolerations causes all components to tolerate any taints.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Location where MCE resources will be placed
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName=mce
+
+// MultiClusterEngine is the Schema for the multiclusterengines API. It is converted to and from the
+// v1 storage version by the conversion webhook.
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The overall state of the MultiClusterEngine"
+// +kubebuilder:printcolumn:name="Available Components",type="string",JSONPath=".status.availableComponents",description="Number of available components out of those tracked"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type MultiClusterEngine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MultiClusterEngineSpec      `json:"spec,omitempty"`
+	Status v1.MultiClusterEngineStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MultiClusterEngineList contains a list of MultiClusterEngine
+type MultiClusterEngineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MultiClusterEngine `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MultiClusterEngine{}, &MultiClusterEngineList{})
+}
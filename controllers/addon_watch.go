@@ -0,0 +1,73 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/foundation"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var clusterManagementAddOnGVK = schema.GroupVersionKind{
+	Group:   "addon.open-cluster-management.io",
+	Version: "v1alpha1",
+	Kind:    foundation.ClusterManagementAddonKind,
+}
+
+// addonDeletedPredicate only passes deletions. Addon controllers update the spec fields they own
+// on the ClusterManagementAddOns, and those updates must not trigger a reconcile that would
+// compete with them; the operator only owns the fields it applies.
+var addonDeletedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// addonWatcher starts watching ClusterManagementAddOns once their CRD exists. The CRD is installed
+// by the cluster manager, so it is usually missing when the controller starts.
+type addonWatcher struct {
+	mu         sync.Mutex
+	controller controller.Controller
+	started    bool
+}
+
+// watchClusterManagementAddOns makes the deletion of a ClusterManagementAddOn owned by a
+// multiclusterengine reconcile it right away so the addon is recreated. Only called once the
+// ClusterManagementAddOn CRD is installed.
+func (r *MultiClusterEngineReconciler) watchClusterManagementAddOns(ctx context.Context) error {
+	w := r.addonWatch
+	if w == nil || w.controller == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started {
+		return nil
+	}
+
+	addon := &unstructured.Unstructured{}
+	addon.SetGroupVersionKind(utils.PreferredGVK(r.Client.RESTMapper(), clusterManagementAddOnGVK))
+	ownerHandler := &handler.EnqueueRequestForOwner{
+		OwnerType:    &backplanev1.MultiClusterEngine{},
+		IsController: true,
+	}
+	if err := w.controller.Watch(&source.Kind{Type: addon}, ownerHandler, addonDeletedPredicate); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Watching ClusterManagementAddOns for deletion")
+	w.started = true
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// watchRecorder is a controller that records the watches added to it
+type watchRecorder struct {
+	controller.Controller
+	watches []source.Source
+}
+
+func (w *watchRecorder) Watch(src source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error {
+	w.watches = append(w.watches, src)
+	return nil
+}
+
+func Test_addonDeletedPredicate(t *testing.T) {
+	addon := &unstructured.Unstructured{}
+	addon.SetGroupVersionKind(clusterManagementAddOnGVK)
+	addon.SetName("work-manager")
+
+	if !addonDeletedPredicate.Delete(event.DeleteEvent{Object: addon}) {
+		t.Error("deleting a ClusterManagementAddOn should trigger a reconcile")
+	}
+	if addonDeletedPredicate.Update(event.UpdateEvent{ObjectOld: addon, ObjectNew: addon}) {
+		t.Error("updating a ClusterManagementAddOn should not trigger a reconcile")
+	}
+	if addonDeletedPredicate.Create(event.CreateEvent{Object: addon}) {
+		t.Error("creating a ClusterManagementAddOn should not trigger a reconcile")
+	}
+}
+
+func Test_recreateDeletedClusterManagementAddOn(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	s.AddKnownTypeWithName(clusterManagementAddOnGVK, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(clusterManagementAddOnGVK.GroupVersion().WithKind(clusterManagementAddOnGVK.Kind+"List"), &unstructured.UnstructuredList{})
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+		&apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "clustermanagementaddons.addon.open-cluster-management.io"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	recorder := &watchRecorder{}
+	r.addonWatch = &addonWatcher{controller: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	if len(recorder.watches) != 1 {
+		t.Fatalf("ClusterManagementAddOn watches = %d, want 1 across reconciles", len(recorder.watches))
+	}
+
+	getAddon := func() *unstructured.Unstructured {
+		t.Helper()
+		addons := &unstructured.UnstructuredList{}
+		addons.SetGroupVersionKind(clusterManagementAddOnGVK.GroupVersion().WithKind(clusterManagementAddOnGVK.Kind + "List"))
+		if err := c.List(ctx, addons); err != nil {
+			t.Fatalf("failed to list ClusterManagementAddOns: %v", err)
+		}
+		for i := range addons.Items {
+			if addons.Items[i].GetName() == "work-manager" {
+				return &addons.Items[i]
+			}
+		}
+		return nil
+	}
+	addon := getAddon()
+	if addon == nil {
+		t.Fatal("work-manager ClusterManagementAddOn not created")
+	}
+	if owner := metav1.GetControllerOf(addon); owner == nil || owner.Name != mce.Name {
+		t.Fatalf("ClusterManagementAddOn controller = %v, want the multiclusterengine so its deletion is reconciled", owner)
+	}
+	if err := c.Delete(ctx, addon); err != nil {
+		t.Fatalf("failed to delete ClusterManagementAddOn: %v", err)
+	}
+
+	// The deletion event reconciles the multiclusterengine, which recreates the addon
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if getAddon() == nil {
+		t.Error("work-manager ClusterManagementAddOn was not recreated")
+	}
+}
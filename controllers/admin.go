@@ -0,0 +1,156 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// AdminTokenEnvVar holds the bearer token required by the admin endpoint. Without a token only
+	// requests from the loopback interface are accepted.
+	AdminTokenEnvVar = "ADMIN_TOKEN"
+
+	// reconcileTriggerBuffer is the number of reconcile triggers held until the controller picks
+	// them up
+	reconcileTriggerBuffer = 16
+)
+
+// AdminServer returns a runnable serving the admin endpoint on addr. A POST to /reconcile enqueues
+// a reconcile of the multiclusterengine named by the name query parameter, or of every
+// multiclusterengine without one. It only runs on the leader, whose controller handles the
+// reconciles.
+func (r *MultiClusterEngineReconciler) AdminServer(addr, token string) manager.Runnable {
+	return &adminServer{addr: addr, handler: r.adminHandler(token)}
+}
+
+type adminServer struct {
+	addr    string
+	handler http.Handler
+}
+
+func (s *adminServer) Start(ctx context.Context) error {
+	server := &http.Server{Addr: s.addr, Handler: s.handler, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() {
+		log.FromContext(ctx).Info("Starting admin server", "address", s.addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+func (s *adminServer) NeedLeaderElection() bool {
+	return true
+}
+
+func (r *MultiClusterEngineReconciler) adminHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reconcile", func(w http.ResponseWriter, req *http.Request) {
+		if !adminAuthorized(req, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		names, err := r.reconcileTargets(req.Context(), req.URL.Query().Get("name"))
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, name := range names {
+			if !r.triggerReconcile(name) {
+				http.Error(w, "reconcile queue is full, retry later", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		log.FromContext(req.Context()).Info("Reconcile triggered through the admin endpoint", "multiclusterengines", names)
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "reconcile enqueued for %s\n", strings.Join(names, ", "))
+	})
+	return mux
+}
+
+// adminAuthorized returns true if the request carries the bearer token or, when no token is
+// configured, comes from the loopback interface
+func adminAuthorized(req *http.Request, token string) bool {
+	if token != "" {
+		header := req.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			return false
+		}
+		return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, "Bearer ")), []byte(token)) == 1
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// reconcileTargets returns the names of the multiclusterengines to reconcile. A named one must
+// exist.
+func (r *MultiClusterEngineReconciler) reconcileTargets(ctx context.Context, name string) ([]string, error) {
+	if name != "" {
+		mce := &backplanev1.MultiClusterEngine{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, mce); err != nil {
+			return nil, err
+		}
+		return []string{name}, nil
+	}
+	mceList := &backplanev1.MultiClusterEngineList{}
+	if err := r.Client.List(ctx, mceList); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, mce := range mceList.Items {
+		names = append(names, mce.Name)
+	}
+	return names, nil
+}
+
+// triggerReconcile enqueues a reconcile of the named multiclusterengine. Returns false if the
+// trigger cannot be buffered.
+func (r *MultiClusterEngineReconciler) triggerReconcile(name string) bool {
+	mce := &backplanev1.MultiClusterEngine{}
+	mce.SetName(name)
+	select {
+	case r.reconcileTriggers <- event.GenericEvent{Object: mce}:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func Test_adminReconcileEndpoint(t *testing.T) {
+	s := newTestScheme()
+	mce := &backplanev1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"}}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build()
+	r := newTestReconciler(s, c)
+	r.reconcileTriggers = make(chan event.GenericEvent, reconcileTriggerBuffer)
+
+	tests := []struct {
+		name       string
+		token      string
+		method     string
+		target     string
+		remoteAddr string
+		header     string
+		wantCode   int
+	}{
+		{name: "loopback without token", method: http.MethodPost, target: "/reconcile?name=multiclusterengine", remoteAddr: "127.0.0.1:40000", wantCode: http.StatusAccepted},
+		{name: "all multiclusterengines", method: http.MethodPost, target: "/reconcile", remoteAddr: "127.0.0.1:40000", wantCode: http.StatusAccepted},
+		{name: "remote without token", method: http.MethodPost, target: "/reconcile", remoteAddr: "10.0.0.5:40000", wantCode: http.StatusUnauthorized},
+		{name: "remote with token", token: "secret", method: http.MethodPost, target: "/reconcile", remoteAddr: "10.0.0.5:40000", header: "Bearer secret", wantCode: http.StatusAccepted},
+		{name: "wrong token", token: "secret", method: http.MethodPost, target: "/reconcile", remoteAddr: "127.0.0.1:40000", header: "Bearer wrong", wantCode: http.StatusUnauthorized},
+		{name: "unknown multiclusterengine", method: http.MethodPost, target: "/reconcile?name=missing", remoteAddr: "127.0.0.1:40000", wantCode: http.StatusNotFound},
+		{name: "get", method: http.MethodGet, target: "/reconcile", remoteAddr: "127.0.0.1:40000", wantCode: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			r.adminHandler(tt.token).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status code = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusAccepted {
+				if len(r.reconcileTriggers) != 0 {
+					t.Errorf("reconcile enqueued for a rejected request")
+				}
+				return
+			}
+			select {
+			case e := <-r.reconcileTriggers:
+				if e.Object.GetName() != mce.Name {
+					t.Errorf("reconcile enqueued for %s, want %s", e.Object.GetName(), mce.Name)
+				}
+			default:
+				t.Errorf("no reconcile enqueued")
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// backplaneFieldManager is the field manager owning the fields of every resource the operator applies
+	backplaneFieldManager = "backplane-operator"
+	// adoptionFieldManager owns only the owner reference set when adopting a resource created by
+	// another installer, so that adoption does not drop fields applied by backplaneFieldManager
+	adoptionFieldManager = "backplane-operator-adoption"
+)
+
+// applyObject server-side applies obj as fieldManager, taking ownership of any conflicting fields.
+// Server populated metadata left over from a previous read or apply is cleared first, since an
+// apply request carrying a stale resourceVersion is rejected with a conflict.
+func (r *MultiClusterEngineReconciler) applyObject(ctx context.Context, obj client.Object, fieldManager string) error {
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	force := true
+	return r.Client.Patch(ctx, obj, client.Apply, &client.PatchOptions{Force: &force, FieldManager: fieldManager})
+}
+
+// withPreferredVersion sets the version the API server prefers for the kind of obj on it, so that
+// cluster-scoped resources of other operators, such as the ClusterManager, are still applied once
+// those operators move them to a new version. obj is returned for chaining.
+func (r *MultiClusterEngineReconciler) withPreferredVersion(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj.SetGroupVersionKind(utils.PreferredGVK(r.Client.RESTMapper(), obj.GroupVersionKind()))
+	return obj
+}
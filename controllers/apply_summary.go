@@ -0,0 +1,110 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	applyCreated   = "created"
+	applyUpdated   = "updated"
+	applyUnchanged = "unchanged"
+
+	// ResourcesAppliedReason is the reason of the event summarizing the resources applied by a
+	// reconcile
+	ResourcesAppliedReason = "ResourcesApplied"
+)
+
+// applyOutcomes orders the outcomes in the summary
+var applyOutcomes = []string{applyCreated, applyUpdated, applyUnchanged}
+
+// applySummary counts the outcomes of the resources applied during a reconcile, by kind
+type applySummary map[string]map[string]int
+
+// recordApplied counts the outcome of applying a resource of the given kind
+func (r *MultiClusterEngineReconciler) recordApplied(kind, outcome string) {
+	if r.appliedResources == nil {
+		r.appliedResources = applySummary{}
+	}
+	r.appliedResources.add(kind, outcome, 1)
+}
+
+func (s applySummary) add(kind, outcome string, n int) {
+	if s[kind] == nil {
+		s[kind] = map[string]int{}
+	}
+	s[kind][outcome] += n
+}
+
+// merge adds the counts of other to the summary
+func (s applySummary) merge(other applySummary) {
+	for kind, outcomes := range other {
+		for outcome, n := range outcomes {
+			s.add(kind, outcome, n)
+		}
+	}
+}
+
+// changed returns true if any resource was created or updated
+func (s applySummary) changed() bool {
+	for _, outcomes := range s {
+		if outcomes[applyCreated] > 0 || outcomes[applyUpdated] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists the counts of each kind in alphabetical order, e.g.
+// "Deployment: 2 created, 1 unchanged; Service: 1 updated"
+func (s applySummary) String() string {
+	kinds := []string{}
+	for kind := range s {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	entries := []string{}
+	for _, kind := range kinds {
+		counts := []string{}
+		for _, outcome := range applyOutcomes {
+			if n := s[kind][outcome]; n > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", n, outcome))
+			}
+		}
+		entries = append(entries, fmt.Sprintf("%s: %s", kind, strings.Join(counts, ", ")))
+	}
+	return strings.Join(entries, "; ")
+}
+
+// applyOutcome returns the outcome applying the template is about to have, given the live resource
+// and the error reading it: created if the resource does not exist, unchanged if it already matches
+// the template and updated otherwise
+func applyOutcome(template, live *unstructured.Unstructured, err error) string {
+	switch {
+	case apierrors.IsNotFound(err):
+		return applyCreated
+	case err == nil && matchesDesired(template, live):
+		return applyUnchanged
+	default:
+		return applyUpdated
+	}
+}
+
+// reportApplied emits an event summarizing the resources applied by the reconcile, unless nothing
+// was created or updated
+func (r *MultiClusterEngineReconciler) reportApplied(mce *backplanev1.MultiClusterEngine) {
+	if !r.appliedResources.changed() {
+		return
+	}
+	r.Recorder.Event(mce, corev1.EventTypeNormal, ResourcesAppliedReason, "Applied resources: "+r.appliedResources.String())
+}
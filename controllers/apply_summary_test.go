@@ -0,0 +1,165 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_applySummary(t *testing.T) {
+	s := applySummary{}
+	if s.changed() {
+		t.Errorf("changed() = true for an empty summary")
+	}
+	s.add("Service", applyUnchanged, 2)
+	if s.changed() {
+		t.Errorf("changed() = true with only unchanged resources")
+	}
+	other := applySummary{}
+	other.add("Deployment", applyCreated, 2)
+	other.add("Deployment", applyUnchanged, 1)
+	other.add("Service", applyUpdated, 1)
+	s.merge(other)
+	if !s.changed() {
+		t.Errorf("changed() = false with created resources")
+	}
+	want := "Deployment: 2 created, 1 unchanged; Service: 1 updated, 2 unchanged"
+	if got := s.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func Test_reportApplied(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	recorder := r.Recorder.(*record.FakeRecorder)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	summaries := []string{}
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		summaries = append(summaries, appliedEvents(recorder)...)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected a single %s event for the install, got %v", ResourcesAppliedReason, summaries)
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.InNamespace("multicluster-engine")); err != nil {
+		t.Fatalf("failed to list deployments: %v", err)
+	}
+	if len(deployments.Items) == 0 {
+		t.Fatalf("expected deployments to be installed")
+	}
+	want := fmt.Sprintf("Deployment: %d created;", len(deployments.Items))
+	if !strings.Contains(summaries[0], want) {
+		t.Errorf("expected the event to contain %q, got %q", want, summaries[0])
+	}
+
+	// Nothing changes once installed, so no further event is emitted
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if events := appliedEvents(recorder); len(events) != 0 {
+		t.Errorf("expected no %s event when nothing changed, got %v", ResourcesAppliedReason, events)
+	}
+}
+
+// getCountingClient counts the reads of unstructured resources sent through it
+type getCountingClient struct {
+	client.Client
+	gets *int
+}
+
+func (gc getCountingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		*gc.gets++
+	}
+	return gc.Client.Get(ctx, key, obj)
+}
+
+func Test_applyTemplateReadsLiveOnce(t *testing.T) {
+	ctx := context.Background()
+	mce := newMonitoringTestMCE()
+	s := newTestScheme()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	gets := 0
+	r.Client = getCountingClient{Client: r.Client, gets: &gets}
+
+	newTemplate := func() *unstructured.Unstructured {
+		template := &unstructured.Unstructured{}
+		template.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		template.SetName("test")
+		template.SetNamespace(mce.Spec.TargetNamespace)
+		if err := unstructured.SetNestedField(template.Object, "value", "data", "key"); err != nil {
+			t.Fatal(err)
+		}
+		return template
+	}
+
+	// Created, then reapplied with its desired state recorded, so drift is checked too
+	for _, want := range []string{applyCreated, applyUnchanged} {
+		gets = 0
+		r.appliedResources = nil
+		if _, err := r.applyTemplate(ctx, mce, newTemplate()); err != nil {
+			t.Fatalf("applyTemplate() error = %v", err)
+		}
+		if gets != 1 {
+			t.Errorf("applyTemplate() read the live resource %d times, want once", gets)
+		}
+		if n := r.appliedResources["ConfigMap"][want]; n != 1 {
+			t.Errorf("applied resources = %v, want the ConfigMap %s", r.appliedResources, want)
+		}
+	}
+}
+
+// appliedEvents drains the recorded events and returns the ResourcesApplied ones
+func appliedEvents(recorder *record.FakeRecorder) []string {
+	events := []string{}
+	for {
+		select {
+		case e := <-recorder.Events:
+			if strings.Contains(e, ResourcesAppliedReason) {
+				events = append(events, e)
+			}
+		default:
+			return events
+		}
+	}
+}
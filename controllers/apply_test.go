@@ -0,0 +1,117 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/foundation"
+	renderer "github.com/stolostron/backplane-operator/pkg/rendering"
+	"github.com/stolostron/backplane-operator/pkg/toggle"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_applyTemplateNoConflict(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	r.Images = testImages()
+	ctx := context.Background()
+
+	templates, errs := renderer.RenderChart(toggle.ClusterLifecycleChartDir, mce, r.Images)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render chart: %v", errs)
+	}
+	var template *unstructured.Unstructured
+	for _, tmpl := range templates {
+		if tmpl.GetKind() == "Deployment" {
+			template = tmpl
+			break
+		}
+	}
+
+	if _, err := r.applyTemplate(ctx, mce, template); err != nil {
+		t.Fatalf("applyTemplate() error = %v", err)
+	}
+
+	// Another writer modifies the resource between reconciles, leaving the resourceVersion
+	// populated on the template by the first apply stale
+	deployment := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(template), deployment); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	deployment.Annotations = map[string]string{"example.com/touched": "true"}
+	if err := r.Client.Update(ctx, deployment); err != nil {
+		t.Fatalf("failed to update deployment: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.applyTemplate(ctx, mce, template); err != nil {
+			t.Errorf("applyTemplate() reconcile %d error = %v, want no conflict", i, err)
+		}
+	}
+}
+
+func Test_reconcileClusterManagerPreferredVersion(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	// Discovery only serves the ClusterManager at a newer version than the one it is built with
+	preferred := foundation.ClusterManagerGVK
+	preferred.Version = "v2"
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{preferred.GroupVersion()})
+	mapper.Add(preferred, meta.RESTScopeRoot)
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	clusterManager := &unstructured.Unstructured{}
+	clusterManager.SetGroupVersionKind(preferred)
+	if err := c.Get(ctx, types.NamespacedName{Name: "cluster-manager"}, clusterManager); err != nil {
+		t.Fatalf("expected the ClusterManager to be applied at %s: %v", preferred.GroupVersion(), err)
+	}
+	clusterManager.SetGroupVersionKind(foundation.ClusterManagerGVK)
+	err := c.Get(ctx, types.NamespacedName{Name: "cluster-manager"}, clusterManager)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected no ClusterManager at %s, got error %v", foundation.ClusterManagerGVK.GroupVersion(), err)
+	}
+}
@@ -24,14 +24,15 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
 	"github.com/stolostron/backplane-operator/pkg/foundation"
 	"github.com/stolostron/backplane-operator/pkg/hive"
-	"github.com/stolostron/backplane-operator/pkg/images"
 	renderer "github.com/stolostron/backplane-operator/pkg/rendering"
 	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/tracing"
 	"github.com/stolostron/backplane-operator/pkg/utils"
 	clustermanager "open-cluster-management.io/api/operator/v1"
 
@@ -40,16 +41,21 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -69,9 +75,52 @@ type MultiClusterEngineReconciler struct {
 	Scheme        *runtime.Scheme
 	Images        map[string]string
 	StatusManager *status.StatusTracker
+	Recorder      record.EventRecorder
+	// ManagedComponents restricts reconciliation to the listed components. All components are
+	// managed when empty.
+	ManagedComponents []string
+	// MaxConcurrentReconciles is the number of MultiClusterEngines that can be reconciled at once.
+	// Defaults to 1.
+	MaxConcurrentReconciles int
+	// ComponentApplyWorkers is the number of independent components applied at once. Defaults to
+	// 4.
+	ComponentApplyWorkers int
+	// ComponentDependencies declares, for a component, the components that must report Available
+	// before it is applied. Defaults to defaultComponentDependencies.
+	ComponentDependencies map[string][]string
+	// Platform is the kind of cluster the operator runs on, PlatformOpenShift or PlatformKubernetes.
+	// Defaults to PlatformOpenShift.
+	Platform string
+	// ResyncPeriod is how often a multiclusterengine is reconciled again while it is not available
+	// or waits on a prerequisite. Defaults to 15 seconds.
+	ResyncPeriod time.Duration
+
+	// State shared by concurrent reconciles. Set up by SetupWithManager.
+	drift          *driftTracker
+	imageOverrides *imageOverrideCache
+	clusterVersion *clusterVersionCache
+	addonWatch     *addonWatcher
+
+	// reconcileTriggers carries the reconciles requested through the admin endpoint
+	reconcileTriggers chan event.GenericEvent
+
+	// desiredUnchanged is set for a reconcile when the desired state was already fully applied, so
+	// that only resources no longer matching it are applied again
+	desiredUnchanged bool
+	// pendingCRDs are the component CRDs found not established during a reconcile
+	pendingCRDs []string
+	// installedCRDs and removedCRDs are the CRDs applied and deleted during a reconcile
+	installedCRDs []string
+	removedCRDs   []string
+	// deniedPermissions are the verbs and resources the operator was forbidden from applying during
+	// a reconcile
+	deniedPermissions []string
+	// appliedResources counts the resources created, updated and left unchanged during a reconcile
+	appliedResources applySummary
 }
 
 const (
+	// requeuePeriod is the default ResyncPeriod
 	requeuePeriod      = 15 * time.Second
 	backplaneFinalizer = "finalizer.multicluster.openshift.io"
 
@@ -83,11 +132,14 @@ const (
 //+kubebuilder:rbac:groups=multicluster.openshift.io,resources=multiclusterengines/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=multicluster.openshift.io,resources=multiclusterengines/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apiextensions.k8s.io;rbac.authorization.k8s.io;"";apps,resources=deployments;serviceaccounts;customresourcedefinitions;clusterrolebindings;clusterroles,verbs=get;create;update;list
-//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create;update;list;watch;delete;patch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;create;update;list;watch;delete;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;create;update;list;watch;delete;patch
+//+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
 //+kubebuilder:rbac:groups="discovery.open-cluster-management.io",resources=discoveryconfigs,verbs=get
 //+kubebuilder:rbac:groups="discovery.open-cluster-management.io",resources=discoveryconfigs,verbs=list
 //+kubebuilder:rbac:groups="discovery.open-cluster-management.io",resources=discoveryconfigs;discoveredclusters,verbs=create;get;list;watch;update;delete;deletecollection;patch;approve;escalate;bind
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins;consolequickstarts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=operator.openshift.io,resources=consoles,verbs=get;list;watch;update;patch
 
@@ -138,14 +190,39 @@ const (
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (retRes ctrl.Result, retErr error) {
+func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Different MultiClusterEngines may be reconciled concurrently, so each reconcile tracks status
+	// and images on its own copy of the reconciler
+	rc := *r
+	rc.StatusManager = &status.StatusTracker{
+		Client:              r.StatusManager.Client,
+		APIReader:           r.StatusManager.APIReader,
+		DegradedGracePeriod: r.StatusManager.DegradedGracePeriod,
+		InstallTimeout:      r.StatusManager.InstallTimeout,
+	}
+	rc.Images = nil
+	rc.desiredUnchanged = false
+	rc.pendingCRDs = nil
+	rc.installedCRDs = nil
+	rc.removedCRDs = nil
+	rc.deniedPermissions = nil
+	rc.appliedResources = nil
+
+	ctx, span := tracing.Start(ctx, "Reconcile", tracing.MultiClusterEngineKey.String(req.Name))
+	result, err := rc.reconcile(ctx, req)
+	endSpan(span, result, err)
+	return result, err
+}
+
+func (r *MultiClusterEngineReconciler) reconcile(ctx context.Context, req ctrl.Request) (retRes ctrl.Result, retErr error) {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("multiClusterEngine", req.Name))
 	log := log.FromContext(ctx)
 	// Fetch the BackplaneConfig instance
 	backplaneConfig, err := r.getBackplaneConfig(ctx, req)
 	if err != nil && !apierrors.IsNotFound(err) {
 		// Unknown error. Requeue
 		log.Info("Failed to fetch backplaneConfig")
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	} else if err != nil && apierrors.IsNotFound(err) {
 		// BackplaneConfig deleted or not found
 		// Return and don't requeue
@@ -157,24 +234,66 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 	for _, c := range backplaneConfig.Status.Conditions {
 		r.StatusManager.AddCondition(c)
 	}
+	r.expireDriftCondition()
 
 	// Do not preform any further action on a hosted-mode MCE
 	if backplanev1.IsInHostedMode(backplaneConfig) {
 		return r.HostedReconcile(ctx, backplaneConfig)
 	}
 
+	// Set once every resource has been applied for the desired state
+	appliedStateHash := ""
+	// Set while image upgrades wait for the upgrade window, so the new version is not reported yet
+	upgradeDeferred := false
+	previousComponents := backplaneConfig.Status.EnabledComponents
 	defer func() {
 		log.Info("Updating status")
+		previousPhase := backplaneConfig.Status.Phase
+		previousVersion := backplaneConfig.Status.CurrentVersion
 		backplaneConfig.Status = r.StatusManager.ReportStatus(*backplaneConfig)
+		reportComponentReadiness(backplaneConfig.Status.Components)
+		backplaneConfig.Status.InstalledCRDs = mergeInstalledCRDs(backplaneConfig.Status.InstalledCRDs, r.installedCRDs, r.removedCRDs)
+		backplaneConfig.Status.EnabledComponents = backplaneConfig.EnabledComponentNames()
+		if appliedStateHash != "" {
+			backplaneConfig.Status.DesiredStateHash = appliedStateHash
+		}
+		if upgradeDeferred {
+			backplaneConfig.Status.CurrentVersion = previousVersion
+		}
+		succeeded := retErr == nil
+		if succeeded {
+			markReconciled(backplaneConfig)
+		}
 		err := r.Client.Status().Update(ctx, backplaneConfig)
-		if backplaneConfig.Status.Phase != backplanev1.MultiClusterEnginePhaseAvailable && !utils.IsPaused(backplaneConfig) {
-			retRes = ctrl.Result{RequeueAfter: requeuePeriod}
+		if backplaneConfig.Status.Phase != backplanev1.MultiClusterEnginePhaseAvailable &&
+			backplaneConfig.Status.Phase != backplanev1.MultiClusterEnginePhaseMaintenance && !utils.IsPaused(backplaneConfig) {
+			retRes = ctrl.Result{RequeueAfter: r.resyncPeriod()}
+		} else if upgradeDeferred && retRes.RequeueAfter == 0 {
+			retRes = ctrl.Result{RequeueAfter: upgradeWindowRequeuePeriod}
 		}
 		if err != nil {
 			retErr = err
+		} else {
+			reportPhaseTransition(previousPhase, backplaneConfig)
+			r.reportComponentToggles(previousComponents, backplaneConfig)
+			if succeeded {
+				reportReconciled(backplaneConfig)
+			}
 		}
+		r.reportApplied(backplaneConfig)
 	}()
 
+	// A multiclusterengine sharing its target namespace with an older one is left alone, so the two
+	// do not fight over the same resources
+	conflict, err := r.conflictingMCE(ctx, backplaneConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if conflict != "" {
+		return r.reconcileConflicting(ctx, backplaneConfig, conflict)
+	}
+	r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineConflicting)
+
 	// If deletion detected, finalize backplane config
 	if backplaneConfig.GetDeletionTimestamp() != nil {
 		if controllerutil.ContainsFinalizer(backplaneConfig, backplaneFinalizer) {
@@ -194,6 +313,10 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, nil // Object finalized successfully
 	}
 
+	if r.downgradeBlocked(ctx, backplaneConfig) {
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
 	// Add finalizer for this CR
 	if !controllerutil.ContainsFinalizer(backplaneConfig, backplaneFinalizer) {
 		controllerutil.AddFinalizer(backplaneConfig, backplaneFinalizer)
@@ -202,6 +325,8 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 		}
 	}
 
+	r.reportPlatform(ctx, backplaneConfig)
+
 	var result ctrl.Result
 
 	result, err = r.setDefaults(ctx, backplaneConfig)
@@ -229,7 +354,7 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	// Read images from environmental variables
-	imgs, err := images.GetImagesWithOverrides(r.Client, backplaneConfig)
+	imgs, err := r.getImages(ctx, backplaneConfig)
 	if err != nil {
 		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason, fmt.Sprintf("Issue building image references: %s", err.Error())))
 		return ctrl.Result{}, err
@@ -237,9 +362,15 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if len(imgs) == 0 {
 		// If images are not set from environmental variables, fail
 		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason, "No image references defined in deployment"))
-		return ctrl.Result{RequeueAfter: requeuePeriod}, errors.New("no image references exist. images must be defined as environment variables")
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, errors.New("no image references exist. images must be defined as environment variables")
+	}
+	r.Images, upgradeDeferred = r.deferImageUpgrades(ctx, backplaneConfig, imgs)
+
+	stateHash := desiredStateHash(backplaneConfig, r.Images)
+	if desiredStateUnchanged(backplaneConfig, stateHash) {
+		log.Info("Desired state unchanged, only applying resources that no longer match it")
+		r.desiredUnchanged = true
 	}
-	r.Images = imgs
 
 	// Do not reconcile objects if this instance of mce is labeled "paused"
 	if utils.IsPaused(backplaneConfig) {
@@ -254,7 +385,29 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, nil
 	}
 
-	result, err = r.adoptExistingSubcomponents(ctx, backplaneConfig)
+	if _, err := r.recreateComponent(ctx, backplaneConfig); err != nil {
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+	}
+	// The recreate annotation has been removed by now
+	stateHash = desiredStateHash(backplaneConfig, r.Images)
+	backplaneConfig.Status.DeployedImages = r.Images
+
+	// Bundled CRD versions only change with the operator version, which is part of the desired state
+	if !r.desiredUnchanged {
+		result, err = traced(ctx, "PruneCRDVersions", func(ctx context.Context) (ctrl.Result, error) {
+			return ctrl.Result{}, r.pruneCRDVersions(ctx)
+		})
+		if err != nil {
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+		}
+		if err := r.recordOperatorCRDs(); err != nil {
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+		}
+	}
+
+	result, err = traced(ctx, "AdoptExistingSubcomponents", func(ctx context.Context) (ctrl.Result, error) {
+		return r.adoptExistingSubcomponents(ctx, backplaneConfig)
+	})
 	if err != nil {
 		cond := status.NewCondition(
 			backplanev1.MultiClusterEngineProgressing,
@@ -266,7 +419,9 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return result, err
 	}
 
-	result, err = r.DeployAlwaysSubcomponents(ctx, backplaneConfig)
+	result, err = traced(ctx, "DeployAlwaysSubcomponents", func(ctx context.Context) (ctrl.Result, error) {
+		return r.DeployAlwaysSubcomponents(ctx, backplaneConfig)
+	})
 	if err != nil {
 		cond := status.NewCondition(
 			backplanev1.MultiClusterEngineProgressing,
@@ -278,12 +433,30 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return result, err
 	}
 
-	result, err = r.ensureToggleableComponents(ctx, backplaneConfig)
+	if err := r.validatePriorityClasses(ctx, backplaneConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.validateComponentNames(ctx, backplaneConfig)
+	r.warnDeprecatedComponents(backplaneConfig)
+
+	result, err = traced(ctx, "EnsureToggleableComponents", func(ctx context.Context) (ctrl.Result, error) {
+		return r.ensureToggleableComponents(ctx, backplaneConfig)
+	})
+	r.reportCRDsEstablished()
+	if err != nil {
+		return result, err
+	}
+
+	if utils.NetworkPoliciesEnabled(backplaneConfig) {
+		result, err = r.ensureNetworkPolicies(ctx, backplaneConfig)
+	} else {
+		result, err = r.ensureNoNetworkPolicies(ctx, backplaneConfig)
+	}
 	if err != nil {
 		return result, err
 	}
 
-	result, err = r.createTrustBundleConfigmap(ctx, backplaneConfig)
+	result, err = r.ensureTrustBundle(ctx, backplaneConfig)
 	if err != nil {
 		return result, err
 	}
@@ -293,19 +466,53 @@ func (r *MultiClusterEngineReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return result, err
 	}
 
+	// The rendered manifests are only for auditing, so failing to write them does not hold up the
+	// install
+	if err := r.ensureRenderedManifests(ctx, backplaneConfig); err != nil {
+		log.Error(err, "failed to write rendered manifests")
+		r.Recorder.Event(backplaneConfig, corev1.EventTypeWarning, "RenderedManifestsFailed", err.Error())
+	}
+
+	if !r.reportPermissions() {
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
 	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionTrue, status.DeploySuccessReason, "All components deployed"))
+	appliedStateHash = stateHash
 
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *MultiClusterEngineReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&backplanev1.MultiClusterEngine{}).
-		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
-		Watches(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{
-			OwnerType: &backplanev1.MultiClusterEngine{},
-		}).
+	if err := validateComponentDependencies(r.componentDependencies()); err != nil {
+		return err
+	}
+	if r.drift == nil {
+		r.drift = &driftTracker{}
+	}
+	if r.imageOverrides == nil {
+		r.imageOverrides = &imageOverrideCache{}
+	}
+	if r.clusterVersion == nil {
+		r.clusterVersion = &clusterVersionCache{}
+	}
+	if r.reconcileTriggers == nil {
+		r.reconcileTriggers = make(chan event.GenericEvent, reconcileTriggerBuffer)
+	}
+
+	changed := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+	ownerHandler := &handler.EnqueueRequestForOwner{
+		OwnerType: &backplanev1.MultiClusterEngine{},
+	}
+
+	mceBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&backplanev1.MultiClusterEngine{}, builder.WithPredicates(changed)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Watches(&source.Kind{Type: &appsv1.Deployment{}}, ownerHandler, builder.WithPredicates(changed)).
+		// Services and configmaps carry no generation, so any change to a managed one is reconciled
+		Watches(&source.Kind{Type: &corev1.Service{}}, ownerHandler, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ownerHandler, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Watches(&source.Kind{Type: &hiveconfig.HiveConfig{}}, &handler.Funcs{
 			DeleteFunc: func(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
 				labels := e.Object.GetLabels()
@@ -328,42 +535,103 @@ func (r *MultiClusterEngineReconciler) SetupWithManager(mgr ctrl.Manager) error
 				}})
 			},
 		}, builder.WithPredicates(predicate.LabelChangedPredicate{})).
-		Watches(&source.Kind{Type: &monitorv1.ServiceMonitor{}}, &handler.Funcs{
-			DeleteFunc: func(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
-				labels := e.Object.GetLabels()
-				if label, ok := labels["backplaneconfig.name"]; ok {
-					q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
-						Name: label,
-					}})
-				}
-			},
-		}, builder.WithPredicates(predicate.LabelChangedPredicate{})).
-		Watches(&source.Kind{Type: &configv1.ClusterVersion{}}, &handler.Funcs{
+		Watches(&source.Kind{Type: &apixv1.CustomResourceDefinition{}},
+			handler.EnqueueRequestsFromMapFunc(r.monitoringCRDToMCE), builder.WithPredicates(changed)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.imageOverridesConfigmapToMCE), builder.WithPredicates(imageOverridesConfigmapChanged)).
+		Watches(&source.Channel{Source: r.reconcileTriggers}, &handler.EnqueueRequestForObject{})
+
+	// The ClusterVersion and the Prometheus operator APIs are only watched on OpenShift
+	if r.onOpenShift() {
+		mceBuilder = mceBuilder.Watches(&source.Kind{Type: &configv1.ClusterVersion{}}, &handler.Funcs{
 			UpdateFunc: func(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
 				labels := e.ObjectOld.GetLabels()
 				q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
 					Name: labels["backplaneconfig.name"],
 				}})
 			},
-		}, builder.WithPredicates(predicate.LabelChangedPredicate{})).
-		Complete(r)
+		}, builder.WithPredicates(predicate.LabelChangedPredicate{}))
+
+		// Monitoring resources can only be watched if the Prometheus operator is installed
+		for _, obj := range []client.Object{&monitorv1.ServiceMonitor{}, &monitorv1.PrometheusRule{}} {
+			gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+			if err != nil {
+				return err
+			}
+			if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+				ctrl.Log.WithName("setup").Info(fmt.Sprintf("%s API is not installed. Not watching %ss.", gvk.Kind, gvk.Kind))
+				continue
+			}
+			mceBuilder = mceBuilder.Watches(&source.Kind{Type: obj}, ownerHandler, builder.WithPredicates(changed))
+		}
+	}
+
+	c, err := mceBuilder.Build(r)
+	if err != nil {
+		return err
+	}
+	r.addonWatch = &addonWatcher{controller: c}
+	return nil
 }
 
-// createTrustBundleConfigmap creates a configmap that will be injected with the
-// trusted CA bundle for use with the OCP cluster wide proxy
-func (r *MultiClusterEngineReconciler) createTrustBundleConfigmap(ctx context.Context, mce *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
+// ensureTrustBundle creates the trust bundle configmap, or removes it if the trust bundle
+// has been disabled
+func (r *MultiClusterEngineReconciler) ensureTrustBundle(ctx context.Context, mce *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+	if utils.IsTrustBundleDisabled(mce) {
+		return r.removeTrustBundleConfigmap(ctx, mce)
+	}
+	return r.createTrustBundleConfigmap(ctx, mce)
+}
 
-	// Get Trusted Bundle configmap name
+// trustBundleNamespacedName returns the name and namespace of the trust bundle configmap
+func trustBundleNamespacedName(mce *backplanev1.MultiClusterEngine) types.NamespacedName {
 	trustBundleName := defaultTrustBundleName
-	trustBundleNamespace := mce.Spec.TargetNamespace
 	if name, ok := os.LookupEnv(trustBundleNameEnvVar); ok && name != "" {
 		trustBundleName = name
 	}
-	namespacedName := types.NamespacedName{
+	return types.NamespacedName{
 		Name:      trustBundleName,
-		Namespace: trustBundleNamespace,
+		Namespace: mce.Spec.TargetNamespace,
 	}
+}
+
+// removeTrustBundleConfigmap deletes the trust bundle configmap if it was created by this operator
+func (r *MultiClusterEngineReconciler) removeTrustBundleConfigmap(ctx context.Context, mce *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	namespacedName := trustBundleNamespacedName(mce)
+
+	cm := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, namespacedName, cm)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Info(fmt.Sprintf("error while getting trust bundle configmap %s: %s", namespacedName.Name, err))
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+	}
+
+	// Leave configmaps not created by the operator alone
+	owner := metav1.GetControllerOf(cm)
+	if owner == nil || owner.UID != mce.GetUID() {
+		return ctrl.Result{}, nil
+	}
+
+	log.Info(fmt.Sprintf("trust bundle disabled. Removing trust bundle configmap %s/%s", namespacedName.Namespace, namespacedName.Name))
+	err = r.Client.Delete(ctx, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// createTrustBundleConfigmap creates a configmap that will be injected with the
+// trusted CA bundle for use with the OCP cluster wide proxy
+func (r *MultiClusterEngineReconciler) createTrustBundleConfigmap(ctx context.Context, mce *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	// Get Trusted Bundle configmap name
+	namespacedName := trustBundleNamespacedName(mce)
+	trustBundleName := namespacedName.Name
+	trustBundleNamespace := namespacedName.Namespace
 	log.Info("using trust bundle configmap %s/%s", trustBundleNamespace, trustBundleName)
 
 	// Check if configmap exists
@@ -372,7 +640,7 @@ func (r *MultiClusterEngineReconciler) createTrustBundleConfigmap(ctx context.Co
 	if err != nil && !apierrors.IsNotFound(err) {
 		// Unknown error. Requeue
 		log.Info(fmt.Sprintf("error while getting trust bundle configmap %s: %s", trustBundleName, err))
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	} else if err == nil {
 		// configmap exists
 		return ctrl.Result{}, nil
@@ -399,7 +667,7 @@ func (r *MultiClusterEngineReconciler) createTrustBundleConfigmap(ctx context.Co
 	if err != nil {
 		// Error creating configmap
 		log.Info(fmt.Sprintf("error creating trust bundle configmap %s: %s", trustBundleName, err))
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 	// Configmap created successfully
 	return ctrl.Result{}, nil
@@ -416,7 +684,7 @@ func (r *MultiClusterEngineReconciler) DeployAlwaysSubcomponents(ctx context.Con
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -444,207 +712,144 @@ func (r *MultiClusterEngineReconciler) ensureToggleableComponents(ctx context.Co
 	errs := map[string]error{}
 	requeue := false
 
-	if backplaneConfig.Enabled(backplanev1.ManagedServiceAccount) {
-		result, err := r.ensureManagedServiceAccount(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ManagedServiceAccount] = err
-		}
-	} else {
-		result, err := r.ensureNoManagedServiceAccount(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ManagedServiceAccount] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.HyperShift) {
-		result, err := r.ensureHyperShift(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.HyperShift] = err
-		}
-	} else {
-		result, err := r.ensureNoHyperShift(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.HyperShift] = err
-		}
-	}
-
 	ocpConsole, err := r.CheckConsole(ctx)
 	if err != nil {
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
-	}
-
-	if backplaneConfig.Enabled(backplanev1.ConsoleMCE) && ocpConsole {
-		result, err := r.ensureConsoleMCE(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ConsoleMCE] = err
-		}
-	} else {
-		result, err := r.ensureNoConsoleMCE(ctx, backplaneConfig, ocpConsole)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ConsoleMCE] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.Discovery) {
-		result, err := r.ensureDiscovery(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.Discovery] = err
-		}
-	} else {
-		result, err := r.ensureNoDiscovery(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.Discovery] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.Hive) {
-		result, err := r.ensureHive(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.Hive] = err
-		}
-	} else {
-		result, err := r.ensureNoHive(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.Hive] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.AssistedService) {
-		result, err := r.ensureAssistedService(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.AssistedService] = err
-		}
-	} else {
-		result, err := r.ensureNoAssistedService(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.AssistedService] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.ClusterLifecycle) {
-		result, err := r.ensureClusterLifecycle(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ClusterLifecycle] = err
-		}
-	} else {
-		result, err := r.ensureNoClusterLifecycle(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ClusterLifecycle] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.ClusterManager) {
-		result, err := r.ensureClusterManager(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ClusterManager] = err
-		}
-	} else {
-		result, err := r.ensureNoClusterManager(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ClusterManager] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.ServerFoundation) {
-		result, err := r.ensureServerFoundation(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ServerFoundation] = err
-		}
-	} else {
-		result, err := r.ensureNoServerFoundation(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ServerFoundation] = err
-		}
-	}
-
-	if backplaneConfig.Enabled(backplanev1.ClusterProxyAddon) {
-		result, err := r.ensureClusterProxyAddon(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ClusterProxyAddon] = err
-		}
-	} else {
-		result, err := r.ensureNoClusterProxyAddon(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.ClusterProxyAddon] = err
-		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+	}
+
+	type toggleableComponent struct {
+		name     string
+		enabled  bool
+		ensure   func(*MultiClusterEngineReconciler, context.Context, *backplanev1.MultiClusterEngine) (ctrl.Result, error)
+		ensureNo func(*MultiClusterEngineReconciler, context.Context, *backplanev1.MultiClusterEngine) (ctrl.Result, error)
+	}
+	components := []toggleableComponent{
+		{backplanev1.ManagedServiceAccount, backplaneConfig.Enabled(backplanev1.ManagedServiceAccount), (*MultiClusterEngineReconciler).ensureManagedServiceAccount, (*MultiClusterEngineReconciler).ensureNoManagedServiceAccount},
+		{backplanev1.HyperShift, backplaneConfig.Enabled(backplanev1.HyperShift), (*MultiClusterEngineReconciler).ensureHyperShift, (*MultiClusterEngineReconciler).ensureNoHyperShift},
+		{backplanev1.ConsoleMCE, backplaneConfig.Enabled(backplanev1.ConsoleMCE) && ocpConsole, (*MultiClusterEngineReconciler).ensureConsoleMCE,
+			func(r *MultiClusterEngineReconciler, ctx context.Context, mce *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+				return r.ensureNoConsoleMCE(ctx, mce, ocpConsole)
+			}},
+		{backplanev1.Discovery, backplaneConfig.Enabled(backplanev1.Discovery), (*MultiClusterEngineReconciler).ensureDiscovery, (*MultiClusterEngineReconciler).ensureNoDiscovery},
+		{backplanev1.Hive, backplaneConfig.Enabled(backplanev1.Hive), (*MultiClusterEngineReconciler).ensureHive, (*MultiClusterEngineReconciler).ensureNoHive},
+		{backplanev1.AssistedService, backplaneConfig.Enabled(backplanev1.AssistedService), (*MultiClusterEngineReconciler).ensureAssistedService, (*MultiClusterEngineReconciler).ensureNoAssistedService},
+		{backplanev1.ClusterLifecycle, backplaneConfig.Enabled(backplanev1.ClusterLifecycle), (*MultiClusterEngineReconciler).ensureClusterLifecycle, (*MultiClusterEngineReconciler).ensureNoClusterLifecycle},
+		{backplanev1.ClusterManager, backplaneConfig.Enabled(backplanev1.ClusterManager), (*MultiClusterEngineReconciler).ensureClusterManager, (*MultiClusterEngineReconciler).ensureNoClusterManager},
+		{backplanev1.ServerFoundation, backplaneConfig.Enabled(backplanev1.ServerFoundation), (*MultiClusterEngineReconciler).ensureServerFoundation, (*MultiClusterEngineReconciler).ensureNoServerFoundation},
+		{backplanev1.ClusterProxyAddon, backplaneConfig.Enabled(backplanev1.ClusterProxyAddon), (*MultiClusterEngineReconciler).ensureClusterProxyAddon, (*MultiClusterEngineReconciler).ensureNoClusterProxyAddon},
+		{backplanev1.LocalCluster, backplaneConfig.Enabled(backplanev1.LocalCluster), (*MultiClusterEngineReconciler).ensureLocalCluster, (*MultiClusterEngineReconciler).ensureNoLocalCluster},
+		{backplanev1.ImageBasedInstallOperator, backplaneConfig.Enabled(backplanev1.ImageBasedInstallOperator), (*MultiClusterEngineReconciler).ensureImageBasedInstallOperator, (*MultiClusterEngineReconciler).ensureNoImageBasedInstallOperator},
+	}
+
+	enabled := []string{}
+	for _, c := range components {
+		if c.enabled && r.manages(c.name) {
+			enabled = append(enabled, c.name)
+		}
+	}
+	skipped, err := r.skipUnsupportedComponents(ctx, backplaneConfig, enabled)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
-	if backplaneConfig.Enabled(backplanev1.LocalCluster) {
-		result, err := r.ensureLocalCluster(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
-		}
-		if err != nil {
-			errs[backplanev1.LocalCluster] = err
+	managed := map[string]toggleableComponent{}
+	applied, removed := []string{}, []string{}
+	for _, c := range components {
+		if !r.manages(c.name) {
+			// Components outside the managed set are neither created nor removed
+			continue
 		}
-	} else {
-		result, err := r.ensureNoLocalCluster(ctx, backplaneConfig)
-		if result != (ctrl.Result{}) {
-			requeue = true
+		if skipped[c.name] {
+			// Components the cluster cannot support are not installed. They were never deployed, so
+			// there is nothing to remove either.
+			continue
 		}
-		if err != nil {
-			errs[backplanev1.LocalCluster] = err
+		managed[c.name] = c
+		if c.enabled {
+			applied = append(applied, c.name)
+		} else {
+			removed = append(removed, c.name)
+		}
+	}
+
+	// Disabled components are torn down in reverse dependency order before the enabled ones are
+	// applied. A prerequisite is only removed once the removal of its dependents has completed, so
+	// dependents do not error against a prerequisite that is already gone.
+	dependents := componentDependents(r.componentDependencies())
+	waves := append(componentWaves(removed, dependents), componentWaves(applied, r.componentDependencies())...)
+	removing := map[string]bool{}
+
+	// Components in a wave do not depend on each other and are applied in parallel. Each worker
+	// applies on its own copy of the reconciler, whose findings are merged back once it is done.
+	base := *r
+	base.pendingCRDs = nil
+	base.installedCRDs = nil
+	base.removedCRDs = nil
+	base.deniedPermissions = nil
+	base.appliedResources = nil
+	var mu sync.Mutex
+	for _, wave := range waves {
+		workers := make(chan struct{}, r.componentApplyWorkers())
+		var wg sync.WaitGroup
+		for _, name := range wave {
+			c := managed[name]
+			if !c.enabled {
+				mu.Lock()
+				dependent := removingDependent(c.name, dependents, removing)
+				if dependent != "" {
+					// Removals that wait on this one wait on its dependent as well
+					removing[c.name] = true
+					requeue = true
+				}
+				mu.Unlock()
+				if dependent != "" {
+					log.FromContext(ctx).Info(fmt.Sprintf("Waiting for %s to be removed before removing %s", dependent, c.name))
+					continue
+				}
+			}
+			wg.Add(1)
+			workers <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-workers }()
+
+				wr := base
+				result, err := traced(ctx, "EnsureComponent", func(ctx context.Context) (ctrl.Result, error) {
+					if c.enabled {
+						return wr.ensureWithPrerequisites(ctx, backplaneConfig, c.name,
+							func(ctx context.Context, mce *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+								return c.ensure(&wr, ctx, mce)
+							})
+					}
+					return c.ensureNo(&wr, ctx, backplaneConfig)
+				}, tracing.ComponentKey.String(c.name), tracing.EnabledKey.Bool(c.enabled))
+
+				mu.Lock()
+				defer mu.Unlock()
+				r.pendingCRDs = append(r.pendingCRDs, wr.pendingCRDs...)
+				r.installedCRDs = append(r.installedCRDs, wr.installedCRDs...)
+				r.removedCRDs = append(r.removedCRDs, wr.removedCRDs...)
+				for _, p := range wr.deniedPermissions {
+					r.recordPermission(p)
+				}
+				if len(wr.appliedResources) > 0 {
+					if r.appliedResources == nil {
+						r.appliedResources = applySummary{}
+					}
+					r.appliedResources.merge(wr.appliedResources)
+				}
+				if result != (ctrl.Result{}) {
+					requeue = true
+				}
+				if err != nil {
+					errs[c.name] = err
+				}
+				if !c.enabled && (result != (ctrl.Result{}) || err != nil) {
+					removing[c.name] = true
+				}
+			}()
 		}
+		wg.Wait()
 	}
 
 	if len(errs) > 0 {
@@ -654,44 +859,100 @@ func (r *MultiClusterEngineReconciler) ensureToggleableComponents(ctx context.Co
 		}
 		combinedError := fmt.Sprintf(": %s", strings.Join(errorMessages, "; "))
 		log.FromContext(ctx).Error(errors.New("Errors applying components"), combinedError)
-		return ctrl.Result{RequeueAfter: requeuePeriod}, errors.New(combinedError)
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, errors.New(combinedError)
 	}
 	if requeue {
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 	return ctrl.Result{}, nil
 }
 
 func (r *MultiClusterEngineReconciler) applyTemplate(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine, template *unstructured.Unstructured) (ctrl.Result, error) {
+	// Skip monitoring resources on clusters without the Prometheus operator, and ServiceMonitors
+	// managed by external tooling
+	if serviceMonitorUnmanaged(backplaneConfig, template) || r.skipMonitoringResource(ctx, template) {
+		return ctrl.Result{}, nil
+	}
+
 	// Set owner reference.
 	err := ctrl.SetControllerReference(backplaneConfig, template, r.Scheme)
 	if err != nil {
 		return ctrl.Result{}, pkgerrors.Wrapf(err, "Error setting controller reference on resource %s", template.GetName())
 	}
+	// Rendered templates without a namespace, such as CRDs and ClusterRoles, are cluster-scoped
+	if template.GetNamespace() == "" {
+		utils.AddClusterScopedLabels(template, backplaneConfig.Name)
+	}
 
 	if template.GetKind() == "APIService" {
 		result, err := r.ensureUnstructuredResource(ctx, backplaneConfig, template)
+		if apierrors.IsForbidden(err) {
+			r.recordDeniedPermission(template, err)
+			return ctrl.Result{}, nil
+		}
 		if err != nil {
 			return result, err
 		}
 	} else {
+		// The live resource is read once to skip, detect drift in and summarize the apply
+		live, liveErr := r.getLive(ctx, template)
+		if r.desiredUnchanged && liveErr == nil && matchesDesired(template, live) {
+			// Applied before and not modified since
+			r.drift.recordApplied(driftKey(template), desiredHash(template))
+			r.recordComponentImage(template)
+			r.recordCRD(template, true)
+			r.recordApplied(template.GetKind(), applyUnchanged)
+			return ctrl.Result{}, nil
+		}
+		r.detectDrift(ctx, template, live)
+		r.recordComponentImage(template)
+		outcome := applyOutcome(template, live, liveErr)
+
 		// Apply the object data.
-		force := true
-		err = r.Client.Patch(ctx, template, client.Apply, &client.PatchOptions{Force: &force, FieldManager: "backplane-operator"})
+		err = r.applyObject(ctx, template, backplaneFieldManager)
+		if apierrors.IsForbidden(err) {
+			// Keep applying the other resources so every missing permission is reported at once
+			r.recordDeniedPermission(template, err)
+			return ctrl.Result{}, nil
+		}
 		if err != nil {
 			return ctrl.Result{}, pkgerrors.Wrapf(err, "error applying object Name: %s Kind: %s", template.GetName(), template.GetKind())
 		}
+		r.recordCRD(template, true)
+		r.recordApplied(template.GetKind(), outcome)
 	}
 	return ctrl.Result{}, nil
 }
 
+// recordComponentImage records the image of a Deployment's primary container in the status
+func (r *MultiClusterEngineReconciler) recordComponentImage(template *unstructured.Unstructured) {
+	if template.GetKind() != "Deployment" {
+		return
+	}
+	containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if image, ok := container["image"].(string); ok {
+		r.StatusManager.AddImage(template.GetName(), image)
+	}
+}
+
 // deleteTemplate return true if resource does not exist and returns an error if a GET or DELETE errors unexpectedly. A false response without error
 // means the resource is in the process of deleting.
 func (r *MultiClusterEngineReconciler) deleteTemplate(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine, template *unstructured.Unstructured) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
+	if serviceMonitorUnmanaged(backplaneConfig, template) {
+		return ctrl.Result{}, nil
+	}
 	err := r.Client.Get(ctx, types.NamespacedName{Name: template.GetName(), Namespace: template.GetNamespace()}, template)
 
-	if err != nil && apierrors.IsNotFound(err) {
+	if err != nil && (apierrors.IsNotFound(err) || meta.IsNoMatchError(err)) {
+		r.recordCRD(template, false)
 		return ctrl.Result{}, nil
 	}
 
@@ -707,6 +968,7 @@ func (r *MultiClusterEngineReconciler) deleteTemplate(ctx context.Context, backp
 		log.Error(err, "Failed to delete template")
 		return ctrl.Result{}, err
 	}
+	r.recordCRD(template, false)
 
 	return ctrl.Result{}, nil
 }
@@ -715,24 +977,31 @@ func (r *MultiClusterEngineReconciler) ensureCustomResources(ctx context.Context
 	log := log.FromContext(ctx)
 
 	if foundation.CanInstallAddons(ctx, r.Client) {
+		if err := r.watchClusterManagementAddOns(ctx); err != nil {
+			return ctrl.Result{}, pkgerrors.Wrap(err, "error watching ClusterManagementAddOns")
+		}
 		addonTemplates, err := foundation.GetAddons()
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 		for _, addonTemplate := range addonTemplates {
+			r.withPreferredVersion(addonTemplate)
 			addonTemplate.SetNamespace(backplaneConfig.Spec.TargetNamespace)
+			utils.AddClusterScopedLabels(addonTemplate, backplaneConfig.Name)
 			if err := ctrl.SetControllerReference(backplaneConfig, addonTemplate, r.Scheme); err != nil {
 				return ctrl.Result{}, pkgerrors.Wrapf(err, "Error setting controller reference on resource %s", addonTemplate.GetName())
 			}
-			force := true
-			err := r.Client.Patch(ctx, addonTemplate, client.Apply, &client.PatchOptions{Force: &force, FieldManager: "backplane-operator"})
+			if r.desiredUnchanged && r.matchesLive(ctx, addonTemplate) {
+				continue
+			}
+			err := r.applyObject(ctx, addonTemplate, backplaneFieldManager)
 			if err != nil {
 				return ctrl.Result{}, pkgerrors.Wrapf(err, "error applying object Name: %s Kind: %s", addonTemplate.GetName(), addonTemplate.GetKind())
 			}
 		}
 	} else {
 		log.Info("ClusterManagementAddon API is not installed. Waiting to install addons.")
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	return ctrl.Result{}, nil
@@ -740,10 +1009,11 @@ func (r *MultiClusterEngineReconciler) ensureCustomResources(ctx context.Context
 
 func (r *MultiClusterEngineReconciler) finalizeBackplaneConfig(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) error {
 	log := log.FromContext(ctx)
-	_, err := r.removePluginFromConsoleResource(ctx, backplaneConfig)
-	if err != nil {
-		log.Info("Error ensuring plugin is removed from console resource")
-		return err
+	if r.onOpenShift() {
+		if _, err := r.removePluginFromConsoleResource(ctx, backplaneConfig); err != nil {
+			log.Info("Error ensuring plugin is removed from console resource")
+			return err
+		}
 	}
 
 	localCluster := &unstructured.Unstructured{}
@@ -754,41 +1024,18 @@ func (r *MultiClusterEngineReconciler) finalizeBackplaneConfig(ctx context.Conte
 			Kind:    "ManagedCluster",
 		},
 	)
-	err = r.Client.Get(ctx, types.NamespacedName{Name: "local-cluster"}, localCluster)
-	if err == nil { // If resource exists, delete
-		log.Info("finalizing local-cluster custom resource")
-		err := r.Client.Delete(ctx, localCluster)
-		if err != nil {
-			log.Error(err, "error deleting local-cluster ManagedCluster CR")
-			return err
-		}
-	} else if err != nil && !apierrors.IsNotFound(err) { // Return error, if error is not not found error
-		log.Error(err, "error while looking for local-cluster ManagedCluster CR")
+	if err := r.ensureDeleted(ctx, localCluster, "ManagedCluster", "local-cluster"); err != nil {
 		return err
 	}
 
 	clusterManager := &unstructured.Unstructured{}
-	clusterManager.SetGroupVersionKind(
-		schema.GroupVersionKind{
-			Group:   "operator.open-cluster-management.io",
-			Version: "v1",
-			Kind:    "ClusterManager",
-		},
-	)
-
-	err = r.Client.Get(ctx, types.NamespacedName{Name: "cluster-manager"}, clusterManager)
-	if err == nil { // If resource exists, delete
-		log.Info("finalizing cluster-manager custom resource")
-		err := r.Client.Delete(ctx, clusterManager)
-		if err != nil {
-			return err
-		}
-	} else if err != nil && !apierrors.IsNotFound(err) { // Return error, if error is not not found error
+	clusterManager.SetGroupVersionKind(utils.PreferredGVK(r.Client.RESTMapper(), foundation.ClusterManagerGVK))
+	if err := r.ensureDeleted(ctx, clusterManager, "ClusterManager", "cluster-manager"); err != nil {
 		return err
 	}
 
 	ocmHubNamespace := &corev1.Namespace{}
-	err = r.Client.Get(ctx, types.NamespacedName{Name: "open-cluster-management-hub"}, ocmHubNamespace)
+	err := r.Client.Get(ctx, types.NamespacedName{Name: "open-cluster-management-hub"}, ocmHubNamespace)
 	if err == nil {
 		// If wait time exceeds expected then uninstall may not be able to progress
 		if time.Since(backplaneConfig.DeletionTimestamp.Time) < 5*time.Minute {
@@ -805,17 +1052,36 @@ func (r *MultiClusterEngineReconciler) finalizeBackplaneConfig(ctx context.Conte
 	}
 
 	globalSetNamespace := &corev1.Namespace{}
-	err = r.Client.Get(ctx, types.NamespacedName{Name: "open-cluster-management-global-set"}, globalSetNamespace)
-	if err == nil {
-		err := r.Client.Delete(ctx, globalSetNamespace)
-		if err != nil {
+	return r.ensureDeleted(ctx, globalSetNamespace, "namespace", "open-cluster-management-global-set")
+}
+
+// ensureDeleted deletes a cluster-scoped dependent of the multiclusterengine unless it is already
+// terminating, and returns an error for as long as it exists. Dependents with finalizers of their
+// own, such as the ClusterManager, can take a while to go away, so the caller requeues and checks
+// again rather than removing the multiclusterengine finalizer early.
+func (r *MultiClusterEngineReconciler) ensureDeleted(ctx context.Context, obj client.Object, kind, name string) error {
+	err := r.Client.Get(ctx, types.NamespacedName{Name: name}, obj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("error while looking for %s %s", kind, name))
+		return err
+	}
+
+	if obj.GetDeletionTimestamp() == nil {
+		log.FromContext(ctx).Info(fmt.Sprintf("finalizing %s %s", kind, name))
+		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
 			return err
 		}
-	} else if err != nil && !apierrors.IsNotFound(err) {
-		return err
+		// Resources without finalizers are removed right away
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, obj); apierrors.IsNotFound(err) {
+			return nil
+		}
 	}
 
-	return nil
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionTrue,
+		status.WaitingForResourceReason, fmt.Sprintf("Waiting for %s %s to be deleted.", kind, name)))
+	return fmt.Errorf("waiting for %s %s to be deleted before proceeding with uninstallation", kind, name)
 }
 
 func (r *MultiClusterEngineReconciler) getBackplaneConfig(ctx context.Context, req ctrl.Request) (*backplanev1.MultiClusterEngine, error) {
@@ -860,6 +1126,7 @@ func (r *MultiClusterEngineReconciler) ensureUnstructuredResource(ctx context.Co
 		}
 		// Creation was successful
 		log.Info(fmt.Sprintf("Created new resource - kind: %s name: %s", u.GetKind(), u.GetName()))
+		r.recordApplied(u.GetKind(), applyCreated)
 		// condition := NewHubCondition(operatorsv1.Progressing, metav1.ConditionTrue, NewComponentReason, "Created new resource")
 		// SetHubCondition(&m.Status, *condition)
 		return ctrl.Result{}, nil
@@ -869,6 +1136,7 @@ func (r *MultiClusterEngineReconciler) ensureUnstructuredResource(ctx context.Co
 		log.Error(err, "Failed to get resource")
 		return ctrl.Result{}, err
 	}
+	r.recordApplied(u.GetKind(), applyUnchanged)
 
 	return ctrl.Result{}, nil
 }
@@ -895,19 +1163,50 @@ func (r *MultiClusterEngineReconciler) setDefaults(ctx context.Context, m *backp
 		updateNecessary = true
 	}
 
+	if r.onOpenShift() {
+		updated, err := r.setOpenShiftDefaults(ctx, m)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		updateNecessary = updateNecessary || updated
+	}
+
+	// Apply defaults to server
+	if updateNecessary {
+		log.Info("Setting defaults")
+		if err := r.Client.Update(ctx, m); err != nil {
+			log.Error(err, "Failed to update MultiClusterEngine")
+			return ctrl.Result{}, err
+		}
+		log.Info("MultiClusterEngine successfully updated")
+		return ctrl.Result{Requeue: true}, nil
+	} else {
+		return ctrl.Result{}, nil
+	}
+
+}
+
+// setOpenShiftDefaults records the cluster ingress domain and OpenShift version for the charts to
+// render, and enables the MCE console where dynamic plugins are supported. Returns true if the
+// multiclusterengine was changed.
+func (r *MultiClusterEngineReconciler) setOpenShiftDefaults(ctx context.Context, m *backplanev1.MultiClusterEngine) (bool, error) {
+	log := log.FromContext(ctx)
+	updateNecessary := false
+
 	// Set and store cluster Ingress domain for use later
 	clusterIngressDomain, err := r.getClusterIngressDomain(ctx, m)
 	if err != nil {
-		return ctrl.Result{}, pkgerrors.Wrapf(err, "failed to detect cluster ingress domain")
+		return false, pkgerrors.Wrapf(err, "failed to detect cluster ingress domain")
 	}
 
 	// Set OCP version as env var, so that charts can render this value
 	os.Setenv("ACM_CLUSTER_INGRESS_DOMAIN", clusterIngressDomain)
 
-	// If OCP 4.10+ then set then enable the MCE console. Else ensure it is disabled
-	currentClusterVersion, err := r.getClusterVersion(ctx)
-	if err != nil {
-		return ctrl.Result{}, pkgerrors.Wrapf(err, "failed to detect clusterversion")
+	// If OCP 4.10+ then set then enable the MCE console. Else ensure it is disabled. Without a
+	// known version the console is left as configured.
+	currentClusterVersion := r.detectClusterVersion(ctx, m)
+	if currentClusterVersion == "" {
+		return false, nil
 	}
 
 	// Set OCP version as env var, so that charts can render this value
@@ -916,7 +1215,7 @@ func (r *MultiClusterEngineReconciler) setDefaults(ctx context.Context, m *backp
 	currentVersion, err := semver.NewVersion(currentClusterVersion)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("Failed to convert currentClusterVersion %s to semver compatible value for comparison", currentClusterVersion))
-		return ctrl.Result{}, err
+		return false, err
 	}
 
 	// -0 allows for prerelease builds to pass the validation.
@@ -924,7 +1223,7 @@ func (r *MultiClusterEngineReconciler) setDefaults(ctx context.Context, m *backp
 	constraint, err := semver.NewConstraint(">= 4.10.0-0")
 	if err != nil {
 		log.Error(err, "Failed to set constraint of minimum supported version for plugins")
-		return ctrl.Result{}, err
+		return false, err
 	}
 
 	if constraint.Check(currentVersion) {
@@ -942,24 +1241,17 @@ func (r *MultiClusterEngineReconciler) setDefaults(ctx context.Context, m *backp
 		}
 	}
 
-	// Apply defaults to server
-	if updateNecessary {
-		log.Info("Setting defaults")
-		err = r.Client.Update(ctx, m)
-		if err != nil {
-			log.Error(err, "Failed to update MultiClusterEngine")
-			return ctrl.Result{}, err
-		}
-		log.Info("MultiClusterEngine successfully updated")
-		return ctrl.Result{Requeue: true}, nil
-	} else {
-		return ctrl.Result{}, nil
-	}
-
+	return updateNecessary, nil
 }
 
 func (r *MultiClusterEngineReconciler) validateNamespace(ctx context.Context, m *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
+	if m.ProtectedTargetNamespace() {
+		msg := fmt.Sprintf("Target namespace %s is a protected namespace. Set the %s annotation to 'true' to deploy to it anyway", m.Spec.TargetNamespace, backplanev1.AnnotationAllowProtectedNamespace)
+		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.ProtectedNamespaceReason, msg))
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, fmt.Errorf("%w: %s is a protected namespace", backplanev1.ErrInvalidNamespace, m.Spec.TargetNamespace)
+	}
+
 	newNs := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: m.Spec.TargetNamespace,
@@ -986,21 +1278,27 @@ func (r *MultiClusterEngineReconciler) validateNamespace(ctx context.Context, m
 }
 
 // validateImagePullSecret returns an error if the namespace in spec.targetNamespace does not have a secret
-// with the name in spec.imagePullSecret.
+// with the name in spec.imagePullSecret, after expanding environment variable references in it.
 func (r *MultiClusterEngineReconciler) validateImagePullSecret(ctx context.Context, m *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
-	if m.Spec.ImagePullSecret == "" {
+	name, err := utils.ImagePullSecretName(m)
+	if err != nil {
+		unresolved := status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason, fmt.Sprintf("Could not resolve imagePullSecret: %s", err.Error()))
+		r.StatusManager.AddCondition(unresolved)
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+	}
+	if name == "" {
 		return ctrl.Result{}, nil
 	}
 
 	pullSecret := &corev1.Secret{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{
-		Name:      m.Spec.ImagePullSecret,
+	err = r.Client.Get(context.TODO(), types.NamespacedName{
+		Name:      name,
 		Namespace: m.Spec.TargetNamespace,
 	}, pullSecret)
 	if apierrors.IsNotFound(err) {
-		missingPullSecret := status.NewCondition(backplanev1.MultiClusterEngineConditionType(backplanev1.MultiClusterEngineProgressing), metav1.ConditionFalse, status.RequirementsNotMetReason, fmt.Sprintf("Could not find imagePullSecret %s in namespace %s", m.Spec.ImagePullSecret, m.Spec.TargetNamespace))
+		missingPullSecret := status.NewCondition(backplanev1.MultiClusterEngineConditionType(backplanev1.MultiClusterEngineProgressing), metav1.ConditionFalse, status.RequirementsNotMetReason, fmt.Sprintf("Could not find imagePullSecret %s in namespace %s", name, m.Spec.TargetNamespace))
 		r.StatusManager.AddCondition(missingPullSecret)
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 	if err != nil {
 		return ctrl.Result{Requeue: true}, err
@@ -1015,8 +1313,8 @@ func (r *MultiClusterEngineReconciler) adoptExistingSubcomponents(ctx context.Co
 
 	log := log.FromContext(ctx)
 
-	cmTemplate := foundation.ClusterManager(mce, r.Images)
-	hiveTemplate := hive.HiveConfig(mce)
+	cmTemplate := r.withPreferredVersion(foundation.ClusterManager(mce, r.Images))
+	hiveTemplate := r.withPreferredVersion(hive.HiveConfig(mce))
 
 	resources := []*unstructured.Unstructured{cmTemplate, hiveTemplate}
 
@@ -1033,11 +1331,21 @@ func (r *MultiClusterEngineReconciler) adoptExistingSubcomponents(ctx context.Co
 			continue
 		}
 
+		if owner := metav1.GetControllerOf(existingResource); owner != nil && owner.UID == mce.GetUID() {
+			// Already adopted
+			continue
+		}
 		if err := ctrl.SetControllerReference(mce, existingResource, r.Scheme); err != nil {
 			return ctrl.Result{}, pkgerrors.Wrapf(err, "Error setting controller reference on resource %s", existingResource.GetName())
 		}
 
-		err = r.Update(ctx, existingResource)
+		// Apply only the owner reference so the rest of the resource is left untouched
+		adoption := &unstructured.Unstructured{}
+		adoption.SetGroupVersionKind(existingResource.GroupVersionKind())
+		adoption.SetName(existingResource.GetName())
+		adoption.SetNamespace(existingResource.GetNamespace())
+		adoption.SetOwnerReferences([]metav1.OwnerReference{*metav1.GetControllerOf(existingResource)})
+		err = r.applyObject(ctx, adoption, adoptionFieldManager)
 		if err != nil {
 			log.Info(fmt.Sprintf("Unable to update existing resource: %+v", err.Error()))
 			return ctrl.Result{}, err
@@ -1063,11 +1371,12 @@ func (r *MultiClusterEngineReconciler) getClusterVersion(ctx context.Context) (s
 		return "", err
 	}
 
-	if len(clusterVersion.Status.History) == 0 {
+	version, err := clusterVersionOf(clusterVersion)
+	if err != nil {
 		log.Error(err, "Failed to detect status in clusterversion.status.history")
 		return "", err
 	}
-	return clusterVersion.Status.History[0].Version, nil
+	return version, nil
 }
 
 //+kubebuilder:rbac:groups="config.openshift.io",resources="ingresses",verbs=get;list;watch
@@ -75,6 +75,7 @@ var _ = Describe("BackplaneConfig controller", func() {
 		clusterManagementAddon *unstructured.Unstructured
 		tests                  testList
 		msaTests               testList
+		ibioTests              testList
 		secondTests            testList
 	)
 
@@ -325,6 +326,26 @@ var _ = Describe("BackplaneConfig controller", func() {
 				Expected:       nil,
 			},
 		}
+		ibioTests = testList{
+			{
+				Name:           "Image-Based-Install-Operator Deployment",
+				NamespacedName: types.NamespacedName{Name: "image-based-install-operator", Namespace: DestinationNamespace},
+				ResourceType:   &appsv1.Deployment{},
+				Expected:       nil,
+			},
+			{
+				Name:           "Image-Based-Install-Operator ServiceAccount",
+				NamespacedName: types.NamespacedName{Name: "image-based-install-operator", Namespace: DestinationNamespace},
+				ResourceType:   &corev1.ServiceAccount{},
+				Expected:       nil,
+			},
+			{
+				Name:           "Image-Based-Install-Operator CRD",
+				NamespacedName: types.NamespacedName{Name: "imageclusterinstalls.extensions.hive.openshift.io"},
+				ResourceType:   &apixv1.CustomResourceDefinition{},
+				Expected:       nil,
+			},
+		}
 		secondTests = testList{
 			{
 				Name:           BackplaneConfigTestName,
@@ -432,6 +453,18 @@ var _ = Describe("BackplaneConfig controller", func() {
 					return k8sClient.Get(ctx, namespacedName, resourceType)
 				}, timeout, interval).Should(Succeed())
 
+				By("ensuring a deleted deployment is recreated without waiting for a requeue")
+				deploymentName := types.NamespacedName{Name: "cluster-curator-controller", Namespace: DestinationNamespace}
+				deployment := &appsv1.Deployment{}
+				Expect(k8sClient.Get(context.Background(), deploymentName, deployment)).To(Succeed())
+				deletedUID := deployment.GetUID()
+				Expect(k8sClient.Delete(context.Background(), deployment)).To(Succeed())
+				Eventually(func(g Gomega) {
+					recreated := &appsv1.Deployment{}
+					g.Expect(k8sClient.Get(context.Background(), deploymentName, recreated)).To(Succeed())
+					g.Expect(recreated.GetUID()).ToNot(Equal(deletedUID))
+				}, requeuePeriod/3, interval).Should(Succeed())
+
 				By("ensuring the trusted-ca-bundle ConfigMap is created")
 				Eventually(func(g Gomega) {
 					ctx := context.Background()
@@ -652,6 +685,62 @@ var _ = Describe("BackplaneConfig controller", func() {
 			})
 		})
 
+		Context("and enable ImageBasedInstallOperator", func() {
+			It("should deploy sub components", func() {
+				By("creating the backplane config")
+				backplaneConfig := &v1.MultiClusterEngine{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "multicluster.openshift.io/v1",
+						Kind:       "MultiClusterEngine",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: BackplaneConfigName,
+					},
+					Spec: v1.MultiClusterEngineSpec{
+						TargetNamespace: DestinationNamespace,
+						Overrides: &v1.Overrides{
+							Components: []v1.ComponentConfig{
+								{
+									Name:    v1.ImageBasedInstallOperator,
+									Enabled: true,
+								},
+							},
+						},
+					},
+				}
+				createCtx := context.Background()
+				Expect(k8sClient.Create(createCtx, backplaneConfig)).Should(Succeed())
+				withIBIOTests := append(tests, ibioTests...)
+				By("ensuring each deployment and config is created")
+				for _, test := range withIBIOTests {
+					By(fmt.Sprintf("ensuring %s is created", test.Name))
+					Eventually(func() bool {
+						ctx := context.Background()
+						err := k8sClient.Get(ctx, test.NamespacedName, test.ResourceType)
+						return err == test.Expected
+					}, timeout, interval).Should(BeTrue())
+				}
+
+				By("ensuring each deployment and config has an owner reference")
+				for _, test := range withIBIOTests {
+					if test.Name == BackplaneConfigTestName {
+						continue // config itself won't have ownerreference
+					}
+					By(fmt.Sprintf("ensuring %s has an ownerreference set", test.Name))
+					Eventually(func(g Gomega) {
+						ctx := context.Background()
+						g.Expect(k8sClient.Get(ctx, test.NamespacedName, test.ResourceType)).To(Succeed())
+						g.Expect(len(test.ResourceType.GetOwnerReferences())).To(
+							Equal(1),
+							fmt.Sprintf("Missing ownerreference on %s", test.Name),
+						)
+						g.Expect(test.ResourceType.GetOwnerReferences()[0].Name).To(Equal(BackplaneConfigName))
+					}, timeout, interval).Should(Succeed())
+				}
+
+			})
+		})
+
 		Context("and components are defined multiple times in overrides", func() {
 			It("should deduplicate the component list in the override", func() {
 				By("creating the backplane config with repeated component")
@@ -0,0 +1,98 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/foundation"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_clusterScopedLabels(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	const addonCRD = "clustermanagementaddons.addon.open-cluster-management.io"
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec: backplanev1.MultiClusterEngineSpec{
+			TargetNamespace: "multicluster-engine",
+			Overrides: &backplanev1.Overrides{
+				Components: []backplanev1.ComponentConfig{{Name: backplanev1.ManagedServiceAccount, Enabled: true}},
+			},
+		},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+		// The managed-serviceaccount CRDs are only applied with the addon API, whose CRD is not
+		// created by the operator
+		&apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: addonCRD}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	want := utils.ClusterScopedLabels(mce.Name)
+	hasLabels := func(labels map[string]string) bool {
+		for k, v := range want {
+			if labels[k] != v {
+				return false
+			}
+		}
+		return true
+	}
+
+	clusterManager := &unstructured.Unstructured{}
+	clusterManager.SetGroupVersionKind(foundation.ClusterManagerGVK)
+	if err := c.Get(ctx, types.NamespacedName{Name: "cluster-manager"}, clusterManager); err != nil {
+		t.Fatalf("failed to get ClusterManager: %v", err)
+	}
+	if !hasLabels(clusterManager.GetLabels()) {
+		t.Errorf("ClusterManager labels = %v, want %v", clusterManager.GetLabels(), want)
+	}
+
+	crds := &apixv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crds); err != nil {
+		t.Fatalf("failed to list CRDs: %v", err)
+	}
+	applied := 0
+	for _, crd := range crds.Items {
+		if crd.Name == addonCRD {
+			continue
+		}
+		applied++
+		if !hasLabels(crd.Labels) {
+			t.Errorf("CRD %s labels = %v, want %v", crd.Name, crd.Labels, want)
+		}
+	}
+	if applied == 0 {
+		t.Fatal("expected CRDs to be applied")
+	}
+}
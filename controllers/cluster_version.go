@@ -0,0 +1,99 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clusterVersionCache remembers the OpenShift version and enabled capabilities last read from the
+// ClusterVersion. A nil cache remembers nothing.
+type clusterVersionCache struct {
+	mu      sync.Mutex
+	version string
+	// capabilities are the enabled capabilities, which are only known once capabilitiesRead is set
+	capabilities     []configv1.ClusterVersionCapability
+	capabilitiesRead bool
+}
+
+func (c *clusterVersionCache) get() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+func (c *clusterVersionCache) set(version string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version = version
+}
+
+// getCapabilities returns the enabled capabilities last read, and whether they were ever read
+func (c *clusterVersionCache) getCapabilities() ([]configv1.ClusterVersionCapability, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.capabilities, c.capabilitiesRead
+}
+
+func (c *clusterVersionCache) setCapabilities(capabilities []configv1.ClusterVersionCapability) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capabilities = capabilities
+	c.capabilitiesRead = true
+}
+
+// clusterVersionOf returns the current OpenShift version of the ClusterVersion
+func clusterVersionOf(clusterVersion *configv1.ClusterVersion) (string, error) {
+	if len(clusterVersion.Status.History) == 0 {
+		return "", fmt.Errorf("clusterversion %s has no version history", clusterVersion.Name)
+	}
+	return clusterVersion.Status.History[0].Version, nil
+}
+
+// detectClusterVersion returns the OpenShift version of the cluster and records it in the status.
+// When the ClusterVersion cannot be read a ClusterVersionUnavailable condition is added and the
+// last detected version is used instead, so that the components are still reconciled. Returns an
+// empty string if no version was ever detected.
+func (r *MultiClusterEngineReconciler) detectClusterVersion(ctx context.Context, mce *backplanev1.MultiClusterEngine) string {
+	version, err := r.getClusterVersion(ctx)
+	if err == nil {
+		r.clusterVersion.set(version)
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineClusterVersionUnavailable)
+		mce.Status.OCPVersion = version
+		return version
+	}
+
+	// The status holds the version detected before the operator restarted
+	last := r.clusterVersion.get()
+	if last == "" {
+		last = mce.Status.OCPVersion
+	}
+	msg := fmt.Sprintf("Unable to read the ClusterVersion: %s", err.Error())
+	if last != "" {
+		msg = fmt.Sprintf("%s. Using the last detected version %s", msg, last)
+	}
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineClusterVersionUnavailable,
+		metav1.ConditionTrue, status.ClusterVersionUnreadableReason, msg))
+	mce.Status.OCPVersion = last
+	return last
+}
@@ -0,0 +1,118 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func clusterVersionCondition(st backplanev1.MultiClusterEngineStatus) *backplanev1.MultiClusterEngineCondition {
+	for i := range st.Conditions {
+		if st.Conditions[i].Type == backplanev1.MultiClusterEngineClusterVersionUnavailable {
+			return &st.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func Test_detectClusterVersion(t *testing.T) {
+	// Read the ClusterVersion from the client rather than the unit test defaults
+	t.Setenv("UNIT_TEST", "")
+
+	ctx := context.Background()
+	cv := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status: configv1.ClusterVersionStatus{
+			History: []configv1.UpdateHistory{{Version: "4.14.3"}},
+		},
+	}
+	mce := &backplanev1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"}}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(cv).Build()
+	r := newTestReconciler(s, c)
+
+	if got := r.detectClusterVersion(ctx, mce); got != "4.14.3" {
+		t.Fatalf("detectClusterVersion() = %q, want 4.14.3", got)
+	}
+	mce.Status = r.StatusManager.ReportStatus(*mce)
+	if mce.Status.OCPVersion != "4.14.3" {
+		t.Errorf("status.ocpVersion = %q, want 4.14.3", mce.Status.OCPVersion)
+	}
+	if cond := clusterVersionCondition(mce.Status); cond != nil {
+		t.Errorf("unexpected %s condition with a readable ClusterVersion: %s", cond.Type, cond.Message)
+	}
+
+	if err := c.Delete(ctx, cv); err != nil {
+		t.Fatalf("failed to delete clusterversion: %v", err)
+	}
+	r.StatusManager = &status.StatusTracker{Client: r.Client}
+
+	if got := r.detectClusterVersion(ctx, mce); got != "4.14.3" {
+		t.Errorf("detectClusterVersion() = %q, want the last detected version 4.14.3", got)
+	}
+	ocpConsole, err := r.CheckConsole(ctx)
+	if err != nil {
+		t.Errorf("CheckConsole() error = %v, want the unreadable ClusterVersion to be tolerated", err)
+	}
+	if !ocpConsole {
+		t.Errorf("CheckConsole() = false, want the console to be assumed present")
+	}
+	mce.Status = r.StatusManager.ReportStatus(*mce)
+	if mce.Status.OCPVersion != "4.14.3" {
+		t.Errorf("status.ocpVersion = %q, want the last detected version 4.14.3", mce.Status.OCPVersion)
+	}
+	cond := clusterVersionCondition(mce.Status)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != status.ClusterVersionUnreadableReason {
+		t.Fatalf("%s condition = %+v, want True with reason %s", backplanev1.MultiClusterEngineClusterVersionUnavailable, cond, status.ClusterVersionUnreadableReason)
+	}
+
+	// Without any previously detected version, nothing is assumed
+	fresh := newTestReconciler(s, c)
+	if got := fresh.detectClusterVersion(ctx, &backplanev1.MultiClusterEngine{}); got != "" {
+		t.Errorf("detectClusterVersion() = %q, want no version", got)
+	}
+}
+
+func Test_CheckConsoleCachedCapabilities(t *testing.T) {
+	// Use the version of the ClusterVersion rather than one recorded by an earlier reconcile
+	t.Setenv("ACM_HUB_OCP_VERSION", "")
+
+	ctx := context.Background()
+	cv := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status: configv1.ClusterVersionStatus{
+			History: []configv1.UpdateHistory{{Version: "4.14.3"}},
+			Capabilities: configv1.ClusterVersionCapabilitiesStatus{
+				EnabledCapabilities: []configv1.ClusterVersionCapability{configv1.ClusterVersionCapabilityBaremetal},
+			},
+		},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(cv).Build()
+	r := newTestReconciler(s, c)
+
+	if ocpConsole, err := r.CheckConsole(ctx); err != nil || ocpConsole {
+		t.Fatalf("CheckConsole() = %t, %v, want false with the Console capability disabled", ocpConsole, err)
+	}
+
+	// The capabilities last read are used while the ClusterVersion cannot be read
+	if err := c.Delete(ctx, cv); err != nil {
+		t.Fatalf("failed to delete clusterversion: %v", err)
+	}
+	if ocpConsole, err := r.CheckConsole(ctx); err != nil || ocpConsole {
+		t.Errorf("CheckConsole() = %t, %v, want the cached capabilities to keep the console disabled", ocpConsole, err)
+	}
+
+	// Without any capabilities ever read, the console is assumed to be present
+	fresh := newTestReconciler(s, c)
+	if ocpConsole, err := fresh.CheckConsole(ctx); err != nil || !ocpConsole {
+		t.Errorf("CheckConsole() = %t, %v, want the console to be assumed present", ocpConsole, err)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stolostron/backplane-operator/pkg/images"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_statusComponentImages(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	r.StatusManager.Reset("")
+
+	overrides := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "image-overrides"},
+		Data: map[string]string{
+			"overrides.json": `[{"image-key": "cluster_curator_controller", "image-remote": "quay.io/override",
+				"image-name": "cluster-curator-controller", "image-digest": "sha256:abc123"}]`,
+		},
+	}
+	imgs, err := images.OverrideImagesWithConfigmap(testImages(), overrides)
+	if err != nil {
+		t.Fatalf("OverrideImagesWithConfigmap() error = %v", err)
+	}
+	r.Images = imgs
+
+	if _, err := r.ensureClusterLifecycle(context.Background(), mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+
+	status := r.StatusManager.ReportStatus(*mce)
+	want := "quay.io/override/cluster-curator-controller@sha256:abc123"
+	if got := status.ComponentImages["cluster-curator-controller"]; got != want {
+		t.Errorf("ComponentImages[cluster-curator-controller] = %q, want %q", got, want)
+	}
+	if got := status.ComponentImages["clusterclaims-controller"]; got != "quay.io/test/test:test" {
+		t.Errorf("ComponentImages[clusterclaims-controller] = %q, want the default image", got)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// validateComponentNames adds an InvalidComponentConfig condition if the component overrides name
+// an unknown component. The webhook rejects these, but resources created while it was disabled or
+// before a component was renamed can still carry them, and a typo would otherwise do nothing.
+func (r *MultiClusterEngineReconciler) validateComponentNames(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) {
+	unknown := backplaneConfig.UnknownComponents()
+	if len(unknown) == 0 {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineInvalidComponentConfig)
+		return
+	}
+
+	message := fmt.Sprintf("Unknown components in overrides will be ignored: %s", strings.Join(unknown, ", "))
+	log.FromContext(ctx).Info(message)
+	r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineInvalidComponentConfig)
+	r.StatusManager.AddCondition(status.NewCondition(
+		backplanev1.MultiClusterEngineInvalidComponentConfig,
+		metav1.ConditionTrue,
+		status.UnknownComponentReason,
+		message,
+	))
+}
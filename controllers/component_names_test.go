@@ -0,0 +1,45 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_validateComponentNames(t *testing.T) {
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	mce.Enable(backplanev1.Discovery)
+	mce.Enable("discvery")
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	ctx := context.Background()
+
+	condition := func() *backplanev1.MultiClusterEngineCondition {
+		for i, c := range r.StatusManager.Conditions {
+			if c.Type == backplanev1.MultiClusterEngineInvalidComponentConfig {
+				return &r.StatusManager.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	r.validateComponentNames(ctx, mce)
+	cond := condition()
+	if cond == nil {
+		t.Fatalf("expected %s condition for an unknown component", backplanev1.MultiClusterEngineInvalidComponentConfig)
+	}
+	if !strings.Contains(cond.Message, "discvery") {
+		t.Errorf("condition message %q does not name the unknown component", cond.Message)
+	}
+
+	mce.Spec.Overrides.Components = mce.Spec.Overrides.Components[:1]
+	r.validateComponentNames(ctx, mce)
+	if condition() != nil {
+		t.Errorf("expected %s condition to be removed once the component is fixed", backplanev1.MultiClusterEngineInvalidComponentConfig)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var componentReady = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "backplane_operator_component_ready",
+		Help: "Whether each component of the multiclusterengine is available (1) or not (0)",
+	},
+	[]string{"component"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(componentReady)
+}
+
+// reportComponentReadiness sets the readiness gauge of each component from the availability the
+// status tracker determined. Components no longer tracked, e.g. after being disabled, are dropped.
+func reportComponentReadiness(components []backplanev1.ComponentCondition) {
+	componentReady.Reset()
+	for _, c := range components {
+		ready := 0.0
+		if c.Available {
+			ready = 1
+		}
+		componentReady.WithLabelValues(c.Name).Set(ready)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_componentReady(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	key := types.NamespacedName{Name: "ocm-controller", Namespace: "multicluster-engine"}
+	if ready := testutil.ToFloat64(componentReady.WithLabelValues(key.Name)); ready != 0 {
+		t.Errorf("%s ready gauge = %v before the deployment is available, want 0", key.Name, ready)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("failed to get %s deployment: %v", key.Name, err)
+	}
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+	}
+	if err := c.Status().Update(ctx, deployment); err != nil {
+		t.Fatalf("failed to update %s deployment status: %v", key.Name, err)
+	}
+
+	// The status tracker sees the deployment become available, and the components it no longer
+	// tracks are dropped from the gauge
+	sm := &status.StatusTracker{Client: c}
+	sm.AddComponent(status.DeploymentStatus{NamespacedName: key})
+	reportComponentReadiness(sm.ReportStatus(*mce).Components)
+	if ready := testutil.ToFloat64(componentReady.WithLabelValues(key.Name)); ready != 1 {
+		t.Errorf("%s ready gauge = %v once the deployment is available, want 1", key.Name, ready)
+	}
+	if n := testutil.CollectAndCount(componentReady); n != 1 {
+		t.Errorf("ready gauge has %d series, want only %s", n, key.Name)
+	}
+}
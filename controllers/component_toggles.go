@@ -0,0 +1,40 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"fmt"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ComponentToggledReason is the reason of the events recording a component being enabled or
+// disabled
+const ComponentToggledReason = "ComponentToggled"
+
+// reportComponentToggles emits an event for each component enabled or disabled since the previous
+// enabled components recorded in the status. Nothing is reported until the components have been
+// recorded once, so an install or an operator upgrade does not report every component.
+func (r *MultiClusterEngineReconciler) reportComponentToggles(previous []string, mce *backplanev1.MultiClusterEngine) {
+	if previous == nil {
+		return
+	}
+	wasEnabled := map[string]bool{}
+	for _, c := range previous {
+		wasEnabled[c] = true
+	}
+	isEnabled := map[string]bool{}
+	for _, c := range mce.Status.EnabledComponents {
+		isEnabled[c] = true
+		if !wasEnabled[c] {
+			r.Recorder.Event(mce, corev1.EventTypeNormal, ComponentToggledReason, fmt.Sprintf("Component %s enabled", c))
+		}
+	}
+	for _, c := range previous {
+		if !isEnabled[c] {
+			r.Recorder.Event(mce, corev1.EventTypeNormal, ComponentToggledReason, fmt.Sprintf("Component %s disabled", c))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reportComponentToggles(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec: backplanev1.MultiClusterEngineSpec{
+			TargetNamespace: "multicluster-engine",
+			Overrides: &backplanev1.Overrides{
+				Components: []backplanev1.ComponentConfig{{Name: backplanev1.Discovery, Enabled: true}},
+			},
+		},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	recorder := r.Recorder.(*record.FakeRecorder)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	reconcile := func() []string {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		return toggleEvents(recorder)
+	}
+	toggle := func(enabled bool) {
+		got := &backplanev1.MultiClusterEngine{}
+		if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatalf("failed to get multiclusterengine: %v", err)
+		}
+		if enabled {
+			got.Enable(backplanev1.Discovery)
+		} else {
+			got.Disable(backplanev1.Discovery)
+		}
+		if err := c.Update(ctx, got); err != nil {
+			t.Fatalf("failed to update multiclusterengine: %v", err)
+		}
+	}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if events := reconcile(); len(events) != 0 {
+			t.Errorf("expected no %s event on install, got %v", ComponentToggledReason, events)
+		}
+	}
+
+	toggle(false)
+	events := reconcile()
+	if len(events) != 1 || !strings.Contains(events[0], "Component discovery disabled") {
+		t.Errorf("expected a single event disabling discovery, got %v", events)
+	}
+	if events := reconcile(); len(events) != 0 {
+		t.Errorf("expected no %s event without a change, got %v", ComponentToggledReason, events)
+	}
+
+	toggle(true)
+	events = reconcile()
+	if len(events) != 1 || !strings.Contains(events[0], "Component discovery enabled") {
+		t.Errorf("expected a single event enabling discovery, got %v", events)
+	}
+
+	// An empty list of enabled components is still recorded, so enabling one afterwards is reported
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	for i := range got.Spec.Overrides.Components {
+		got.Spec.Overrides.Components[i].Enabled = false
+	}
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update multiclusterengine: %v", err)
+	}
+	reconcile()
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	if got.Status.EnabledComponents == nil || len(got.Status.EnabledComponents) != 0 {
+		t.Fatalf("expected an empty list of enabled components, got %#v", got.Status.EnabledComponents)
+	}
+
+	toggle(true)
+	events = reconcile()
+	if len(events) != 1 || !strings.Contains(events[0], "Component discovery enabled") {
+		t.Errorf("expected a single event enabling discovery after all components were disabled, got %v", events)
+	}
+}
+
+// toggleEvents drains the recorded events and returns the ComponentToggled ones
+func toggleEvents(recorder *record.FakeRecorder) []string {
+	events := []string{}
+	for {
+		select {
+		case e := <-recorder.Events:
+			if strings.Contains(e, ComponentToggledReason) {
+				events = append(events, e)
+			}
+		default:
+			return events
+		}
+	}
+}
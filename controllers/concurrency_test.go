@@ -0,0 +1,82 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Test_concurrentReconciles reconciles two MultiClusterEngines at once. Run with -race to catch
+// state shared between reconciles without locking.
+func Test_concurrentReconciles(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	s := newTestScheme()
+	objs := []client.Object{&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}}}
+	names := []string{"mce-a", "mce-b"}
+	for _, name := range names {
+		mce := &backplanev1.MultiClusterEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: name},
+		}
+		objs = append(objs, mce, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	r := newTestReconciler(s, c)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			// The first passes add the finalizer and defaults, later ones apply the components. Both
+			// apply the same cluster-scoped resources, so a pass failing on a conflict is retried
+			// the way the manager would requeue it.
+			for pass := 0; pass < 3 || (errs[i] != nil && pass < 10); pass++ {
+				_, errs[i] = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Errorf("Reconcile(%s) error = %v", name, errs[i])
+		}
+		mce := &backplanev1.MultiClusterEngine{}
+		if err := c.Get(context.Background(), types.NamespacedName{Name: name}, mce); err != nil {
+			t.Fatalf("failed to get %s: %v", name, err)
+		}
+		if len(mce.Status.Components) == 0 {
+			t.Errorf("status of %s reports no components", name)
+		}
+		// A status tracker shared between the reconciles would report each component twice
+		seen := map[string]bool{}
+		for _, component := range mce.Status.Components {
+			key := component.Kind + "/" + component.Name
+			if seen[key] {
+				t.Errorf("status of %s reports %s more than once", name, key)
+			}
+			seen[key] = true
+		}
+	}
+}
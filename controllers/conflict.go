@@ -0,0 +1,77 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// targetNamespace returns the namespace the multiclusterengine deploys to, applying the default
+// before it is set on the spec
+func targetNamespace(mce *backplanev1.MultiClusterEngine) string {
+	if mce.Spec.TargetNamespace == "" {
+		return backplanev1.DefaultTargetNamespace
+	}
+	return mce.Spec.TargetNamespace
+}
+
+// ownsTargetNamespace returns true if a takes precedence over b for a shared target namespace. The
+// multiclusterengine created first wins, with ties broken by name.
+func ownsTargetNamespace(a, b *backplanev1.MultiClusterEngine) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// conflictingMCE returns the name of the multiclusterengine that owns the target namespace of
+// backplaneConfig, or an empty string if backplaneConfig owns it
+func (r *MultiClusterEngineReconciler) conflictingMCE(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) (string, error) {
+	mceList := &backplanev1.MultiClusterEngineList{}
+	if err := r.Client.List(ctx, mceList); err != nil {
+		return "", err
+	}
+	for i := range mceList.Items {
+		other := &mceList.Items[i]
+		// Hosted mode multiclusterengines deploy to the hosted cluster instead
+		if other.Name == backplaneConfig.Name || backplanev1.IsInHostedMode(other) || targetNamespace(other) != targetNamespace(backplaneConfig) {
+			continue
+		}
+		if ownsTargetNamespace(other, backplaneConfig) {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// reconcileConflicting marks a multiclusterengine whose target namespace is owned by another one as
+// Conflicting. Nothing is applied for it, and deleting it does not finalize the resources of the
+// owner.
+func (r *MultiClusterEngineReconciler) reconcileConflicting(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine, owner string) (ctrl.Result, error) {
+	if backplaneConfig.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(backplaneConfig, backplaneFinalizer) {
+			controllerutil.RemoveFinalizer(backplaneConfig, backplaneFinalizer)
+			if err := r.Client.Update(ctx, backplaneConfig); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	msg := fmt.Sprintf("MultiClusterEngine %s already targets namespace %s", owner, targetNamespace(backplaneConfig))
+	log.FromContext(ctx).Info(msg)
+	r.Recorder.Event(backplaneConfig, corev1.EventTypeWarning, status.TargetNamespaceConflictReason, msg)
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineConflicting, metav1.ConditionTrue, status.TargetNamespaceConflictReason, msg))
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.TargetNamespaceConflictReason, msg))
+	return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+}
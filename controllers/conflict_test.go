@@ -0,0 +1,99 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_reconcileConflictingTargetNamespace(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	created := time.Now().Add(-time.Hour)
+	first := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "first", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	second := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "second", CreationTimestamp: metav1.NewTime(created.Add(time.Minute))},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		first,
+		second,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: second.Name}}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(ctx, types.NamespacedName{Name: second.Name}, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	flagged := false
+	for _, cond := range got.Status.Conditions {
+		if cond.Type == backplanev1.MultiClusterEngineConflicting {
+			flagged = cond.Status == metav1.ConditionTrue && cond.Reason == status.TargetNamespaceConflictReason &&
+				strings.Contains(cond.Message, first.Name)
+		}
+	}
+	if !flagged {
+		t.Errorf("expected %s to be flagged as conflicting with %s, got conditions %v", second.Name, first.Name, got.Status.Conditions)
+	}
+	if controllerutil.ContainsFinalizer(got, backplaneFinalizer) {
+		t.Error("expected no finalizer on the conflicting multiclusterengine")
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments); err != nil {
+		t.Fatalf("failed to list deployments: %v", err)
+	}
+	if len(deployments.Items) > 0 {
+		t.Errorf("expected nothing to be applied for the conflicting multiclusterengine, got %d deployments", len(deployments.Items))
+	}
+
+	// The multiclusterengine created first keeps reconciling
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: first.Name}}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: first.Name}, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	for _, cond := range got.Status.Conditions {
+		if cond.Type == backplanev1.MultiClusterEngineConflicting {
+			t.Errorf("expected %s not to be flagged as conflicting", first.Name)
+		}
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "ocm-controller", Namespace: "multicluster-engine"}, &appsv1.Deployment{}); err != nil {
+		t.Errorf("failed to get ocm-controller deployment: %v", err)
+	}
+}
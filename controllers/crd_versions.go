@@ -0,0 +1,139 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	renderer "github.com/stolostron/backplane-operator/pkg/rendering"
+	"github.com/stolostron/backplane-operator/pkg/toggle"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// managedCRDDirs are the directories of the CRDs installed by the operator
+var managedCRDDirs = []string{
+	operatorCRDsDir,
+	toggle.ManagedServiceAccountCRDPath,
+	toggle.ImageBasedInstallOperatorCRDPath,
+}
+
+// pruneCRDVersions removes the versions of the installed CRDs that are no longer in their bundled
+// definition. A pruned version is first dropped from status.storedVersions, which is only done when
+// no objects of the CRD exist, as they may still be stored in that version. CRDs that are not
+// installed yet are left to the apply that creates them.
+func (r *MultiClusterEngineReconciler) pruneCRDVersions(ctx context.Context) error {
+	for _, dir := range managedCRDDirs {
+		templates, errs := renderer.RenderCRDs(dir)
+		if len(errs) > 0 {
+			return fmt.Errorf("error rendering CRDs in %s: %v", dir, errs)
+		}
+		for _, template := range templates {
+			if template.GetKind() != "CustomResourceDefinition" {
+				continue
+			}
+			if err := r.pruneCRDVersionsOf(ctx, template); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *MultiClusterEngineReconciler) pruneCRDVersionsOf(ctx context.Context, template *unstructured.Unstructured) error {
+	log := log.FromContext(ctx)
+
+	bundled := &apixv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, bundled); err != nil {
+		return fmt.Errorf("unable to convert CRD %s: %w", template.GetName(), err)
+	}
+	shipped := map[string]bool{}
+	for _, v := range bundled.Spec.Versions {
+		shipped[v.Name] = true
+	}
+
+	crd := &apixv1.CustomResourceDefinition{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: bundled.Name}, crd)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	obsolete := []string{}
+	for _, v := range crd.Spec.Versions {
+		if !shipped[v.Name] {
+			obsolete = append(obsolete, v.Name)
+		}
+	}
+	if len(obsolete) == 0 {
+		return nil
+	}
+
+	storedObsolete := false
+	for _, v := range crd.Status.StoredVersions {
+		if !shipped[v] {
+			storedObsolete = true
+		}
+	}
+	if storedObsolete {
+		inUse, err := r.crdInUse(ctx, crd)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			log.Info(fmt.Sprintf("Not pruning versions %v of CRD %s: objects may still be stored in them", obsolete, crd.Name))
+			return nil
+		}
+		storedVersions := []string{}
+		for _, v := range crd.Status.StoredVersions {
+			if shipped[v] {
+				storedVersions = append(storedVersions, v)
+			}
+		}
+		crd.Status.StoredVersions = storedVersions
+		if err := r.Client.Status().Update(ctx, crd); err != nil {
+			return fmt.Errorf("unable to prune stored versions of CRD %s: %w", crd.Name, err)
+		}
+	}
+
+	log.Info(fmt.Sprintf("Pruning versions %v of CRD %s that are no longer shipped", obsolete, crd.Name))
+	crd.Spec.Versions = bundled.Spec.Versions
+	if err := r.Client.Update(ctx, crd); err != nil {
+		return fmt.Errorf("unable to prune versions of CRD %s: %w", crd.Name, err)
+	}
+	return nil
+}
+
+// crdInUse returns true if any object of the installed CRD exists. Objects are listed through its
+// current storage version, which the cluster is known to serve.
+func (r *MultiClusterEngineReconciler) crdInUse(ctx context.Context, crd *apixv1.CustomResourceDefinition) (bool, error) {
+	version := ""
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			version = v.Name
+		}
+	}
+	if version == "" {
+		return true, nil
+	}
+	listKind := crd.Spec.Names.ListKind
+	if listKind == "" {
+		listKind = crd.Spec.Names.Kind + "List"
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: listKind})
+	if err := r.Client.List(ctx, list, client.Limit(1)); err != nil {
+		return false, fmt.Errorf("unable to list objects of CRD %s: %w", crd.Name, err)
+	}
+	return len(list.Items) > 0, nil
+}
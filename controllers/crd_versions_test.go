@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// staleClusterManagerCRD returns the clustermanagers CRD as left by an older release that also
+// served v1alpha1
+func staleClusterManagerCRD() *apixv1.CustomResourceDefinition {
+	schema := &apixv1.CustomResourceValidation{OpenAPIV3Schema: &apixv1.JSONSchemaProps{Type: "object"}}
+	return &apixv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "clustermanagers.operator.open-cluster-management.io"},
+		Spec: apixv1.CustomResourceDefinitionSpec{
+			Group: "operator.open-cluster-management.io",
+			Names: apixv1.CustomResourceDefinitionNames{Kind: "ClusterManager", ListKind: "ClusterManagerList", Plural: "clustermanagers"},
+			Scope: apixv1.ClusterScoped,
+			Versions: []apixv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: false, Schema: schema},
+				{Name: "v1", Served: true, Storage: true, Schema: schema},
+			},
+		},
+		Status: apixv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1", "v1"}},
+	}
+}
+
+func Test_pruneCRDVersions(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	ctx := context.Background()
+	s := newTestScheme()
+
+	t.Run("unused obsolete version is pruned", func(t *testing.T) {
+		crd := staleClusterManagerCRD()
+		c := fake.NewClientBuilder().WithScheme(s).WithObjects(crd).Build()
+		r := newTestReconciler(s, c)
+
+		if err := r.pruneCRDVersions(ctx); err != nil {
+			t.Fatalf("pruneCRDVersions() error = %v", err)
+		}
+
+		got := &apixv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, types.NamespacedName{Name: crd.Name}, got); err != nil {
+			t.Fatalf("failed to get CRD: %v", err)
+		}
+		versions := []string{}
+		for _, v := range got.Spec.Versions {
+			versions = append(versions, v.Name)
+		}
+		if !reflect.DeepEqual(versions, []string{"v1"}) {
+			t.Errorf("spec.versions = %v, want [v1]", versions)
+		}
+		if !reflect.DeepEqual(got.Status.StoredVersions, []string{"v1"}) {
+			t.Errorf("status.storedVersions = %v, want [v1]", got.Status.StoredVersions)
+		}
+	})
+
+	t.Run("obsolete version is kept while objects exist", func(t *testing.T) {
+		crd := staleClusterManagerCRD()
+		cm := &unstructured.Unstructured{}
+		cm.SetAPIVersion("operator.open-cluster-management.io/v1")
+		cm.SetKind("ClusterManager")
+		cm.SetName("cluster-manager")
+		c := fake.NewClientBuilder().WithScheme(s).WithObjects(crd, cm).Build()
+		r := newTestReconciler(s, c)
+
+		if err := r.pruneCRDVersions(ctx); err != nil {
+			t.Fatalf("pruneCRDVersions() error = %v", err)
+		}
+
+		got := &apixv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, types.NamespacedName{Name: crd.Name}, got); err != nil {
+			t.Fatalf("failed to get CRD: %v", err)
+		}
+		if len(got.Spec.Versions) != 2 || len(got.Status.StoredVersions) != 2 {
+			t.Errorf("versions = %v, storedVersions = %v, want both versions kept", got.Spec.Versions, got.Status.StoredVersions)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	renderer "github.com/stolostron/backplane-operator/pkg/rendering"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/toggle"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// componentCRDs maps a component to the directory of the CRDs its resources depend on
+var componentCRDs = map[string]string{
+	backplanev1.ManagedServiceAccount: toggle.ManagedServiceAccountCRDPath,
+	backplanev1.ClusterManager:        toggle.ClusterManagerCRDPath,
+	backplanev1.Hive:                  toggle.HiveCRDPath,
+	backplanev1.Discovery:             toggle.DiscoveryCRDPath,
+}
+
+// crdsEstablished returns true if every installed CRD of the component is established. CRDs that
+// are not installed yet are left to the apply that creates them. CRDs that are not established are
+// recorded for the CRDsEstablished condition.
+func (r *MultiClusterEngineReconciler) crdsEstablished(ctx context.Context, component string) (bool, error) {
+	dir, ok := componentCRDs[component]
+	if !ok {
+		return true, nil
+	}
+	crds, errs := renderer.RenderCRDs(dir)
+	if len(errs) > 0 {
+		return false, fmt.Errorf("error rendering CRDs of %s: %v", component, errs)
+	}
+
+	pending := []string{}
+	for _, template := range crds {
+		crd := &apixv1.CustomResourceDefinition{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: template.GetName()}, crd)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if !isEstablished(crd) {
+			pending = append(pending, crd.Name)
+		}
+	}
+	if len(pending) > 0 {
+		log.FromContext(ctx).Info(fmt.Sprintf("Waiting for CRDs of %s to be established: %s", component, strings.Join(pending, ", ")))
+		r.pendingCRDs = append(r.pendingCRDs, pending...)
+		return false, nil
+	}
+	return true, nil
+}
+
+func isEstablished(crd *apixv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apixv1.Established {
+			return c.Status == apixv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// reportCRDsEstablished sets the CRDsEstablished condition from the CRDs found not established
+// while ensuring the components
+func (r *MultiClusterEngineReconciler) reportCRDsEstablished() {
+	if len(r.pendingCRDs) == 0 {
+		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineCRDsEstablished,
+			metav1.ConditionTrue, status.CRDsEstablishedReason, "All component CRDs are established"))
+		return
+	}
+	pending := append([]string{}, r.pendingCRDs...)
+	sort.Strings(pending)
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineCRDsEstablished,
+		metav1.ConditionFalse, status.CRDsNotEstablishedReason,
+		fmt.Sprintf("Waiting for CRDs to be established: %s", strings.Join(pending, ", "))))
+}
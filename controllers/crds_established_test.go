@@ -0,0 +1,101 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_crdsEstablished(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	crd := &apixv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "clustermanagers.operator.open-cluster-management.io"},
+		Status: apixv1.CustomResourceDefinitionStatus{
+			Conditions: []apixv1.CustomResourceDefinitionCondition{
+				{Type: apixv1.NamesAccepted, Status: apixv1.ConditionTrue},
+				{Type: apixv1.Established, Status: apixv1.ConditionFalse},
+			},
+		},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		crd,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+	deploymentKey := types.NamespacedName{Name: "cluster-manager", Namespace: "multicluster-engine"}
+
+	reconcile := func() *backplanev1.MultiClusterEngineCondition {
+		t.Helper()
+		// The first passes add the finalizer and defaults, later ones apply the components
+		for pass := 0; pass < 3; pass++ {
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+		}
+		got := &backplanev1.MultiClusterEngine{}
+		if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatalf("failed to get multiclusterengine: %v", err)
+		}
+		for i := range got.Status.Conditions {
+			if got.Status.Conditions[i].Type == backplanev1.MultiClusterEngineCRDsEstablished {
+				return &got.Status.Conditions[i]
+			}
+		}
+		t.Fatalf("CRDsEstablished condition not reported")
+		return nil
+	}
+
+	cond := reconcile()
+	if cond.Status != metav1.ConditionFalse || cond.Reason != status.CRDsNotEstablishedReason || !strings.Contains(cond.Message, crd.Name) {
+		t.Errorf("CRDsEstablished condition = %v, want false naming %s", cond, crd.Name)
+	}
+	if err := c.Get(ctx, deploymentKey, &appsv1.Deployment{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected cluster-manager to wait for its CRD, got error %v", err)
+	}
+	// Components that do not depend on the CRD are applied
+	if err := c.Get(ctx, types.NamespacedName{Name: "ocm-controller", Namespace: "multicluster-engine"}, &appsv1.Deployment{}); err != nil {
+		t.Errorf("failed to get ocm-controller deployment: %v", err)
+	}
+
+	crd.Status.Conditions[1].Status = apixv1.ConditionTrue
+	if err := c.Update(ctx, crd); err != nil {
+		t.Fatalf("failed to update CRD: %v", err)
+	}
+	cond = reconcile()
+	if cond.Status != metav1.ConditionTrue || cond.Reason != status.CRDsEstablishedReason {
+		t.Errorf("CRDsEstablished condition = %v, want true", cond)
+	}
+	if err := c.Get(ctx, deploymentKey, &appsv1.Deployment{}); err != nil {
+		t.Errorf("failed to get cluster-manager deployment: %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultComponentDependencies declares, for a component, the components that must report Available
+// before it is applied. Components without dependencies are applied in parallel.
+var defaultComponentDependencies = map[string][]string{
+	backplanev1.Discovery: {backplanev1.ClusterManager},
+	// The local-cluster ManagedCluster is served and registered by the cluster manager
+	backplanev1.LocalCluster: {backplanev1.ClusterManager},
+}
+
+// defaultComponentApplyWorkers is the number of independent components applied at once when the
+// reconciler does not set ComponentApplyWorkers
+const defaultComponentApplyWorkers = 4
+
+func (r *MultiClusterEngineReconciler) componentApplyWorkers() int {
+	if r.ComponentApplyWorkers < 1 {
+		return defaultComponentApplyWorkers
+	}
+	return r.ComponentApplyWorkers
+}
+
+func (r *MultiClusterEngineReconciler) componentDependencies() map[string][]string {
+	if r.ComponentDependencies == nil {
+		return defaultComponentDependencies
+	}
+	return r.ComponentDependencies
+}
+
+// componentWaves groups components into waves that are applied one after another. A component is
+// placed in the wave after the last of its dependencies, so the components of a wave do not depend
+// on each other. Components keep their given order within a wave. The dependencies must not
+// contain a cycle.
+func componentWaves(components []string, dependencies map[string][]string) [][]string {
+	included := map[string]bool{}
+	for _, c := range components {
+		included[c] = true
+	}
+
+	depth := map[string]int{}
+	var waveOf func(component string) int
+	waveOf = func(component string) int {
+		if d, ok := depth[component]; ok {
+			return d
+		}
+		d := 0
+		for _, dep := range dependencies[component] {
+			if included[dep] && waveOf(dep)+1 > d {
+				d = waveOf(dep) + 1
+			}
+		}
+		depth[component] = d
+		return d
+	}
+
+	waves := [][]string{}
+	for _, c := range components {
+		d := waveOf(c)
+		for len(waves) <= d {
+			waves = append(waves, []string{})
+		}
+		waves[d] = append(waves[d], c)
+	}
+	return waves
+}
+
+// componentDependents inverts the dependencies, returning for each component the components that
+// depend on it. Passed to componentWaves, it orders components for removal: dependents are removed
+// in a wave before their prerequisites.
+func componentDependents(dependencies map[string][]string) map[string][]string {
+	dependents := map[string][]string{}
+	components := []string{}
+	for c := range dependencies {
+		components = append(components, c)
+	}
+	sort.Strings(components)
+	for _, c := range components {
+		for _, dep := range dependencies[c] {
+			dependents[dep] = append(dependents[dep], c)
+		}
+	}
+	return dependents
+}
+
+// removingDependent returns a dependent of the component whose removal has not completed, or an
+// empty string if there is none
+func removingDependent(component string, dependents map[string][]string, removing map[string]bool) string {
+	for _, d := range dependents[component] {
+		if removing[d] {
+			return d
+		}
+	}
+	return ""
+}
+
+// componentHealth returns the status reporters whose availability indicates a component is healthy.
+// Any component used as a prerequisite in the component dependencies must be listed here.
+func componentHealth(component string, backplaneConfig *backplanev1.MultiClusterEngine) []status.StatusReporter {
+	switch component {
+	case backplanev1.ClusterManager:
+		return []status.StatusReporter{
+			status.DeploymentStatus{NamespacedName: types.NamespacedName{Name: "cluster-manager", Namespace: backplaneConfig.Spec.TargetNamespace}},
+			status.ClusterManagerStatus{NamespacedName: types.NamespacedName{Name: "cluster-manager"}},
+		}
+	case backplanev1.ServerFoundation:
+		reporters := []status.StatusReporter{
+			status.DeploymentStatus{NamespacedName: types.NamespacedName{Name: "ocm-controller", Namespace: backplaneConfig.Spec.TargetNamespace}},
+			status.DeploymentStatus{NamespacedName: types.NamespacedName{Name: "ocm-webhook", Namespace: backplaneConfig.Spec.TargetNamespace}},
+		}
+		if backplaneConfig.Enabled(backplanev1.ServerFoundationProxy) {
+			reporters = append(reporters, status.DeploymentStatus{NamespacedName: types.NamespacedName{Name: proxyServerName, Namespace: backplaneConfig.Spec.TargetNamespace}})
+		}
+		return reporters
+	case backplanev1.Hive:
+		return []status.StatusReporter{
+			status.DeploymentStatus{NamespacedName: types.NamespacedName{Name: "hive-operator", Namespace: backplaneConfig.Spec.TargetNamespace}},
+		}
+	}
+	return nil
+}
+
+// validateComponentDependencies returns an error if the dependency graph contains a cycle
+func validateComponentDependencies(dependencies map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+
+	var visit func(component string, path []string) error
+	visit = func(component string, path []string) error {
+		switch state[component] {
+		case visiting:
+			return fmt.Errorf("component dependency cycle detected: %s", strings.Join(append(path, component), " -> "))
+		case visited:
+			return nil
+		}
+		state[component] = visiting
+		for _, dep := range dependencies[component] {
+			if err := visit(dep, append(path, component)); err != nil {
+				return err
+			}
+		}
+		state[component] = visited
+		return nil
+	}
+
+	// Iterate in a stable order so the reported cycle is deterministic
+	components := []string{}
+	for c := range dependencies {
+		components = append(components, c)
+	}
+	sort.Strings(components)
+	for _, c := range components {
+		if err := visit(c, []string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureWithPrerequisites calls ensure for the component once its CRDs are established and all of
+// its enabled prerequisites report Available. Until then the component is left untouched and a
+// requeue is returned.
+func (r *MultiClusterEngineReconciler) ensureWithPrerequisites(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine, component string,
+	ensure func(context.Context, *backplanev1.MultiClusterEngine) (ctrl.Result, error)) (ctrl.Result, error) {
+	// Tag everything logged while ensuring this component with its name
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("component", component))
+	log := log.FromContext(ctx)
+
+	established, err := r.crdsEstablished(ctx, component)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !established {
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
+	if utils.IsMaintenanceMode(backplaneConfig) {
+		// Prerequisites are being scaled down as well, so there is nothing to wait for
+		return ensure(ctx, backplaneConfig)
+	}
+
+	for _, dep := range r.componentDependencies()[component] {
+		if !backplaneConfig.Enabled(dep) {
+			// A disabled prerequisite will never become available
+			continue
+		}
+		reporters := componentHealth(dep, backplaneConfig)
+		if len(reporters) == 0 {
+			return ctrl.Result{}, fmt.Errorf("no health check defined for %s, a prerequisite of %s", dep, component)
+		}
+		if !r.StatusManager.ComponentsAvailable(reporters...) {
+			log.Info(fmt.Sprintf("Waiting for %s to be available before applying %s", dep, component))
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+		}
+	}
+
+	return ensure(ctx, backplaneConfig)
+}
@@ -0,0 +1,221 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// deleteOrderClient records the Deployments deleted through it and fails the deletion of those
+// named in failing
+type deleteOrderClient struct {
+	client.Client
+	mu      *sync.Mutex
+	deleted *[]string
+	failing map[string]bool
+}
+
+func (dc deleteOrderClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if obj.GetObjectKind().GroupVersionKind().Kind != "Deployment" {
+		return dc.Client.Delete(ctx, obj, opts...)
+	}
+	if dc.failing[obj.GetName()] {
+		return errors.New("injected delete failure")
+	}
+	dc.mu.Lock()
+	*dc.deleted = append(*dc.deleted, obj.GetName())
+	dc.mu.Unlock()
+	return dc.Client.Delete(ctx, obj, opts...)
+}
+
+func Test_validateComponentDependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies map[string][]string
+		wantErr      bool
+	}{
+		{
+			name:         "no dependencies",
+			dependencies: map[string][]string{},
+			wantErr:      false,
+		},
+		{
+			name:         "default dependencies",
+			dependencies: defaultComponentDependencies,
+			wantErr:      false,
+		},
+		{
+			name: "chain",
+			dependencies: map[string][]string{
+				backplanev1.Discovery:      {backplanev1.ClusterManager},
+				backplanev1.ClusterManager: {backplanev1.ServerFoundation},
+			},
+			wantErr: false,
+		},
+		{
+			name: "self cycle",
+			dependencies: map[string][]string{
+				backplanev1.Discovery: {backplanev1.Discovery},
+			},
+			wantErr: true,
+		},
+		{
+			name: "indirect cycle",
+			dependencies: map[string][]string{
+				backplanev1.Discovery:        {backplanev1.ClusterManager},
+				backplanev1.ClusterManager:   {backplanev1.ServerFoundation},
+				backplanev1.ServerFoundation: {backplanev1.Discovery},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateComponentDependencies(tt.dependencies); (err != nil) != tt.wantErr {
+				t.Errorf("validateComponentDependencies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ensureWithPrerequisites(t *testing.T) {
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec: backplanev1.MultiClusterEngineSpec{
+			TargetNamespace: "multicluster-engine",
+			Overrides: &backplanev1.Overrides{
+				Components: []backplanev1.ComponentConfig{
+					{Name: backplanev1.Discovery, Enabled: true},
+					{Name: backplanev1.Hive, Enabled: true},
+				},
+			},
+		},
+	}
+	hive := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "hive-operator", Namespace: "multicluster-engine"},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+	s := newTestScheme()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce, hive).Build())
+	r.ComponentDependencies = map[string][]string{backplanev1.Discovery: {backplanev1.Hive}}
+	ctx := context.Background()
+
+	applied := false
+	ensure := func(context.Context, *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+		applied = true
+		return ctrl.Result{}, nil
+	}
+
+	result, err := r.ensureWithPrerequisites(ctx, mce, backplanev1.Discovery, ensure)
+	if err != nil {
+		t.Fatalf("ensureWithPrerequisites() error = %v", err)
+	}
+	if applied {
+		t.Errorf("expected %s not to be applied before %s is available", backplanev1.Discovery, backplanev1.Hive)
+	}
+	if result.RequeueAfter != requeuePeriod {
+		t.Errorf("expected requeue while waiting for prerequisite, got %#v", result)
+	}
+
+	hive.Status.Conditions[0].Status = corev1.ConditionTrue
+	if err := r.Client.Status().Update(ctx, hive); err != nil {
+		t.Fatalf("failed to update deployment status: %v", err)
+	}
+
+	_, err = r.ensureWithPrerequisites(ctx, mce, backplanev1.Discovery, ensure)
+	if err != nil {
+		t.Fatalf("ensureWithPrerequisites() error = %v", err)
+	}
+	if !applied {
+		t.Errorf("expected %s to be applied once %s is available", backplanev1.Discovery, backplanev1.Hive)
+	}
+}
+
+func Test_componentDependents(t *testing.T) {
+	got := componentDependents(map[string][]string{
+		backplanev1.Discovery:    {backplanev1.ClusterManager},
+		backplanev1.LocalCluster: {backplanev1.ClusterManager},
+		backplanev1.Hive:         {backplanev1.ServerFoundation},
+	})
+	want := map[string][]string{
+		backplanev1.ClusterManager:   {backplanev1.Discovery, backplanev1.LocalCluster},
+		backplanev1.ServerFoundation: {backplanev1.Hive},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("componentDependents() = %v, want %v", got, want)
+	}
+}
+
+func Test_ensureToggleableComponentsRemovalOrder(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+
+	ctx := context.Background()
+	mce := newMonitoringTestMCE()
+	mce.Enable(backplanev1.Discovery)
+	mce.Enable(backplanev1.ClusterManager)
+	s := newTestScheme()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	r.Images = testImages()
+	r.StatusManager.Reset("")
+	if _, err := r.ensureToggleableComponents(ctx, mce); err != nil {
+		t.Fatalf("ensureToggleableComponents() error = %v", err)
+	}
+
+	// Discovery depends on the cluster manager, so it is removed first
+	r.ComponentDependencies = defaultComponentDependencies
+	mce.Disable(backplanev1.Discovery)
+	mce.Disable(backplanev1.ClusterManager)
+	deleted := []string{}
+	dc := deleteOrderClient{Client: r.Client, mu: &sync.Mutex{}, deleted: &deleted,
+		failing: map[string]bool{"discovery-operator": true}}
+	r.Client = dc
+
+	// The cluster manager is kept while the removal of discovery has not completed
+	result, _ := r.ensureToggleableComponents(ctx, mce)
+	if result.RequeueAfter == 0 {
+		t.Errorf("expected a requeue while waiting for %s to be removed, got %#v", backplanev1.Discovery, result)
+	}
+	for _, name := range deleted {
+		if name == "cluster-manager" {
+			t.Fatalf("expected cluster-manager to be kept until discovery-operator is removed, deleted %v", deleted)
+		}
+	}
+
+	delete(dc.failing, "discovery-operator")
+	if _, err := r.ensureToggleableComponents(ctx, mce); err != nil {
+		t.Fatalf("ensureToggleableComponents() error = %v", err)
+	}
+	order := map[string]int{}
+	for i, name := range deleted {
+		order[name] = i
+	}
+	discovery, ok := order["discovery-operator"]
+	if !ok {
+		t.Fatalf("expected discovery-operator to be deleted, deleted %v", deleted)
+	}
+	clusterManager, ok := order["cluster-manager"]
+	if !ok {
+		t.Fatalf("expected cluster-manager to be deleted, deleted %v", deleted)
+	}
+	if clusterManager < discovery {
+		t.Errorf("expected discovery-operator to be deleted before cluster-manager, deleted %v", deleted)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DeprecatedComponentsEventReason is the reason of the event emitted when the overrides reference
+// deprecated components
+const DeprecatedComponentsEventReason = "DeprecatedComponents"
+
+// warnDeprecatedComponents emits a warning event when the component overrides start referencing
+// deprecated components. The names are listed in the status, so the event is only repeated when
+// that list changes.
+func (r *MultiClusterEngineReconciler) warnDeprecatedComponents(backplaneConfig *backplanev1.MultiClusterEngine) {
+	deprecated := backplaneConfig.DeprecatedComponentNames()
+	if len(deprecated) == 0 || reflect.DeepEqual(deprecated, backplaneConfig.Status.DeprecatedComponents) {
+		return
+	}
+
+	notes := []string{}
+	for _, name := range deprecated {
+		notes = append(notes, fmt.Sprintf("%s (%s)", name, backplanev1.DeprecatedComponents[name]))
+	}
+	r.Recorder.Eventf(backplaneConfig, corev1.EventTypeWarning, DeprecatedComponentsEventReason,
+		"Component overrides reference deprecated components and can be removed: %s", strings.Join(notes, ", "))
+}
@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_deprecatedComponents(t *testing.T) {
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	mce.Enable(backplanev1.Discovery)
+	mce.Enable(backplanev1.ImageBasedInstallOperatorPreview)
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	recorder := r.Recorder.(*record.FakeRecorder)
+	r.StatusManager.Reset("")
+
+	r.validateComponentNames(context.Background(), mce)
+	for _, c := range r.StatusManager.Conditions {
+		if c.Type == backplanev1.MultiClusterEngineInvalidComponentConfig {
+			t.Errorf("deprecated component reported as unknown: %s", c.Message)
+		}
+	}
+
+	r.warnDeprecatedComponents(mce)
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, DeprecatedComponentsEventReason) || !strings.Contains(event, backplanev1.ImageBasedInstallOperatorPreview) {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected an event for the deprecated component")
+	}
+
+	mce.Status = r.StatusManager.ReportStatus(*mce)
+	if want := []string{backplanev1.ImageBasedInstallOperatorPreview}; !reflect.DeepEqual(mce.Status.DeprecatedComponents, want) {
+		t.Errorf("Status.DeprecatedComponents = %v, want %v", mce.Status.DeprecatedComponents, want)
+	}
+
+	// The event is not repeated while the status already lists the component
+	r.warnDeprecatedComponents(mce)
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("unexpected repeated event %q", event)
+	default:
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/version"
+)
+
+// desiredStateHash identifies everything the resources of the multiclusterengine are rendered from:
+// its spec and annotations, the resolved images and the operator version
+func desiredStateHash(mce *backplanev1.MultiClusterEngine, images map[string]string) string {
+	b, err := json.Marshal(struct {
+		Spec        backplanev1.MultiClusterEngineSpec
+		Annotations map[string]string
+		Images      map[string]string
+		Version     string
+	}{mce.Spec, mce.GetAnnotations(), images, version.Version})
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+// desiredStateUnchanged returns true if the desired state identified by hash was fully applied by an
+// earlier reconcile and the components have been available since
+func desiredStateUnchanged(mce *backplanev1.MultiClusterEngine, hash string) bool {
+	return hash != "" && mce.Status.DesiredStateHash == hash &&
+		mce.Status.Phase == backplanev1.MultiClusterEnginePhaseAvailable
+}
@@ -0,0 +1,151 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// countingClient counts the apply patches sent through it. Components are applied in parallel, so
+// the count is updated atomically.
+type countingClient struct {
+	client.Client
+	applies *int32
+}
+
+func (cc countingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() == types.ApplyPatchType {
+		atomic.AddInt32(cc.applies, 1)
+	}
+	return cc.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func Test_reconcileUnchangedDesiredState(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	var applies int32
+	r.Client = countingClient{Client: r.Client, applies: &applies}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		reconcile()
+	}
+	got := &backplanev1.MultiClusterEngine{}
+	// The fake client does not run the deployments, so their availability is reported directly
+	markAvailable := func() {
+		t.Helper()
+		if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatalf("failed to get multiclusterengine: %v", err)
+		}
+		if got.Status.DesiredStateHash == "" {
+			t.Fatal("desiredStateHash not recorded after a full reconcile")
+		}
+		got.Status.Phase = backplanev1.MultiClusterEnginePhaseAvailable
+		if err := c.Status().Update(ctx, got); err != nil {
+			t.Fatalf("failed to update multiclusterengine status: %v", err)
+		}
+	}
+
+	markAvailable()
+	atomic.StoreInt32(&applies, 0)
+	reconcile()
+	if n := atomic.LoadInt32(&applies); n != 0 {
+		t.Errorf("unchanged reconcile applied %d resources, want none", n)
+	}
+
+	// A resource modified outside the operator is still restored
+	markAvailable()
+	drifted := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "ocm-controller", Namespace: "multicluster-engine"}, drifted); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	drifted.Spec.Template.Spec.ServiceAccountName = "modified"
+	if err := c.Update(ctx, drifted); err != nil {
+		t.Fatalf("failed to modify deployment: %v", err)
+	}
+	atomic.StoreInt32(&applies, 0)
+	reconcile()
+	if n := atomic.LoadInt32(&applies); n != 1 {
+		t.Errorf("reconcile after drift applied %d resources, want 1", n)
+	}
+
+	// Annotations and the controller reference removed outside the operator are restored too
+	markAvailable()
+	service := &corev1.Service{}
+	serviceKey := types.NamespacedName{Name: "ocm-webhook", Namespace: "multicluster-engine"}
+	if err := c.Get(ctx, serviceKey, service); err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	annotations := service.GetAnnotations()
+	if len(annotations) == 0 {
+		t.Fatal("expected the ocm-webhook service to be annotated")
+	}
+	service.SetAnnotations(nil)
+	service.SetOwnerReferences(nil)
+	if err := c.Update(ctx, service); err != nil {
+		t.Fatalf("failed to modify service: %v", err)
+	}
+	reconcile()
+	if err := c.Get(ctx, serviceKey, service); err != nil {
+		t.Fatalf("failed to get service: %v", err)
+	}
+	for k, v := range annotations {
+		if service.GetAnnotations()[k] != v {
+			t.Errorf("annotation %s = %q after an unchanged reconcile, want %q restored", k, service.GetAnnotations()[k], v)
+		}
+	}
+	if metav1.GetControllerOf(service) == nil {
+		t.Error("controller reference not restored after an unchanged reconcile")
+	}
+
+	// A spec change is applied in full
+	markAvailable()
+	got.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/infra": ""}
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update multiclusterengine: %v", err)
+	}
+	atomic.StoreInt32(&applies, 0)
+	reconcile()
+	if n := atomic.LoadInt32(&applies); n == 0 {
+		t.Error("reconcile after a spec change applied no resources")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	semver "github.com/Masterminds/semver"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+	"github.com/stolostron/backplane-operator/pkg/version"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// downgradeBlocked returns true if the operator version is lower than the version the components
+// of the multiclusterengine were last deployed with, and the multiclusterengine does not allow a
+// downgrade. Older operand images may not be compatible with the data left by newer ones, so
+// nothing is applied and a DowngradeBlocked condition is set instead. The warning event is only
+// recorded when the condition is first set, not on every blocked reconcile.
+func (r *MultiClusterEngineReconciler) downgradeBlocked(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) bool {
+	current := backplaneConfig.Status.CurrentVersion
+	if current == "" || !isDowngrade(current, version.Version) {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineDowngradeBlocked)
+		return false
+	}
+	if utils.DowngradeAllowed(backplaneConfig) {
+		log.FromContext(ctx).Info(fmt.Sprintf("Downgrading from %s to %s as allowed by the %s annotation", current, version.Version, utils.AnnotationAllowDowngrade))
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineDowngradeBlocked)
+		return false
+	}
+
+	msg := fmt.Sprintf("Operator version %s is lower than the deployed version %s. Set the %s annotation to 'true' to downgrade anyway",
+		version.Version, current, utils.AnnotationAllowDowngrade)
+	log.FromContext(ctx).Info(msg)
+	if !downgradeBlockedReported(backplaneConfig) {
+		r.Recorder.Event(backplaneConfig, corev1.EventTypeWarning, status.DowngradeBlockedReason, msg)
+	}
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineDowngradeBlocked, metav1.ConditionTrue, status.DowngradeBlockedReason, msg))
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.DowngradeBlockedReason, msg))
+	return true
+}
+
+// downgradeBlockedReported returns true if the status already carries a true DowngradeBlocked
+// condition
+func downgradeBlockedReported(backplaneConfig *backplanev1.MultiClusterEngine) bool {
+	for _, c := range backplaneConfig.Status.Conditions {
+		if c.Type == backplanev1.MultiClusterEngineDowngradeBlocked && c.Status == metav1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// isDowngrade returns true if desired is a lower semver version than current. Versions that are not
+// semver are never considered a downgrade.
+func isDowngrade(current, desired string) bool {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return false
+	}
+	desiredVersion, err := semver.NewVersion(desired)
+	if err != nil {
+		return false
+	}
+	return desiredVersion.LessThan(currentVersion)
+}
@@ -0,0 +1,138 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+	"github.com/stolostron/backplane-operator/pkg/version"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_isDowngrade(t *testing.T) {
+	tests := []struct {
+		current, desired string
+		want             bool
+	}{
+		{current: "2.4.0", desired: "2.3.1", want: true},
+		{current: "2.4.0", desired: "2.4.0", want: false},
+		{current: "2.4.0", desired: "2.5.0", want: false},
+		{current: "2.4.0", desired: "2.4.0-rc1", want: true},
+		{current: "not-a-version", desired: "2.3.1", want: false},
+	}
+	for _, tt := range tests {
+		if got := isDowngrade(tt.current, tt.desired); got != tt.want {
+			t.Errorf("isDowngrade(%q, %q) = %v, want %v", tt.current, tt.desired, got, tt.want)
+		}
+	}
+}
+
+func Test_downgradeBlocked(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+	// The operator version is lower than the version the components were deployed with
+	defer func(v string) { version.Version = v }(version.Version)
+	version.Version = "2.3.0"
+
+	tests := []struct {
+		name    string
+		allow   bool
+		blocked bool
+	}{
+		{name: "downgrade", blocked: true},
+		{name: "downgrade allowed", allow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mce := newMonitoringTestMCE()
+			mce.Status.CurrentVersion = "2.4.0"
+			if tt.allow {
+				mce.SetAnnotations(map[string]string{utils.AnnotationAllowDowngrade: "true"})
+			}
+			s := newTestScheme()
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+				mce,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+				&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+			).Build()
+			r := newTestReconciler(s, c)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+			for pass := 0; pass < 3; pass++ {
+				if _, err := r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+			}
+
+			got := &backplanev1.MultiClusterEngine{}
+			if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+				t.Fatalf("failed to get multiclusterengine: %v", err)
+			}
+			var cond *backplanev1.MultiClusterEngineCondition
+			for i := range got.Status.Conditions {
+				if got.Status.Conditions[i].Type == backplanev1.MultiClusterEngineDowngradeBlocked {
+					cond = &got.Status.Conditions[i]
+				}
+			}
+			deployments := &appsv1.DeploymentList{}
+			if err := c.List(ctx, deployments, client.InNamespace("multicluster-engine")); err != nil {
+				t.Fatalf("failed to list deployments: %v", err)
+			}
+
+			if tt.blocked {
+				if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != status.DowngradeBlockedReason {
+					t.Errorf("expected a true %s condition, got %+v", backplanev1.MultiClusterEngineDowngradeBlocked, cond)
+				}
+				if len(deployments.Items) != 0 {
+					t.Errorf("expected no deployments to be applied while the downgrade is blocked, got %d", len(deployments.Items))
+				}
+				if got.Status.CurrentVersion != "2.4.0" {
+					t.Errorf("status.currentVersion = %q, want it to stay 2.4.0", got.Status.CurrentVersion)
+				}
+				if n := downgradeEvents(r.Recorder.(*record.FakeRecorder)); n != 1 {
+					t.Errorf("recorded %d %s events over the blocked reconciles, want 1", n, status.DowngradeBlockedReason)
+				}
+				return
+			}
+			if cond != nil {
+				t.Errorf("unexpected %s condition with the downgrade allowed: %s", cond.Type, cond.Message)
+			}
+			if len(deployments.Items) == 0 {
+				t.Error("expected deployments to be applied with the downgrade allowed")
+			}
+		})
+	}
+}
+
+// downgradeEvents drains the recorded events and counts the DowngradeBlocked ones
+func downgradeEvents(recorder *record.FakeRecorder) int {
+	n := 0
+	for {
+		select {
+		case e := <-recorder.Events:
+			if strings.Contains(e, status.DowngradeBlockedReason) {
+				n++
+			}
+		default:
+			return n
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// driftConditionTTL is how long the DriftCorrected condition remains after the last correction
+const driftConditionTTL = time.Hour
+
+var driftCorrectedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "backplane_operator_drift_corrected_total",
+		Help: "Number of times a managed resource modified outside the operator was restored to its desired state",
+	},
+	[]string{"kind", "name"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftCorrectedTotal)
+}
+
+// driftTracker remembers the desired state last applied to each resource so that a later
+// difference in the live resource can be attributed to an external change rather than to a
+// change in the desired state, e.g. after an upgrade. A nil tracker remembers nothing.
+type driftTracker struct {
+	mu      sync.Mutex
+	applied map[string]string
+}
+
+func driftKey(template *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", template.GroupVersionKind().String(), template.GetNamespace(), template.GetName())
+}
+
+func desiredHash(template *unstructured.Unstructured) string {
+	b, err := json.Marshal(template.Object)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+// lastApplied returns the hash of the desired state last applied for the resource
+func (d *driftTracker) lastApplied(key string) (string, bool) {
+	if d == nil {
+		return "", false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hash, ok := d.applied[key]
+	return hash, ok
+}
+
+func (d *driftTracker) recordApplied(key, hash string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.applied == nil {
+		d.applied = map[string]string{}
+	}
+	d.applied[key] = hash
+}
+
+// containsDesired returns true if every value set in desired is present and equal in live.
+// Fields only present in live, such as those defaulted by the API server, are ignored, as are
+// empty and zero values in desired.
+func containsDesired(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return len(d) == 0
+		}
+		for k, v := range d {
+			if isEmptyValue(v) {
+				continue
+			}
+			if !containsDesired(v, l[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(d) != len(l) {
+			return false
+		}
+		for i := range d {
+			if !containsDesired(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if live == nil {
+			// Zero values are dropped from live resources by omitempty
+			return reflect.ValueOf(desired).IsZero()
+		}
+		return reflect.DeepEqual(desired, live) || fmt.Sprint(desired) == fmt.Sprint(live)
+	}
+}
+
+func isEmptyValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch t := v.(type) {
+	case string:
+		return t == ""
+	case map[string]interface{}:
+		return len(t) == 0
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// matchesDesired returns true if the live resource has the spec, labels, annotations and controller
+// owner reference of the desired template
+func matchesDesired(template, live *unstructured.Unstructured) bool {
+	desired := template.DeepCopy().Object
+	delete(desired, "metadata")
+	delete(desired, "status")
+	actual := live.DeepCopy().Object
+	delete(actual, "metadata")
+	delete(actual, "status")
+	return containsDesired(desired, actual) &&
+		containsDesired(stringMap(template.GetLabels()), stringMap(live.GetLabels())) &&
+		containsDesired(stringMap(template.GetAnnotations()), stringMap(live.GetAnnotations())) &&
+		sameController(template, live)
+}
+
+// stringMap converts labels or annotations so that containsDesired ignores the entries only
+// present in live, such as annotations added by other controllers
+func stringMap(m map[string]string) map[string]interface{} {
+	converted := map[string]interface{}{}
+	for k, v := range m {
+		converted[k] = v
+	}
+	return converted
+}
+
+// sameController returns true if the live resource is controlled by the owner the template sets, if any
+func sameController(template, live *unstructured.Unstructured) bool {
+	desired := metav1.GetControllerOf(template)
+	if desired == nil {
+		return true
+	}
+	actual := metav1.GetControllerOf(live)
+	return actual != nil && actual.UID == desired.UID && actual.Kind == desired.Kind && actual.Name == desired.Name
+}
+
+// getLive reads the resource of the template
+func (r *MultiClusterEngineReconciler) getLive(ctx context.Context, template *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(template.GroupVersionKind())
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(template), live); err != nil {
+		return nil, err
+	}
+	return live, nil
+}
+
+// matchesLive returns true if the resource of the template exists and matches it
+func (r *MultiClusterEngineReconciler) matchesLive(ctx context.Context, template *unstructured.Unstructured) bool {
+	live, err := r.getLive(ctx, template)
+	return err == nil && matchesDesired(template, live)
+}
+
+// detectDrift compares the live resource, nil if it could not be read, against the desired
+// template and, if the desired state is unchanged since it was last applied but the live resource
+// no longer matches it, records that the resource was modified outside the operator. The
+// template's desired state is then recorded as applied.
+func (r *MultiClusterEngineReconciler) detectDrift(ctx context.Context, template, live *unstructured.Unstructured) {
+	key := driftKey(template)
+	hash := desiredHash(template)
+	defer r.drift.recordApplied(key, hash)
+
+	if last, ok := r.drift.lastApplied(key); !ok || last != hash {
+		return
+	}
+
+	// Missing resources are simply recreated
+	if live == nil || matchesDesired(template, live) {
+		return
+	}
+
+	log.FromContext(ctx).Info(fmt.Sprintf("Correcting drift in %s %s", template.GetKind(), template.GetName()))
+	driftCorrectedTotal.WithLabelValues(template.GetKind(), template.GetName()).Inc()
+	r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineDriftCorrected)
+	r.StatusManager.AddCondition(status.NewCondition(
+		backplanev1.MultiClusterEngineDriftCorrected,
+		metav1.ConditionTrue,
+		status.DriftCorrectedReason,
+		fmt.Sprintf("%s %s was modified outside the operator and has been restored", template.GetKind(), client.ObjectKeyFromObject(template)),
+	))
+}
+
+// expireDriftCondition removes the DriftCorrected condition once no drift has been corrected for driftConditionTTL
+func (r *MultiClusterEngineReconciler) expireDriftCondition() {
+	for _, c := range r.StatusManager.Conditions {
+		if c.Type == backplanev1.MultiClusterEngineDriftCorrected && time.Since(c.LastUpdateTime.Time) > driftConditionTTL {
+			r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineDriftCorrected)
+			return
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_detectDrift(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	r.Images = testImages()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "cluster-curator-controller", Namespace: "multicluster-engine"}
+	drifted := driftCorrectedTotal.WithLabelValues("Deployment", key.Name)
+	before := testutil.ToFloat64(drifted)
+
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+	// Reapplying an unchanged resource is not drift
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+	if got := testutil.ToFloat64(drifted); got != before {
+		t.Fatalf("expected no drift to be recorded, got %v corrections", got-before)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/tampered/image:latest"
+	if err := r.Client.Update(ctx, deployment); err != nil {
+		t.Fatalf("failed to update deployment: %v", err)
+	}
+
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+	if got := testutil.ToFloat64(drifted); got != before+1 {
+		t.Errorf("expected drift metric to increment by 1, got %v", got-before)
+	}
+
+	found := false
+	for _, c := range r.StatusManager.Conditions {
+		if c.Type == backplanev1.MultiClusterEngineDriftCorrected {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected %s condition to be set", backplanev1.MultiClusterEngineDriftCorrected)
+	}
+
+	if err := r.Client.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if deployment.Spec.Template.Spec.Containers[0].Image == "quay.io/tampered/image:latest" {
+		t.Errorf("expected drift to be corrected")
+	}
+}
+
+func Test_containsDesired(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired interface{}
+		live    interface{}
+		want    bool
+	}{
+		{
+			name:    "defaulted fields ignored",
+			desired: map[string]interface{}{"replicas": int64(1)},
+			live:    map[string]interface{}{"replicas": int64(1), "revisionHistoryLimit": int64(10)},
+			want:    true,
+		},
+		{
+			name:    "omitted zero values",
+			desired: map[string]interface{}{"hostNetwork": false, "image": ""},
+			live:    map[string]interface{}{},
+			want:    true,
+		},
+		{
+			name:    "changed value",
+			desired: map[string]interface{}{"replicas": int64(1)},
+			live:    map[string]interface{}{"replicas": int64(3)},
+			want:    false,
+		},
+		{
+			name:    "extra list item",
+			desired: []interface{}{"a"},
+			live:    []interface{}{"a", "b"},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsDesired(tt.desired, tt.live); got != tt.want {
+				t.Errorf("containsDesired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
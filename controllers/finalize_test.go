@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Test_finalizeWaitsForDependents deletes a multiclusterengine while the ClusterManager is held by
+// a finalizer of its own
+func Test_finalizeWaitsForDependents(t *testing.T) {
+	t.Setenv("UNIT_TEST", "true")
+
+	ctx := context.Background()
+	now := metav1.Now()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "multiclusterengine",
+			Finalizers:        []string{backplaneFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Spec: backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	clusterManagerGVK := schema.GroupVersionKind{Group: "operator.open-cluster-management.io", Version: "v1", Kind: "ClusterManager"}
+	clusterManager := &unstructured.Unstructured{}
+	clusterManager.SetGroupVersionKind(clusterManagerGVK)
+	clusterManager.SetName("cluster-manager")
+	clusterManager.SetFinalizers([]string{"operator.open-cluster-management.io/cluster-manager-cleanup"})
+
+	s := newTestScheme()
+	s.AddKnownTypeWithName(clusterManagerGVK, &unstructured.Unstructured{})
+	managedClusterGVK := schema.GroupVersionKind{Group: "cluster.open-cluster-management.io", Version: "v1", Kind: "ManagedCluster"}
+	s.AddKnownTypeWithName(managedClusterGVK, &unstructured.Unstructured{})
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce, clusterManager).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	for i := 0; i < 2; i++ {
+		res, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v, want the deletion to be retried", err)
+		}
+		if res.RequeueAfter == 0 {
+			t.Fatalf("Reconcile() result = %v, want a requeue while the ClusterManager exists", res)
+		}
+
+		got := &backplanev1.MultiClusterEngine{}
+		if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatalf("failed to get multiclusterengine: %v", err)
+		}
+		if !controllerutil.ContainsFinalizer(got, backplaneFinalizer) {
+			t.Fatal("multiclusterengine finalizer removed while the ClusterManager still exists")
+		}
+	}
+
+	// The ClusterManager finalizer completes
+	cm := &unstructured.Unstructured{}
+	cm.SetGroupVersionKind(clusterManagerGVK)
+	if err := c.Get(ctx, types.NamespacedName{Name: "cluster-manager"}, cm); err != nil {
+		t.Fatalf("failed to get ClusterManager: %v", err)
+	}
+	if cm.GetDeletionTimestamp() == nil {
+		t.Fatal("ClusterManager was not deleted")
+	}
+	cm.SetFinalizers(nil)
+	if err := c.Update(ctx, cm); err != nil {
+		t.Fatalf("failed to remove ClusterManager finalizer: %v", err)
+	}
+
+	// Removing the last finalizer deletes the multiclusterengine before its status is written
+	if _, err := r.Reconcile(ctx, req); err != nil && !apierrors.IsNotFound(err) {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	got := &backplanev1.MultiClusterEngine{}
+	err := c.Get(ctx, req.NamespacedName, got)
+	if err == nil && controllerutil.ContainsFinalizer(got, backplaneFinalizer) {
+		t.Error("multiclusterengine finalizer not removed once its dependents were gone")
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+}
@@ -10,7 +10,6 @@ import (
 
 	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
 	"github.com/stolostron/backplane-operator/pkg/foundation"
-	"github.com/stolostron/backplane-operator/pkg/images"
 	"github.com/stolostron/backplane-operator/pkg/status"
 	"github.com/stolostron/backplane-operator/pkg/utils"
 
@@ -18,7 +17,6 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,13 +31,24 @@ func (r *MultiClusterEngineReconciler) HostedReconcile(ctx context.Context, mce
 	log := log.FromContext(ctx)
 
 	defer func() {
+		previousPhase := mce.Status.Phase
 		mce.Status = r.StatusManager.ReportStatus(*mce)
+		reportComponentReadiness(mce.Status.Components)
+		succeeded := retErr == nil
+		if succeeded {
+			markReconciled(mce)
+		}
 		err := r.Client.Status().Update(ctx, mce)
 		if mce.Status.Phase != backplanev1.MultiClusterEnginePhaseAvailable && !utils.IsPaused(mce) {
-			retRes = ctrl.Result{RequeueAfter: requeuePeriod}
+			retRes = ctrl.Result{RequeueAfter: r.resyncPeriod()}
 		}
 		if err != nil {
 			retErr = err
+		} else {
+			reportPhaseTransition(previousPhase, mce)
+			if succeeded {
+				reportReconciled(mce)
+			}
 		}
 	}()
 
@@ -98,7 +107,7 @@ func (r *MultiClusterEngineReconciler) HostedReconcile(ctx context.Context, mce
 	}
 
 	// Read images from environmental variables
-	imgs, err := images.GetImagesWithOverrides(r.Client, mce)
+	imgs, err := r.getImages(ctx, mce)
 	if err != nil {
 		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason, fmt.Sprintf("Issue building image references: %s", err.Error())))
 		return ctrl.Result{}, err
@@ -106,7 +115,7 @@ func (r *MultiClusterEngineReconciler) HostedReconcile(ctx context.Context, mce
 	if len(imgs) == 0 {
 		// If images are not set from environmental variables, fail
 		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason, "No image references defined in deployment"))
-		return ctrl.Result{RequeueAfter: requeuePeriod}, errors.New("no image references exist. images must be defined as environment variables")
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, errors.New("no image references exist. images must be defined as environment variables")
 	}
 	r.Images = imgs
 
@@ -120,7 +129,7 @@ func (r *MultiClusterEngineReconciler) HostedReconcile(ctx context.Context, mce
 	hostedClient, err := r.GetHostedClient(ctx, mce)
 	if err != nil {
 		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason, fmt.Sprintf("couldn't connect to hosted environment: %s", err.Error())))
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
 	err = hostedClient.Create(ctx, &corev1.Namespace{
@@ -128,7 +137,7 @@ func (r *MultiClusterEngineReconciler) HostedReconcile(ctx context.Context, mce
 	})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
 		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason, err.Error()))
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
 	// Create hosted ClusterManager
@@ -246,20 +255,18 @@ func (r *MultiClusterEngineReconciler) ensureHostedClusterManager(ctx context.Co
 		Type: corev1.SecretTypeOpaque,
 	}
 
-	force := true
-	err = r.Client.Patch(context.TODO(), cmSecret, client.Apply, &client.PatchOptions{Force: &force, FieldManager: "backplane-operator"})
+	err = r.applyObject(context.TODO(), cmSecret, backplaneFieldManager)
 	if err != nil {
 		log.Info(fmt.Sprintf("Error applying kubeconfig secret to hosted cluster-manager namespace: %s", err.Error()))
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Apply clustermanager
-	cmTemplate := foundation.HostedClusterManager(mce, r.Images)
+	cmTemplate := r.withPreferredVersion(foundation.HostedClusterManager(mce, r.Images))
 	if err := ctrl.SetControllerReference(mce, cmTemplate, r.Scheme); err != nil {
 		return ctrl.Result{}, fmt.Errorf("Error setting controller reference on resource `%s`: %w", cmTemplate.GetName(), err)
 	}
-	force = true
-	err = r.Client.Patch(ctx, cmTemplate, client.Apply, &client.PatchOptions{Force: &force, FieldManager: "backplane-operator"})
+	err = r.applyObject(ctx, cmTemplate, backplaneFieldManager)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error applying object Name: %s Kind: %s, %w", cmTemplate.GetName(), cmTemplate.GetKind(), err)
 	}
@@ -276,31 +283,25 @@ func (r *MultiClusterEngineReconciler) ensureNoHostedClusterManager(ctx context.
 
 	// Delete clustermanager
 	clusterManager := &unstructured.Unstructured{}
-	clusterManager.SetGroupVersionKind(
-		schema.GroupVersionKind{
-			Group:   "operator.open-cluster-management.io",
-			Version: "v1",
-			Kind:    "ClusterManager",
-		},
-	)
+	clusterManager.SetGroupVersionKind(utils.PreferredGVK(r.Client.RESTMapper(), foundation.ClusterManagerGVK))
 	err := r.Client.Get(ctx, types.NamespacedName{Name: cmName}, clusterManager)
 	if err == nil { // If resource exists, delete
 		err := r.Client.Delete(ctx, clusterManager)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: requeuePeriod}, err
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 		}
 	} else if err != nil && !apierrors.IsNotFound(err) { // Return error, if error is not not found error
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
 	// Verify clustermanager namespace deleted
 	checkNs := &corev1.Namespace{}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: cmName}, checkNs)
 	if err == nil {
-		return ctrl.Result{RequeueAfter: requeuePeriod}, fmt.Errorf("waiting for hosted-clustermanager namespace to be terminated before proceeding with clustermanager cleanup")
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, fmt.Errorf("waiting for hosted-clustermanager namespace to be terminated before proceeding with clustermanager cleanup")
 	}
 	if err != nil && !apierrors.IsNotFound(err) { // Return error, if error is not not found error
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
 	return ctrl.Result{}, nil
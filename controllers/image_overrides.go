@@ -0,0 +1,135 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/images"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// imageOverrideCache remembers the images last resolved with each image overrides configmap. A nil
+// cache remembers nothing.
+type imageOverrideCache struct {
+	mu     sync.Mutex
+	images map[string]map[string]string
+}
+
+func (c *imageOverrideCache) get(configmap string) (map[string]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	imgs, ok := c.images[configmap]
+	return imgs, ok
+}
+
+func (c *imageOverrideCache) set(configmap string, imgs map[string]string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.images == nil {
+		c.images = map[string]map[string]string{}
+	}
+	c.images[configmap] = imgs
+}
+
+// getImages resolves component images for the multiclusterengine. If the configmap named by the
+// imageOverridesCM annotation has been deleted, an ImageOverrideConfigMapMissing condition is added
+// and the images last resolved with it are returned instead of reverting to the defaults. After a
+// restart those are no longer cached, so the images deployed with the configmap, as recorded in the
+// status, are used.
+func (r *MultiClusterEngineReconciler) getImages(ctx context.Context, mce *backplanev1.MultiClusterEngine) (map[string]string, error) {
+	cmName := utils.GetImageOverridesConfigmap(mce)
+	imgs, err := images.GetImagesWithOverrides(r.Client, mce)
+	if err == nil {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineImageOverrideConfigMapMissing)
+		if cmName != "" {
+			r.imageOverrides.set(cmName, imgs)
+		}
+		return imgs, nil
+	}
+	if cmName == "" || !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	lastKnown, ok := r.imageOverrides.get(cmName)
+	if !ok {
+		lastKnown, ok = deployedOverrideImages(mce, cmName)
+	}
+	message := fmt.Sprintf("Image overrides configmap %s not found in namespace %s. Using the last known image overrides.",
+		cmName, utils.OperatorNamespace())
+	if !ok {
+		message = fmt.Sprintf("Image overrides configmap %s not found in namespace %s", cmName, utils.OperatorNamespace())
+	}
+	log.FromContext(ctx).Info(message)
+	r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineImageOverrideConfigMapMissing)
+	r.StatusManager.AddCondition(status.NewCondition(
+		backplanev1.MultiClusterEngineImageOverrideConfigMapMissing,
+		metav1.ConditionTrue,
+		status.ConfigMapNotFoundReason,
+		message,
+	))
+	if !ok {
+		// Without a previous read there is nothing to fall back to but the defaults, which
+		// would silently drop the overrides
+		return nil, err
+	}
+	return lastKnown, nil
+}
+
+// deployedOverrideImages returns the images recorded in the status when they were deployed with
+// the given overrides configmap
+func deployedOverrideImages(mce *backplanev1.MultiClusterEngine, cmName string) (map[string]string, bool) {
+	applied := mce.Status.AppliedOverrides
+	if applied == nil || applied.ImageOverridesConfigMap != cmName || len(mce.Status.DeployedImages) == 0 {
+		return nil, false
+	}
+	imgs := make(map[string]string, len(mce.Status.DeployedImages))
+	for k, v := range mce.Status.DeployedImages {
+		imgs[k] = v
+	}
+	return imgs, true
+}
+
+// imageOverridesConfigmapChanged filters the events of the configmaps watched for image overrides.
+// A configmap carries no generation and edits to its data leave its labels and annotations alone,
+// so every new resource version is reconciled to re-resolve the images right away.
+var imageOverridesConfigmapChanged predicate.Predicate = predicate.ResourceVersionChangedPredicate{}
+
+// imageOverridesConfigmapToMCE enqueues the multiclusterengines referencing a configmap in the
+// operator namespace through the imageOverridesCM annotation
+func (r *MultiClusterEngineReconciler) imageOverridesConfigmapToMCE(obj client.Object) []reconcile.Request {
+	namespace, err := utils.GetOperatorNamespace()
+	if err != nil || obj.GetNamespace() != namespace {
+		return nil
+	}
+
+	mceList := &backplanev1.MultiClusterEngineList{}
+	if err := r.Client.List(context.Background(), mceList); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for i := range mceList.Items {
+		if utils.GetImageOverridesConfigmap(&mceList.Items[i]) == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: mceList.Items[i].GetName()}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,258 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func Test_getImagesOverridesConfigmapDeleted(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("OPERAND_IMAGE_CLUSTER_CURATOR_CONTROLLER", "quay.io/default/cluster-curator-controller:latest")
+
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	mce.SetAnnotations(map[string]string{utils.AnnotationImageOverridesCM: "image-overrides"})
+	overrides := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "image-overrides", Namespace: "default"},
+		Data: map[string]string{
+			"overrides.json": `[{"image-key": "cluster_curator_controller", "image-remote": "quay.io/override",
+				"image-name": "cluster-curator-controller", "image-digest": "sha256:abc123"}]`,
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce, overrides).Build()
+	r := newTestReconciler(s, c)
+	r.StatusManager.Reset("")
+
+	missingCondition := func(r *MultiClusterEngineReconciler) *backplanev1.MultiClusterEngineCondition {
+		for i, c := range r.StatusManager.Conditions {
+			if c.Type == backplanev1.MultiClusterEngineImageOverrideConfigMapMissing {
+				return &r.StatusManager.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	want := "quay.io/override/cluster-curator-controller@sha256:abc123"
+	imgs, err := r.getImages(context.Background(), mce)
+	if err != nil {
+		t.Fatalf("getImages() error = %v", err)
+	}
+	if imgs["cluster_curator_controller"] != want {
+		t.Fatalf("getImages() = %q, want %q", imgs["cluster_curator_controller"], want)
+	}
+	if cond := missingCondition(r); cond != nil {
+		t.Fatalf("unexpected %s condition while the configmap exists", cond.Type)
+	}
+
+	if err := c.Delete(context.Background(), overrides); err != nil {
+		t.Fatalf("failed to delete configmap: %v", err)
+	}
+
+	imgs, err = r.getImages(context.Background(), mce)
+	if err != nil {
+		t.Fatalf("getImages() error = %v", err)
+	}
+	if imgs["cluster_curator_controller"] != want {
+		t.Errorf("getImages() = %q, want the last known override %q", imgs["cluster_curator_controller"], want)
+	}
+	cond := missingCondition(r)
+	if cond == nil {
+		t.Fatalf("expected %s condition after the configmap was deleted", backplanev1.MultiClusterEngineImageOverrideConfigMapMissing)
+	}
+	if cond.Status != metav1.ConditionTrue {
+		t.Errorf("condition status = %s, want %s", cond.Status, metav1.ConditionTrue)
+	}
+
+	// Without a previous read there is nothing to fall back to
+	fresh := newTestReconciler(s, c)
+	fresh.StatusManager.Reset("")
+	if _, err := fresh.getImages(context.Background(), mce); err == nil {
+		t.Error("expected an error when the configmap was never read")
+	}
+	if missingCondition(fresh) == nil {
+		t.Errorf("expected %s condition when the configmap was never read", backplanev1.MultiClusterEngineImageOverrideConfigMapMissing)
+	}
+
+	// After a restart the images deployed with the configmap are taken from the status
+	mce.Status.AppliedOverrides = &backplanev1.AppliedOverrides{ImageOverridesConfigMap: "image-overrides"}
+	mce.Status.DeployedImages = map[string]string{"cluster_curator_controller": want}
+	restarted := newTestReconciler(s, c)
+	restarted.StatusManager.Reset("")
+	imgs, err = restarted.getImages(context.Background(), mce)
+	if err != nil {
+		t.Fatalf("getImages() after a restart error = %v", err)
+	}
+	if imgs["cluster_curator_controller"] != want {
+		t.Errorf("getImages() after a restart = %q, want the deployed override %q", imgs["cluster_curator_controller"], want)
+	}
+	if missingCondition(restarted) == nil {
+		t.Errorf("expected %s condition after a restart", backplanev1.MultiClusterEngineImageOverrideConfigMapMissing)
+	}
+
+	// Images deployed with another configmap are not reused
+	mce.Status.AppliedOverrides.ImageOverridesConfigMap = "other-overrides"
+	other := newTestReconciler(s, c)
+	other.StatusManager.Reset("")
+	if _, err := other.getImages(context.Background(), mce); err == nil {
+		t.Error("expected an error when the deployed images came from another configmap")
+	}
+}
+
+func Test_imageOverridesConfigmapToMCE(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	mce.SetAnnotations(map[string]string{utils.AnnotationImageOverridesCM: "image-overrides"})
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+
+	tests := []struct {
+		name      string
+		configmap *corev1.ConfigMap
+		want      int
+	}{
+		{
+			name:      "referenced configmap",
+			configmap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "image-overrides", Namespace: "default"}},
+			want:      1,
+		},
+		{
+			name:      "unreferenced configmap",
+			configmap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+			want:      0,
+		},
+		{
+			name:      "configmap in another namespace",
+			configmap: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "image-overrides", Namespace: "other"}},
+			want:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.imageOverridesConfigmapToMCE(tt.configmap); len(got) != tt.want {
+				t.Errorf("imageOverridesConfigmapToMCE() returned %d requests, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func Test_imageOverridesConfigmapUpdateRedeploys(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	overridesData := func(digest string) map[string]string {
+		return map[string]string{
+			"overrides.json": `[{"image-key": "discovery_operator", "image-remote": "quay.io/override",
+				"image-name": "discovery-operator", "image-digest": "` + digest + `"}]`,
+		}
+	}
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "multiclusterengine",
+			Annotations: map[string]string{utils.AnnotationImageOverridesCM: "image-overrides"},
+		},
+		Spec: backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	overrides := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "image-overrides", Namespace: "default"},
+		Data:       overridesData("sha256:aaa"),
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		overrides,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	discoveryImage := func() string {
+		deployment := &appsv1.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Name: "discovery-operator", Namespace: "multicluster-engine"}, deployment); err != nil {
+			t.Fatalf("failed to get discovery-operator deployment: %v", err)
+		}
+		return deployment.Spec.Template.Spec.Containers[0].Image
+	}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	if got, want := discoveryImage(), "quay.io/override/discovery-operator@sha256:aaa"; got != want {
+		t.Fatalf("discovery-operator image = %s, want %s", got, want)
+	}
+
+	// Editing the data of the configmap enqueues the multiclusterengine referencing it
+	updated := overrides.DeepCopy()
+	if err := c.Get(ctx, types.NamespacedName{Name: overrides.Name, Namespace: overrides.Namespace}, updated); err != nil {
+		t.Fatalf("failed to get configmap: %v", err)
+	}
+	previous := updated.DeepCopy()
+	updated.Data = overridesData("sha256:bbb")
+	if err := c.Update(ctx, updated); err != nil {
+		t.Fatalf("failed to update configmap: %v", err)
+	}
+	if !imageOverridesConfigmapChanged.Update(event.UpdateEvent{ObjectOld: previous, ObjectNew: updated}) {
+		t.Fatal("expected a data change of the image overrides configmap to be reconciled")
+	}
+	requests := r.imageOverridesConfigmapToMCE(updated)
+	if len(requests) != 1 || requests[0] != req {
+		t.Fatalf("imageOverridesConfigmapToMCE() = %v, want %v", requests, req)
+	}
+
+	if _, err := r.Reconcile(ctx, requests[0]); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got, want := discoveryImage(), "quay.io/override/discovery-operator@sha256:bbb"; got != want {
+		t.Errorf("discovery-operator image = %s, want %s after the configmap update", got, want)
+	}
+
+	// Pointing the annotation at another configmap resolves its images, and the previous one is
+	// no longer watched for the multiclusterengine
+	replacement := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "image-overrides-v2", Namespace: "default"},
+		Data:       overridesData("sha256:ccc"),
+	}
+	if err := c.Create(ctx, replacement); err != nil {
+		t.Fatalf("failed to create configmap: %v", err)
+	}
+	current := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(ctx, req.NamespacedName, current); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	current.Annotations[utils.AnnotationImageOverridesCM] = replacement.Name
+	if err := c.Update(ctx, current); err != nil {
+		t.Fatalf("failed to update multiclusterengine: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got, want := discoveryImage(), "quay.io/override/discovery-operator@sha256:ccc"; got != want {
+		t.Errorf("discovery-operator image = %s, want %s after switching configmaps", got, want)
+	}
+	if requests := r.imageOverridesConfigmapToMCE(updated); len(requests) != 0 {
+		t.Errorf("imageOverridesConfigmapToMCE() = %v for the configmap no longer referenced, want none", requests)
+	}
+}
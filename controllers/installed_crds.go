@@ -0,0 +1,65 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	renderer "github.com/stolostron/backplane-operator/pkg/rendering"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// operatorCRDsDir holds the CRDs installed by the operator when it starts, before any
+// multiclusterengine is reconciled
+const operatorCRDsDir = "pkg/templates/crds"
+
+// recordCRD records that a CRD template was applied, or deleted when installed is false. Other
+// templates are ignored.
+func (r *MultiClusterEngineReconciler) recordCRD(template *unstructured.Unstructured, installed bool) {
+	if template.GetKind() != "CustomResourceDefinition" {
+		return
+	}
+	if installed {
+		r.installedCRDs = append(r.installedCRDs, template.GetName())
+	} else {
+		r.removedCRDs = append(r.removedCRDs, template.GetName())
+	}
+}
+
+// recordOperatorCRDs records the CRDs installed by the operator at startup, which it only
+// applies on behalf of the multiclusterengines
+func (r *MultiClusterEngineReconciler) recordOperatorCRDs() error {
+	crds, errs := renderer.RenderCRDs(operatorCRDsDir)
+	if len(errs) > 0 {
+		return fmt.Errorf("error rendering CRDs in %s: %v", operatorCRDsDir, errs)
+	}
+	for _, crd := range crds {
+		r.recordCRD(crd, true)
+	}
+	return nil
+}
+
+// mergeInstalledCRDs returns the sorted CRD names in previous and installed, without those in removed
+func mergeInstalledCRDs(previous, installed, removed []string) []string {
+	names := map[string]bool{}
+	for _, name := range previous {
+		names[name] = true
+	}
+	for _, name := range installed {
+		names[name] = true
+	}
+	for _, name := range removed {
+		delete(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	merged := []string{}
+	for name := range names {
+		merged = append(merged, name)
+	}
+	sort.Strings(merged)
+	return merged
+}
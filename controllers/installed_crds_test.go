@@ -0,0 +1,93 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_mergeInstalledCRDs(t *testing.T) {
+	got := mergeInstalledCRDs([]string{"b", "c"}, []string{"a", "b"}, []string{"c"})
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeInstalledCRDs() = %v, want %v", got, want)
+	}
+	if got := mergeInstalledCRDs(nil, nil, []string{"a"}); got != nil {
+		t.Errorf("mergeInstalledCRDs() = %v, want nil", got)
+	}
+}
+
+func Test_installedCRDs(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+	const (
+		managedServiceAccountCRD = "managedserviceaccounts.authentication.open-cluster-management.io"
+		clusterManagerCRD        = "clustermanagers.operator.open-cluster-management.io"
+	)
+
+	ctx := context.Background()
+	mce := newMonitoringTestMCE()
+	mce.Spec.Overrides = &backplanev1.Overrides{
+		Components: []backplanev1.ComponentConfig{{Name: backplanev1.ManagedServiceAccount, Enabled: true}},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+		// Addons, and with them the managed-serviceaccount CRDs, are only installed with the addon API
+		&apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "clustermanagementaddons.addon.open-cluster-management.io"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	reconcile := func() *backplanev1.MultiClusterEngine {
+		t.Helper()
+		for pass := 0; pass < 3; pass++ {
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+		}
+		got := &backplanev1.MultiClusterEngine{}
+		if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatalf("failed to get multiclusterengine: %v", err)
+		}
+		return got
+	}
+
+	got := reconcile()
+	for _, crd := range []string{managedServiceAccountCRD, clusterManagerCRD} {
+		if !utils.Contains(got.Status.InstalledCRDs, crd) {
+			t.Errorf("status.installedCRDs = %v, want it to contain %s", got.Status.InstalledCRDs, crd)
+		}
+	}
+
+	// Disabling the component removes its CRDs from the list
+	got.Spec.Overrides.Components[0].Enabled = false
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update multiclusterengine: %v", err)
+	}
+	got = reconcile()
+	if utils.Contains(got.Status.InstalledCRDs, managedServiceAccountCRD) {
+		t.Errorf("status.installedCRDs = %v, want %s removed", got.Status.InstalledCRDs, managedServiceAccountCRD)
+	}
+	if !utils.Contains(got.Status.InstalledCRDs, clusterManagerCRD) {
+		t.Errorf("status.installedCRDs = %v, want it to keep %s", got.Status.InstalledCRDs, clusterManagerCRD)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+)
+
+// ParseManagedComponents parses a comma-separated list of component names. An empty value
+// returns nil, meaning every component is managed.
+func ParseManagedComponents(value string) ([]string, error) {
+	components := []string{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !backplanev1.IsKnownComponent(name) {
+			return nil, fmt.Errorf("%s is not a known component", name)
+		}
+		components = append(components, name)
+	}
+	if len(components) == 0 {
+		return nil, nil
+	}
+	return components, nil
+}
+
+// manages returns true if the reconciler is responsible for applying and removing the component
+func (r *MultiClusterEngineReconciler) manages(component string) bool {
+	if len(r.ManagedComponents) == 0 {
+		return true
+	}
+	for _, c := range r.ManagedComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseManagedComponents(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty manages everything", value: "", want: nil},
+		{name: "single component", value: backplanev1.Discovery, want: []string{backplanev1.Discovery}},
+		{
+			name:  "whitespace is trimmed",
+			value: " discovery , hive,",
+			want:  []string{backplanev1.Discovery, backplanev1.Hive},
+		},
+		{name: "unknown component", value: "discovery,not-a-component", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseManagedComponents(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseManagedComponents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseManagedComponents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ensureToggleableComponentsManagedSubset(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	mce.Enable(backplanev1.Discovery)
+	mce.Enable(backplanev1.ClusterManager)
+	mce.Disable(backplanev1.Hive)
+
+	// A disabled component outside the managed set must be left alone
+	hive := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "hive-operator", Namespace: mce.Spec.TargetNamespace},
+	}
+	clusterVersion := &configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce, hive, clusterVersion).Build()
+	r := newTestReconciler(s, c)
+	r.Images = testImages()
+	r.ManagedComponents = []string{backplanev1.Discovery}
+	r.StatusManager.Reset("")
+
+	if _, err := r.ensureToggleableComponents(context.Background(), mce); err != nil {
+		t.Fatalf("ensureToggleableComponents() error = %v", err)
+	}
+
+	ns := mce.Spec.TargetNamespace
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "discovery-operator", Namespace: ns}, &appsv1.Deployment{}); err != nil {
+		t.Errorf("expected managed component to be created: %v", err)
+	}
+	err := c.Get(context.Background(), types.NamespacedName{Name: "cluster-manager", Namespace: ns}, &appsv1.Deployment{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected unmanaged component not to be created, got error %v", err)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "hive-operator", Namespace: ns}, &appsv1.Deployment{}); err != nil {
+		t.Errorf("expected unmanaged component not to be removed: %v", err)
+	}
+}
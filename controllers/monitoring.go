@@ -0,0 +1,128 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const monitoringGroup = "monitoring.coreos.com"
+
+// monitoringCRDs maps the Prometheus operator kinds rendered by the charts to the
+// CRDs that serve them
+var monitoringCRDs = map[string]string{
+	"ServiceMonitor": "servicemonitors.monitoring.coreos.com",
+	"PrometheusRule": "prometheusrules.monitoring.coreos.com",
+}
+
+// isMonitoringResource returns true if the template is served by the Prometheus operator APIs
+func isMonitoringResource(template *unstructured.Unstructured) bool {
+	_, ok := monitoringCRDs[template.GetKind()]
+	return ok && template.GroupVersionKind().Group == monitoringGroup
+}
+
+// monitoringCRDPresent returns true if the CRD serving the template's kind is installed
+func (r *MultiClusterEngineReconciler) monitoringCRDPresent(ctx context.Context, template *unstructured.Unstructured) bool {
+	crd := &apixv1.CustomResourceDefinition{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: monitoringCRDs[template.GetKind()]}, crd)
+	return err == nil
+}
+
+// skipMonitoringResource returns true if the template is a monitoring resource whose API is not
+// installed. When skipped, a MonitoringUnavailable condition is added to the status. Otherwise
+// any previous MonitoringUnavailable condition is cleared. Monitoring resources are always skipped
+// off OpenShift.
+func (r *MultiClusterEngineReconciler) skipMonitoringResource(ctx context.Context, template *unstructured.Unstructured) bool {
+	if !isMonitoringResource(template) {
+		return false
+	}
+	if !r.onOpenShift() {
+		return true
+	}
+	if r.monitoringCRDPresent(ctx, template) {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineMonitoringUnavailable)
+		return false
+	}
+
+	log.FromContext(ctx).Info(fmt.Sprintf("%s CRD not installed. Skipping %s %s",
+		monitoringCRDs[template.GetKind()], template.GetKind(), template.GetName()))
+	r.StatusManager.AddCondition(status.NewCondition(
+		backplanev1.MultiClusterEngineMonitoringUnavailable,
+		metav1.ConditionTrue,
+		status.CRDNotFoundReason,
+		fmt.Sprintf("%s is not installed. Monitoring resources will be created once it is available.", monitoringCRDs[template.GetKind()]),
+	))
+	return true
+}
+
+// serviceMonitorUnmanaged returns true if the template is a ServiceMonitor left to external tooling,
+// which the operator neither applies nor deletes
+func serviceMonitorUnmanaged(backplaneConfig *backplanev1.MultiClusterEngine, template *unstructured.Unstructured) bool {
+	return template.GetKind() == "ServiceMonitor" && isMonitoringResource(template) && utils.ServiceMonitorManagementDisabled(backplaneConfig)
+}
+
+// removeMovedServiceMonitors deletes the ServiceMonitors created for the multiclusterengine in a
+// namespace other than the one the templates render them in, which are left behind when the
+// monitoring namespace is changed
+func (r *MultiClusterEngineReconciler) removeMovedServiceMonitors(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine, templates []*unstructured.Unstructured) error {
+	for _, template := range templates {
+		if template.GetKind() != "ServiceMonitor" || !isMonitoringResource(template) || serviceMonitorUnmanaged(backplaneConfig, template) ||
+			!r.onOpenShift() || !r.monitoringCRDPresent(ctx, template) {
+			continue
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(template.GroupVersionKind().GroupVersion().WithKind("ServiceMonitorList"))
+		if err := r.Client.List(ctx, list); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			sm := &list.Items[i]
+			if sm.GetName() != template.GetName() || sm.GetNamespace() == template.GetNamespace() || !metav1.IsControlledBy(sm, backplaneConfig) {
+				continue
+			}
+			log.FromContext(ctx).Info(fmt.Sprintf("Removing ServiceMonitor %s from previous monitoring namespace %s", sm.GetName(), sm.GetNamespace()))
+			if err := r.Client.Delete(ctx, sm); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// monitoringCRDToMCE enqueues every MultiClusterEngine when a monitoring CRD is created so that
+// previously skipped monitoring resources get applied
+func (r *MultiClusterEngineReconciler) monitoringCRDToMCE(obj client.Object) []reconcile.Request {
+	isMonitoringCRD := false
+	for _, name := range monitoringCRDs {
+		if obj.GetName() == name {
+			isMonitoringCRD = true
+		}
+	}
+	if !isMonitoringCRD {
+		return nil
+	}
+
+	mceList := &backplanev1.MultiClusterEngineList{}
+	if err := r.Client.List(context.Background(), mceList); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, mce := range mceList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: mce.GetName()}})
+	}
+	return requests
+}
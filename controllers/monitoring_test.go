@@ -0,0 +1,242 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	monitorv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newMonitoringTestMCE() *backplanev1.MultiClusterEngine {
+	return &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine", UID: "test-uid"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+}
+
+func testImages() map[string]string {
+	imgs := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		imgs[v] = "quay.io/test/test:test"
+	}
+	return imgs
+}
+
+func Test_ensureClusterLifecycleWithoutMonitoringCRD(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	r.Images = testImages()
+
+	_, err := r.ensureClusterLifecycle(context.Background(), mce)
+	if err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v, want nil when ServiceMonitor CRD is absent", err)
+	}
+
+	found := false
+	for _, c := range r.StatusManager.Conditions {
+		if c.Type == backplanev1.MultiClusterEngineMonitoringUnavailable {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected %s condition to be set", backplanev1.MultiClusterEngineMonitoringUnavailable)
+	}
+
+	_, err = r.ensureNoClusterLifecycle(context.Background(), mce)
+	if err != nil {
+		t.Errorf("ensureNoClusterLifecycle() error = %v, want nil when ServiceMonitor CRD is absent", err)
+	}
+}
+
+func Test_ensureClusterLifecycleWithMonitoringCRD(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	utilruntime.Must(monitorv1.AddToScheme(s))
+	mce := newMonitoringTestMCE()
+	crd := &apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: monitoringCRDs["ServiceMonitor"]}}
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce, crd).Build())
+	r.Images = testImages()
+	r.StatusManager.AddCondition(backplanev1.MultiClusterEngineCondition{Type: backplanev1.MultiClusterEngineMonitoringUnavailable})
+
+	_, err := r.ensureClusterLifecycle(context.Background(), mce)
+	if err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+
+	sm := &monitorv1.ServiceMonitor{}
+	err = r.Client.Get(context.Background(), types.NamespacedName{Name: "clusterlifecycle-state-metrics-v2", Namespace: "openshift-monitoring"}, sm)
+	if apierrors.IsNotFound(err) {
+		t.Errorf("expected ServiceMonitor to be created once the CRD is present")
+	} else if err != nil {
+		t.Errorf("failed to get ServiceMonitor: %v", err)
+	}
+
+	for _, c := range r.StatusManager.Conditions {
+		if c.Type == backplanev1.MultiClusterEngineMonitoringUnavailable {
+			t.Errorf("expected %s condition to be removed", backplanev1.MultiClusterEngineMonitoringUnavailable)
+		}
+	}
+}
+
+func Test_ensureClusterLifecycleCustomMonitoringNamespace(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	utilruntime.Must(monitorv1.AddToScheme(s))
+	mce := newMonitoringTestMCE()
+	crd := &apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: monitoringCRDs["ServiceMonitor"]}}
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce, crd).Build())
+	r.Images = testImages()
+	ctx := context.Background()
+
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+	defaultKey := types.NamespacedName{Name: "clusterlifecycle-state-metrics-v2", Namespace: backplanev1.DefaultMonitoringNamespace}
+	if err := r.Client.Get(ctx, defaultKey, &monitorv1.ServiceMonitor{}); err != nil {
+		t.Fatalf("failed to get ServiceMonitor in %s: %v", backplanev1.DefaultMonitoringNamespace, err)
+	}
+
+	mce.Spec.Overrides = &backplanev1.Overrides{ServiceMonitor: &backplanev1.ServiceMonitorConfig{Namespace: "custom-monitoring"}}
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+	customKey := types.NamespacedName{Name: "clusterlifecycle-state-metrics-v2", Namespace: "custom-monitoring"}
+	if err := r.Client.Get(ctx, customKey, &monitorv1.ServiceMonitor{}); err != nil {
+		t.Errorf("expected ServiceMonitor in the custom monitoring namespace: %v", err)
+	}
+	if err := r.Client.Get(ctx, defaultKey, &monitorv1.ServiceMonitor{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected ServiceMonitor in the previous monitoring namespace to be removed, got error %v", err)
+	}
+}
+
+func Test_monitoringCRDToMCE(t *testing.T) {
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+
+	crd := &apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: monitoringCRDs["ServiceMonitor"]}}
+	if got := r.monitoringCRDToMCE(crd); len(got) != 1 || got[0].Name != mce.Name {
+		t.Errorf("monitoringCRDToMCE() = %v, want request for %s", got, mce.Name)
+	}
+
+	other := &apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"}}
+	if got := r.monitoringCRDToMCE(other); len(got) != 0 {
+		t.Errorf("monitoringCRDToMCE() = %v, want no requests for unrelated CRD", got)
+	}
+}
+
+func Test_DeployAlwaysSubcomponentsPrometheusRule(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	utilruntime.Must(monitorv1.AddToScheme(s))
+	mce := newMonitoringTestMCE()
+	crd := &apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: monitoringCRDs["PrometheusRule"]}}
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce, crd).Build())
+	r.Images = testImages()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "multicluster-engine-alerts", Namespace: "openshift-monitoring"}
+
+	if _, err := r.DeployAlwaysSubcomponents(ctx, mce); err != nil {
+		t.Fatalf("DeployAlwaysSubcomponents() error = %v", err)
+	}
+	rule := &monitorv1.PrometheusRule{}
+	if err := r.Client.Get(ctx, key, rule); err != nil {
+		t.Fatalf("expected PrometheusRule to be created: %v", err)
+	}
+	if len(rule.GetOwnerReferences()) != 1 || rule.GetOwnerReferences()[0].UID != mce.UID {
+		t.Errorf("expected PrometheusRule to be owned by the MultiClusterEngine, got %v", rule.GetOwnerReferences())
+	}
+	alerts := map[string]bool{}
+	for _, g := range rule.Spec.Groups {
+		for _, rr := range g.Rules {
+			alerts[rr.Alert] = true
+		}
+	}
+	for _, a := range []string{"MCEComponentDegraded", "MCEReconcileErrors"} {
+		if !alerts[a] {
+			t.Errorf("expected PrometheusRule to contain alert %s", a)
+		}
+	}
+
+	if err := r.Client.Delete(ctx, rule); err != nil {
+		t.Fatalf("failed to delete PrometheusRule: %v", err)
+	}
+	if _, err := r.DeployAlwaysSubcomponents(ctx, mce); err != nil {
+		t.Fatalf("DeployAlwaysSubcomponents() error = %v", err)
+	}
+	if err := r.Client.Get(ctx, key, &monitorv1.PrometheusRule{}); err != nil {
+		t.Errorf("expected PrometheusRule to be recreated after deletion: %v", err)
+	}
+}
+
+func Test_ensureClusterLifecycleUnmanagedServiceMonitors(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	s := newTestScheme()
+	utilruntime.Must(monitorv1.AddToScheme(s))
+	mce := newMonitoringTestMCE()
+	crd := &apixv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: monitoringCRDs["ServiceMonitor"]}}
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce, crd).Build())
+	r.Images = testImages()
+	ctx := context.Background()
+
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+	key := types.NamespacedName{Name: "clusterlifecycle-state-metrics-v2", Namespace: backplanev1.DefaultMonitoringNamespace}
+	sm := &monitorv1.ServiceMonitor{}
+	if err := r.Client.Get(ctx, key, sm); err != nil {
+		t.Fatalf("failed to get ServiceMonitor: %v", err)
+	}
+
+	// Once left to external tooling, a deleted ServiceMonitor is not recreated
+	mce.Spec.Overrides = &backplanev1.Overrides{DisableServiceMonitorManagement: true}
+	if err := r.Client.Delete(ctx, sm); err != nil {
+		t.Fatalf("failed to delete ServiceMonitor: %v", err)
+	}
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+	if err := r.Client.Get(ctx, key, &monitorv1.ServiceMonitor{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the deleted ServiceMonitor not to be recreated, got error %v", err)
+	}
+
+	// The metrics endpoint is still deployed
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: "clusterlifecycle-state-metrics-v2", Namespace: mce.Spec.TargetNamespace}, &corev1.Service{}); err != nil {
+		t.Errorf("expected the metrics service to be deployed: %v", err)
+	}
+
+	// A ServiceMonitor created by external tooling is left alone when the component is removed
+	external := &monitorv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+	if err := r.Client.Create(ctx, external); err != nil {
+		t.Fatalf("failed to create ServiceMonitor: %v", err)
+	}
+	if _, err := r.ensureNoClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureNoClusterLifecycle() error = %v", err)
+	}
+	if err := r.Client.Get(ctx, key, &monitorv1.ServiceMonitor{}); err != nil {
+		t.Errorf("expected the externally managed ServiceMonitor to be kept: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ensureNetworkPolicies(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+
+	s := newTestScheme()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine", UID: "test-uid"},
+		Spec: backplanev1.MultiClusterEngineSpec{
+			TargetNamespace: "multicluster-engine",
+			Overrides:       &backplanev1.Overrides{DeployNetworkPolicies: true},
+		},
+	}
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	ctx := context.Background()
+
+	if _, err := r.ensureNetworkPolicies(ctx, mce); err != nil {
+		t.Fatalf("ensureNetworkPolicies() error = %v", err)
+	}
+
+	policies := &networkingv1.NetworkPolicyList{}
+	if err := r.Client.List(ctx, policies, client.InNamespace("multicluster-engine")); err != nil {
+		t.Fatalf("failed to list NetworkPolicies: %v", err)
+	}
+	if len(policies.Items) == 0 {
+		t.Fatalf("expected NetworkPolicies to be created in the target namespace")
+	}
+
+	foundDefaultDeny := false
+	for _, np := range policies.Items {
+		owner := metav1.GetControllerOf(&np)
+		if owner == nil || owner.UID != mce.UID {
+			t.Errorf("expected NetworkPolicy %s to be owned by the MultiClusterEngine", np.Name)
+		}
+		if np.Name == "multicluster-engine-default-deny" {
+			foundDefaultDeny = true
+			if len(np.Spec.PodSelector.MatchLabels) != 0 || len(np.Spec.Ingress) != 0 {
+				t.Errorf("expected default deny policy to select all pods and allow no ingress")
+			}
+		}
+	}
+	if !foundDefaultDeny {
+		t.Errorf("expected default deny NetworkPolicy to be created")
+	}
+
+	mce.Spec.Overrides.DeployNetworkPolicies = false
+	if _, err := r.ensureNoNetworkPolicies(ctx, mce); err != nil {
+		t.Fatalf("ensureNoNetworkPolicies() error = %v", err)
+	}
+	if err := r.Client.List(ctx, policies, client.InNamespace("multicluster-engine")); err != nil {
+		t.Fatalf("failed to list NetworkPolicies: %v", err)
+	}
+	if len(policies.Items) != 0 {
+		t.Errorf("expected NetworkPolicies to be removed when disabled, found %d", len(policies.Items))
+	}
+}
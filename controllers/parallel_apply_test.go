@@ -0,0 +1,120 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_componentWaves(t *testing.T) {
+	dependencies := map[string][]string{
+		"b": {"a"},
+		"c": {"b"},
+		"d": {"a", "not-applied"},
+	}
+	tests := []struct {
+		name       string
+		components []string
+		want       [][]string
+	}{
+		{
+			name:       "independent components share a wave",
+			components: []string{"e", "f"},
+			want:       [][]string{{"e", "f"}},
+		},
+		{
+			name:       "dependents follow their dependencies",
+			components: []string{"c", "d", "b", "e", "a"},
+			want:       [][]string{{"e", "a"}, {"d", "b"}, {"c"}},
+		},
+		{
+			name:       "dependencies that are not applied are ignored",
+			components: []string{"c", "d"},
+			want:       [][]string{{"c", "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := componentWaves(tt.components, dependencies); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("componentWaves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Run with -race to check the components applied in parallel do not share unguarded state
+func Test_ensureToggleableComponentsParallel(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+
+	kinds := []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1", Kind: "DeploymentList"},
+		{Version: "v1", Kind: "ServiceList"},
+		{Version: "v1", Kind: "ServiceAccountList"},
+		{Version: "v1", Kind: "ConfigMapList"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleList"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBindingList"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleList"},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBindingList"},
+		{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinitionList"},
+	}
+
+	apply := func(workers int) ([]string, map[string]string) {
+		s := newTestScheme()
+		mce := newMonitoringTestMCE()
+		for _, component := range []string{
+			backplanev1.ManagedServiceAccount, backplanev1.ConsoleMCE, backplanev1.Discovery, backplanev1.Hive,
+			backplanev1.ClusterLifecycle, backplanev1.ClusterManager, backplanev1.ServerFoundation,
+			backplanev1.ClusterProxyAddon, backplanev1.LocalCluster,
+		} {
+			mce.Enable(component)
+		}
+		c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce,
+			&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}}).Build()
+		r := newTestReconciler(s, c)
+		r.Images = testImages()
+		r.ComponentApplyWorkers = workers
+		r.StatusManager.Reset("")
+
+		if _, err := r.ensureToggleableComponents(context.Background(), mce); err != nil {
+			t.Fatalf("ensureToggleableComponents() with %d workers error = %v", workers, err)
+		}
+
+		resources := []string{}
+		for _, gvk := range kinds {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk)
+			if err := c.List(context.Background(), list); err != nil {
+				t.Fatalf("failed to list %s: %v", gvk.Kind, err)
+			}
+			for _, item := range list.Items {
+				resources = append(resources, item.GetKind()+"/"+item.GetNamespace()+"/"+item.GetName())
+			}
+		}
+		sort.Strings(resources)
+		return resources, r.StatusManager.Images
+	}
+
+	serialResources, serialImages := apply(1)
+	parallelResources, parallelImages := apply(8)
+	if len(serialResources) == 0 {
+		t.Fatal("no resources applied")
+	}
+	if !reflect.DeepEqual(serialResources, parallelResources) {
+		t.Errorf("parallel apply created %v, want %v", parallelResources, serialResources)
+	}
+	if !reflect.DeepEqual(serialImages, parallelImages) {
+		t.Errorf("parallel apply recorded images %v, want %v", parallelImages, serialImages)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// forbiddenVerbRegexp extracts the denied verb from the message of a forbidden error, e.g.
+// `User "x" cannot patch resource "deployments" in API group "apps"`
+var forbiddenVerbRegexp = regexp.MustCompile(`cannot (\S+) resource`)
+
+// deniedPermission describes the verb and resource a forbidden error was returned for, such as
+// "patch deployments.apps". The resource falls back to the kind of the template when the error
+// carries no details.
+func deniedPermission(template *unstructured.Unstructured, err error) string {
+	verb := "patch"
+	if m := forbiddenVerbRegexp.FindStringSubmatch(err.Error()); m != nil {
+		verb = m[1]
+	}
+	resource := schema.GroupResource{Group: template.GroupVersionKind().Group, Resource: strings.ToLower(template.GetKind())}
+	var statusErr *apierrors.StatusError
+	if errors.As(err, &statusErr) && statusErr.ErrStatus.Details != nil && statusErr.ErrStatus.Details.Kind != "" {
+		resource = schema.GroupResource{Group: statusErr.ErrStatus.Details.Group, Resource: statusErr.ErrStatus.Details.Kind}
+	}
+	return fmt.Sprintf("%s %s", verb, resource.String())
+}
+
+// recordDeniedPermission records the permission a template could not be applied without
+func (r *MultiClusterEngineReconciler) recordDeniedPermission(template *unstructured.Unstructured, err error) {
+	r.recordPermission(deniedPermission(template, err))
+}
+
+// recordPermission records a denied permission unless it was already recorded
+func (r *MultiClusterEngineReconciler) recordPermission(permission string) {
+	for _, p := range r.deniedPermissions {
+		if p == permission {
+			return
+		}
+	}
+	r.deniedPermissions = append(r.deniedPermissions, permission)
+}
+
+// reportPermissions sets the InsufficientPermissions condition from the permissions denied while
+// applying the components. Returns false if any permission was denied.
+func (r *MultiClusterEngineReconciler) reportPermissions() bool {
+	if len(r.deniedPermissions) == 0 {
+		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineInsufficientPermissions,
+			metav1.ConditionFalse, status.PermissionsGrantedReason, "All component resources could be applied"))
+		return true
+	}
+	denied := append([]string{}, r.deniedPermissions...)
+	sort.Strings(denied)
+	msg := fmt.Sprintf("The operator is forbidden to %s", strings.Join(denied, ", "))
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineInsufficientPermissions,
+		metav1.ConditionTrue, status.PermissionsDeniedReason, msg))
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing,
+		metav1.ConditionFalse, status.PermissionsDeniedReason, msg))
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// forbiddenClient denies writes of Deployments the way the API server does for a service account
+// missing the RBAC permission
+type forbiddenClient struct {
+	client.Client
+}
+
+func (fc forbiddenClient) deny(obj client.Object) error {
+	if obj.GetObjectKind().GroupVersionKind().Kind != "Deployment" {
+		return nil
+	}
+	return apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, obj.GetName(),
+		errors.New(`User "system:serviceaccount:default:backplane-operator" cannot patch resource "deployments" in API group "apps"`))
+}
+
+func (fc forbiddenClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := fc.deny(obj); err != nil {
+		return err
+	}
+	return fc.Client.Create(ctx, obj, opts...)
+}
+
+func (fc forbiddenClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := fc.deny(obj); err != nil {
+		return err
+	}
+	return fc.Client.Update(ctx, obj, opts...)
+}
+
+func Test_reportInsufficientPermissions(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, forbiddenClient{Client: c})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	var cond *backplanev1.MultiClusterEngineCondition
+	for i := range got.Status.Conditions {
+		if got.Status.Conditions[i].Type == backplanev1.MultiClusterEngineInsufficientPermissions {
+			cond = &got.Status.Conditions[i]
+		}
+	}
+	if cond == nil {
+		t.Fatal("InsufficientPermissions condition not reported")
+	}
+	if cond.Status != metav1.ConditionTrue || cond.Reason != status.PermissionsDeniedReason {
+		t.Errorf("InsufficientPermissions condition = %v, want true with reason %s", cond, status.PermissionsDeniedReason)
+	}
+	if !strings.Contains(cond.Message, "patch deployments.apps") {
+		t.Errorf("InsufficientPermissions message = %q, want it to name the denied verb and resource", cond.Message)
+	}
+	// Resources the operator is allowed to apply are still applied
+	if err := c.Get(ctx, types.NamespacedName{Name: "cluster-manager", Namespace: "multicluster-engine"}, &corev1.ServiceAccount{}); err != nil {
+		t.Errorf("failed to get cluster-manager service account: %v", err)
+	}
+}
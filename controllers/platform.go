@@ -0,0 +1,75 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// PlatformOpenShift runs the operator with its OpenShift integrations
+	PlatformOpenShift = "openshift"
+	// PlatformKubernetes skips the integrations that need OpenShift APIs, such as reading the
+	// ClusterVersion, Infrastructure and Ingress configs, the console plugin and ServiceMonitors
+	PlatformKubernetes = "kubernetes"
+)
+
+// ParsePlatform validates the platform the operator runs on. An empty value defaults to
+// PlatformOpenShift.
+func ParsePlatform(value string) (string, error) {
+	switch value {
+	case "":
+		return PlatformOpenShift, nil
+	case PlatformOpenShift, PlatformKubernetes:
+		return value, nil
+	default:
+		return "", fmt.Errorf("%s is not a known platform. Must be one of %s, %s", value, PlatformOpenShift, PlatformKubernetes)
+	}
+}
+
+// onOpenShift returns true unless the operator runs on plain Kubernetes
+func (r *MultiClusterEngineReconciler) onOpenShift() bool {
+	return r.Platform != PlatformKubernetes
+}
+
+// getPlatformType returns the platform type of the cluster Infrastructure config, or an empty string
+// if the cluster has none or the operator does not run on OpenShift
+func (r *MultiClusterEngineReconciler) getPlatformType(ctx context.Context) (string, error) {
+	if !r.onOpenShift() {
+		return "", nil
+	}
+	infrastructure := &configv1.Infrastructure{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: "cluster"}, infrastructure)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if infrastructure.Status.PlatformStatus != nil && infrastructure.Status.PlatformStatus.Type != "" {
+		return string(infrastructure.Status.PlatformStatus.Type), nil
+	}
+	// Clusters installed before platformStatus was introduced only report the deprecated field
+	return string(infrastructure.Status.Platform), nil
+}
+
+// reportPlatform records the platform type of the cluster in the multiclusterengine status. The
+// platform is only informational, so a failure to read it keeps the previously reported value.
+func (r *MultiClusterEngineReconciler) reportPlatform(ctx context.Context, mce *backplanev1.MultiClusterEngine) {
+	platform, err := r.getPlatformType(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to detect the cluster platform")
+		return
+	}
+	mce.Status.Platform = platform
+}
@@ -0,0 +1,181 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// platformRequirement describes the clusters a component can function on
+type platformRequirement struct {
+	// platforms are the Infrastructure platform types the component supports. Any platform is
+	// supported when empty.
+	platforms []configv1.PlatformType
+	// capability is a cluster capability the component needs enabled, if any
+	capability configv1.ClusterVersionCapability
+	// deployment is the primary deployment of the component, whose presence shows the component
+	// is already installed
+	deployment string
+}
+
+// componentPlatformRequirements holds the requirements of the components that cannot function on
+// every cluster. Components without an entry are deployed anywhere.
+var componentPlatformRequirements = map[string]platformRequirement{
+	// Assisted installs provision bare metal and on-premise hosts booted from a discovery image,
+	// which cloud platforms do not allow
+	backplanev1.AssistedService: {
+		platforms: []configv1.PlatformType{
+			configv1.BareMetalPlatformType,
+			configv1.NonePlatformType,
+			configv1.VSpherePlatformType,
+			configv1.NutanixPlatformType,
+			configv1.OpenStackPlatformType,
+		},
+		deployment: "infrastructure-operator",
+	},
+	// Image based installs reboot BareMetalHosts into the installed image
+	backplanev1.ImageBasedInstallOperator: {
+		capability: configv1.ClusterVersionCapabilityBaremetal,
+		deployment: "image-based-install-operator",
+	},
+}
+
+// clusterCapabilities returns the known and enabled capabilities of the cluster. Both are empty if
+// the cluster does not report capabilities or the operator does not run on OpenShift.
+func (r *MultiClusterEngineReconciler) clusterCapabilities(ctx context.Context) (known, enabled map[configv1.ClusterVersionCapability]bool, err error) {
+	known = map[configv1.ClusterVersionCapability]bool{}
+	enabled = map[configv1.ClusterVersionCapability]bool{}
+	if !r.onOpenShift() {
+		return known, enabled, nil
+	}
+	clusterVersion := &configv1.ClusterVersion{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: "version"}, clusterVersion)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return known, enabled, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range clusterVersion.Status.Capabilities.KnownCapabilities {
+		known[c] = true
+	}
+	for _, c := range clusterVersion.Status.Capabilities.EnabledCapabilities {
+		enabled[c] = true
+	}
+	return known, enabled, nil
+}
+
+// unsupportedComponents returns the reason each component in names cannot function on the cluster,
+// keyed by component. An unknown platform or capability never makes a component unsupported.
+func (r *MultiClusterEngineReconciler) unsupportedComponents(ctx context.Context, mce *backplanev1.MultiClusterEngine, names []string) (map[string]string, error) {
+	platform := configv1.PlatformType(mce.Status.Platform)
+	var known, enabled map[configv1.ClusterVersionCapability]bool
+
+	unsupported := map[string]string{}
+	for _, name := range names {
+		req, ok := componentPlatformRequirements[name]
+		if !ok {
+			continue
+		}
+		if platform != "" && len(req.platforms) > 0 && !containsPlatform(req.platforms, platform) {
+			unsupported[name] = fmt.Sprintf("not supported on the %s platform", platform)
+			continue
+		}
+		if req.capability == "" {
+			continue
+		}
+		if known == nil {
+			var err error
+			known, enabled, err = r.clusterCapabilities(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if known[req.capability] && !enabled[req.capability] {
+			unsupported[name] = fmt.Sprintf("the %s capability is not enabled", req.capability)
+		}
+	}
+	return unsupported, nil
+}
+
+func containsPlatform(platforms []configv1.PlatformType, platform configv1.PlatformType) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// componentInstalled returns true if the primary deployment of a component exists, as it does
+// once the component was installed, e.g. before an upgrade or by a user enabling it
+func (r *MultiClusterEngineReconciler) componentInstalled(ctx context.Context, mce *backplanev1.MultiClusterEngine, name string) (bool, error) {
+	deployment := componentPlatformRequirements[name].deployment
+	if deployment == "" {
+		return false, nil
+	}
+	namespace := targetNamespace(mce)
+	if name == backplanev1.AssistedService && mce.Spec.Overrides != nil && mce.Spec.Overrides.InfrastructureCustomNamespace != "" {
+		namespace = mce.Spec.Overrides.InfrastructureCustomNamespace
+	}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: deployment, Namespace: namespace}, &appsv1.Deployment{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// skipUnsupportedComponents returns the enabled components whose installation is skipped because
+// the cluster cannot support them, and sets the UnsupportedOnPlatform condition naming them.
+// Components that are forced on or already installed are kept, so an upgrade never removes a
+// running component.
+func (r *MultiClusterEngineReconciler) skipUnsupportedComponents(ctx context.Context, mce *backplanev1.MultiClusterEngine, enabled []string) (map[string]bool, error) {
+	unsupported, err := r.unsupportedComponents(ctx, mce, enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := map[string]bool{}
+	reasons := []string{}
+	for name, reason := range unsupported {
+		if mce.ComponentForceEnabled(name) {
+			log.FromContext(ctx).Info(fmt.Sprintf("Deploying %s although it is %s, as it is force enabled", name, reason))
+			continue
+		}
+		installed, err := r.componentInstalled(ctx, mce, name)
+		if err != nil {
+			return nil, err
+		}
+		if installed {
+			log.FromContext(ctx).Info(fmt.Sprintf("Keeping %s although it is %s, as it is already installed", name, reason))
+			continue
+		}
+		skipped[name] = true
+		reasons = append(reasons, fmt.Sprintf("%s (%s)", name, reason))
+	}
+	if len(skipped) == 0 {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineUnsupportedOnPlatform)
+		return skipped, nil
+	}
+
+	sort.Strings(reasons)
+	msg := fmt.Sprintf("Enabled components are not installed as the cluster cannot support them: %s. Set forceEnable on a component to deploy it anyway",
+		strings.Join(reasons, ", "))
+	log.FromContext(ctx).Info(msg)
+	r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineUnsupportedOnPlatform, metav1.ConditionTrue, status.UnsupportedOnPlatformReason, msg))
+	return skipped, nil
+}
@@ -0,0 +1,184 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_unsupportedComponents(t *testing.T) {
+	tests := []struct {
+		name       string
+		platform   string
+		capability *configv1.ClusterVersionCapabilitiesStatus
+		want       []string
+	}{
+		{
+			name:     "cloud platform",
+			platform: "AWS",
+			want:     []string{backplanev1.AssistedService},
+		},
+		{
+			name:     "bare metal platform",
+			platform: "BareMetal",
+		},
+		{
+			name: "unknown platform",
+		},
+		{
+			name:     "baremetal capability disabled",
+			platform: "None",
+			capability: &configv1.ClusterVersionCapabilitiesStatus{
+				KnownCapabilities: []configv1.ClusterVersionCapability{configv1.ClusterVersionCapabilityBaremetal},
+			},
+			want: []string{backplanev1.ImageBasedInstallOperator},
+		},
+		{
+			name:     "baremetal capability enabled",
+			platform: "None",
+			capability: &configv1.ClusterVersionCapabilitiesStatus{
+				KnownCapabilities:   []configv1.ClusterVersionCapability{configv1.ClusterVersionCapabilityBaremetal},
+				EnabledCapabilities: []configv1.ClusterVersionCapability{configv1.ClusterVersionCapabilityBaremetal},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusterVersion := &configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}}
+			if tt.capability != nil {
+				clusterVersion.Status.Capabilities = *tt.capability
+			}
+			s := newTestScheme()
+			r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(clusterVersion).Build())
+			mce := &backplanev1.MultiClusterEngine{Status: backplanev1.MultiClusterEngineStatus{Platform: tt.platform}}
+
+			got, err := r.unsupportedComponents(context.Background(), mce,
+				[]string{backplanev1.AssistedService, backplanev1.ImageBasedInstallOperator, backplanev1.Hive})
+			if err != nil {
+				t.Fatalf("unsupportedComponents() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("unsupportedComponents() = %v, want %v", got, tt.want)
+			}
+			for _, name := range tt.want {
+				if _, ok := got[name]; !ok {
+					t.Errorf("unsupportedComponents() = %v, want %s unsupported", got, name)
+				}
+			}
+		})
+	}
+}
+
+func Test_skipAssistedServiceOnCloudPlatform(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	tests := []struct {
+		name        string
+		forceEnable bool
+		installed   bool
+		wantSkipped bool
+	}{
+		{
+			name:        "skipped",
+			wantSkipped: true,
+		},
+		{
+			name:        "force enabled",
+			forceEnable: true,
+		},
+		{
+			// An upgrade keeps the running component instead of removing it
+			name:      "already installed",
+			installed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mce := &backplanev1.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+				Spec: backplanev1.MultiClusterEngineSpec{
+					TargetNamespace: "multicluster-engine",
+					Overrides: &backplanev1.Overrides{
+						Components: []backplanev1.ComponentConfig{
+							{Name: backplanev1.AssistedService, Enabled: true, ForceEnable: tt.forceEnable},
+						},
+					},
+				},
+			}
+			objs := []client.Object{
+				mce,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+				&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+				&configv1.Infrastructure{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Status: configv1.InfrastructureStatus{
+						PlatformStatus: &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+					},
+				},
+			}
+			if tt.installed {
+				objs = append(objs, &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "infrastructure-operator", Namespace: "multicluster-engine"},
+				})
+			}
+			s := newTestScheme()
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+			r := newTestReconciler(s, c)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+			// The first passes add the finalizer and defaults, later ones apply the components
+			for pass := 0; pass < 3; pass++ {
+				if _, err := r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+			}
+
+			deployment := &appsv1.Deployment{}
+			err := c.Get(ctx, types.NamespacedName{Name: "infrastructure-operator", Namespace: "multicluster-engine"}, deployment)
+			if tt.wantSkipped && !apierrors.IsNotFound(err) {
+				t.Errorf("expected assisted-service not to be deployed on AWS, got error %v", err)
+			}
+			if !tt.wantSkipped && err != nil {
+				t.Errorf("failed to get infrastructure-operator deployment: %v", err)
+			}
+
+			got := &backplanev1.MultiClusterEngine{}
+			if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+				t.Fatalf("failed to get multiclusterengine: %v", err)
+			}
+			var condition *backplanev1.MultiClusterEngineCondition
+			for i := range got.Status.Conditions {
+				if got.Status.Conditions[i].Type == backplanev1.MultiClusterEngineUnsupportedOnPlatform {
+					condition = &got.Status.Conditions[i]
+				}
+			}
+			if tt.wantSkipped {
+				if condition == nil || condition.Status != metav1.ConditionTrue || !strings.Contains(condition.Message, backplanev1.AssistedService) {
+					t.Errorf("expected an UnsupportedOnPlatform condition naming %s, got %+v", backplanev1.AssistedService, condition)
+				}
+			} else if condition != nil {
+				t.Errorf("expected no UnsupportedOnPlatform condition, got %+v", condition)
+			}
+		})
+	}
+}
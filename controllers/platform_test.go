@@ -0,0 +1,153 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reportPlatform(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	tests := []struct {
+		name           string
+		infrastructure *configv1.Infrastructure
+		want           string
+	}{
+		{
+			name: "platform status",
+			infrastructure: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.InfrastructureStatus{
+					PlatformStatus: &configv1.PlatformStatus{Type: configv1.AWSPlatformType},
+				},
+			},
+			want: "AWS",
+		},
+		{
+			name: "deprecated platform",
+			infrastructure: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{Platform: configv1.BareMetalPlatformType},
+			},
+			want: "BareMetal",
+		},
+		{
+			name: "no infrastructure",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mce := &backplanev1.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+				Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+			}
+			objs := []client.Object{
+				mce,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+				&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+			}
+			if tt.infrastructure != nil {
+				objs = append(objs, tt.infrastructure)
+			}
+			s := newTestScheme()
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+			r := newTestReconciler(s, c)
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &backplanev1.MultiClusterEngine{}
+			if err := c.Get(ctx, types.NamespacedName{Name: mce.Name}, got); err != nil {
+				t.Fatalf("failed to get multiclusterengine: %v", err)
+			}
+			if got.Status.Platform != tt.want {
+				t.Errorf("Status.Platform = %q, want %q", got.Status.Platform, tt.want)
+			}
+		})
+	}
+}
+
+func Test_reconcileKubernetesPlatform(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	// The OpenShift config APIs are not registered, so any attempt to read them fails
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(apixv1.AddToScheme(s))
+	utilruntime.Must(backplanev1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	r.Platform = PlatformKubernetes
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "cluster-manager", Namespace: "multicluster-engine"}, deployment); err != nil {
+		t.Errorf("failed to get cluster-manager deployment: %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "console-mce-console", Namespace: "multicluster-engine"}, deployment); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the console not to be deployed off OpenShift, got error %v", err)
+	}
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	if got.Status.Platform != "" {
+		t.Errorf("Status.Platform = %q, want none", got.Status.Platform)
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	for value, want := range map[string]string{"": PlatformOpenShift, "openshift": PlatformOpenShift, "kubernetes": PlatformKubernetes} {
+		if got, err := ParsePlatform(value); err != nil || got != want {
+			t.Errorf("ParsePlatform(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParsePlatform("vsphere"); err == nil {
+		t.Error("ParsePlatform() expected an error for an unknown platform")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// validatePriorityClasses adds a PriorityClassUnavailable condition if a priority class referenced by
+// the overrides does not exist. Pods referencing a missing priority class are rejected at admission,
+// so components are still applied and the condition only serves as a warning.
+func (r *MultiClusterEngineReconciler) validatePriorityClasses(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) error {
+	missing := []string{}
+	for _, name := range backplaneConfig.PriorityClassNames() {
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name}, &schedulingv1.PriorityClass{})
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(missing) == 0 {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEnginePriorityClassUnavailable)
+		return nil
+	}
+
+	log.FromContext(ctx).Info(fmt.Sprintf("Priority classes not found: %s", strings.Join(missing, ", ")))
+	r.StatusManager.RemoveCondition(backplanev1.MultiClusterEnginePriorityClassUnavailable)
+	r.StatusManager.AddCondition(status.NewCondition(
+		backplanev1.MultiClusterEnginePriorityClassUnavailable,
+		metav1.ConditionTrue,
+		status.PriorityClassNotFoundReason,
+		fmt.Sprintf("Priority classes %s do not exist. Pods referencing them will not be created.", strings.Join(missing, ", ")),
+	))
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_validatePriorityClasses(t *testing.T) {
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	mce.Spec.Overrides = &backplanev1.Overrides{PriorityClassName: "backplane-critical"}
+	pc := &schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "backplane-critical"}, Value: 1000}
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	ctx := context.Background()
+
+	hasCondition := func() bool {
+		for _, c := range r.StatusManager.Conditions {
+			if c.Type == backplanev1.MultiClusterEnginePriorityClassUnavailable {
+				return true
+			}
+		}
+		return false
+	}
+
+	if err := r.validatePriorityClasses(ctx, mce); err != nil {
+		t.Fatalf("validatePriorityClasses() error = %v", err)
+	}
+	if !hasCondition() {
+		t.Errorf("expected %s condition when the priority class is missing", backplanev1.MultiClusterEnginePriorityClassUnavailable)
+	}
+
+	if err := r.Client.Create(ctx, pc); err != nil {
+		t.Fatalf("failed to create priority class: %v", err)
+	}
+	if err := r.validatePriorityClasses(ctx, mce); err != nil {
+		t.Fatalf("validatePriorityClasses() error = %v", err)
+	}
+	if hasCondition() {
+		t.Errorf("expected %s condition to be removed once the priority class exists", backplanev1.MultiClusterEnginePriorityClassUnavailable)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_validateNamespaceProtected(t *testing.T) {
+	tests := []struct {
+		name        string
+		namespace   string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{name: "kube-system", namespace: "kube-system", wantErr: true},
+		{name: "openshift namespace", namespace: "openshift-config", wantErr: true},
+		{name: "default", namespace: "default", wantErr: true},
+		{
+			name:        "allowed by annotation",
+			namespace:   "kube-system",
+			annotations: map[string]string{backplanev1.AnnotationAllowProtectedNamespace: "true"},
+		},
+		{name: "normal namespace", namespace: "multicluster-engine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheme()
+			mce := &backplanev1.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine", Annotations: tt.annotations},
+				Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: tt.namespace},
+			}
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tt.namespace}}
+			r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce, ns).Build())
+			r.StatusManager.Reset("")
+
+			_, err := r.validateNamespace(context.Background(), mce)
+			if tt.wantErr != errors.Is(err, backplanev1.ErrInvalidNamespace) {
+				t.Fatalf("validateNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("validateNamespace() error = %v", err)
+			}
+
+			rejected := false
+			for _, c := range r.StatusManager.Conditions {
+				if c.Type == backplanev1.MultiClusterEngineProgressing && c.Reason == status.ProtectedNamespaceReason {
+					rejected = true
+				}
+			}
+			if rejected != tt.wantErr {
+				t.Errorf("%s condition present = %v, want %v", status.ProtectedNamespaceReason, rejected, tt.wantErr)
+			}
+		})
+	}
+}
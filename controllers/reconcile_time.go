@@ -0,0 +1,57 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"time"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var lastReconcileTimestamp = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "backplane_operator_last_reconcile_timestamp_seconds",
+		Help: "Unix time of the last reconcile of a multiclusterengine that completed without error",
+	},
+)
+
+var phaseTransitionTimestamp = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "backplane_operator_phase_transition_timestamp_seconds",
+		Help: "Unix time a multiclusterengine last entered each phase",
+	},
+	[]string{"phase"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(lastReconcileTimestamp, phaseTransitionTimestamp)
+}
+
+// markReconciled records the current time as the last successful reconcile of the
+// multiclusterengine. The status still has to be written for it to be persisted.
+func markReconciled(mce *backplanev1.MultiClusterEngine) {
+	now := metav1.Now()
+	mce.Status.LastReconcileTime = &now
+}
+
+// reportReconciled updates the last reconcile gauge once the status recording a successful
+// reconcile has been written
+func reportReconciled(mce *backplanev1.MultiClusterEngine) {
+	if mce.Status.LastReconcileTime == nil {
+		return
+	}
+	lastReconcileTimestamp.Set(float64(mce.Status.LastReconcileTime.Unix()))
+}
+
+// reportPhaseTransition updates the phase transition gauge of the current phase of the
+// multiclusterengine once the status moving it out of the previous phase has been written
+func reportPhaseTransition(previous backplanev1.PhaseType, mce *backplanev1.MultiClusterEngine) {
+	if mce.Status.Phase == "" || mce.Status.Phase == previous {
+		return
+	}
+	phaseTransitionTimestamp.WithLabelValues(string(mce.Status.Phase)).Set(float64(time.Now().Unix()))
+}
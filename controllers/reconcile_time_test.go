@@ -0,0 +1,109 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_lastReconcileTime(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	previous := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+		Status:     backplanev1.MultiClusterEngineStatus{LastReconcileTime: &previous},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: mce.Name}, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	if got.Status.LastReconcileTime == nil || !got.Status.LastReconcileTime.After(previous.Time) {
+		t.Fatalf("LastReconcileTime = %v, want a time after %v", got.Status.LastReconcileTime, previous)
+	}
+	if gauge := testutil.ToFloat64(lastReconcileTimestamp); gauge != float64(got.Status.LastReconcileTime.Unix()) {
+		t.Errorf("last reconcile gauge = %v, want %v", gauge, got.Status.LastReconcileTime.Unix())
+	}
+}
+
+func Test_phaseTransitionTimestamp(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	start := time.Now().Truncate(time.Second)
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: mce.Name}, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	if got.Status.Phase == "" {
+		t.Fatal("multiclusterengine has no phase after reconcile")
+	}
+	gauge := phaseTransitionTimestamp.WithLabelValues(string(got.Status.Phase))
+	if ts := testutil.ToFloat64(gauge); ts < float64(start.Unix()) {
+		t.Errorf("%s phase transition gauge = %v, want a time from %v", got.Status.Phase, ts, start.Unix())
+	}
+
+	// Staying in a phase leaves the time it was entered
+	gauge.Set(0)
+	reportPhaseTransition(got.Status.Phase, got)
+	if ts := testutil.ToFloat64(gauge); ts != 0 {
+		t.Errorf("%s phase transition gauge = %v after no phase change, want 0", got.Status.Phase, ts)
+	}
+
+	previous := got.Status.Phase
+	got.Status.Phase = backplanev1.MultiClusterEnginePhaseAvailable
+	reportPhaseTransition(previous, got)
+	if ts := testutil.ToFloat64(phaseTransitionTimestamp.WithLabelValues("Available")); ts < float64(start.Unix()) {
+		t.Errorf("Available phase transition gauge = %v, want a time from %v", ts, start.Unix())
+	}
+}
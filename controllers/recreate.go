@@ -0,0 +1,66 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// recreateComponent deletes the resources of the component named by the recreate annotation so the
+// rest of the reconcile creates them anew. The annotation is removed before anything is deleted, so
+// a component is recreated at most once per annotation even if the deletion fails part way, in which
+// case the remaining resources are updated in place as usual. CustomResourceDefinitions are left
+// alone, since deleting them would delete every resource of their kind. Returns true if resources
+// were deleted.
+func (r *MultiClusterEngineReconciler) recreateComponent(ctx context.Context, mce *backplanev1.MultiClusterEngine) (bool, error) {
+	component := utils.GetRecreateComponent(mce)
+	if component == "" {
+		return false, nil
+	}
+	log := log.FromContext(ctx)
+
+	annotations := mce.GetAnnotations()
+	delete(annotations, utils.AnnotationRecreate)
+	mce.SetAnnotations(annotations)
+	if err := r.Client.Update(ctx, mce); err != nil {
+		return false, err
+	}
+
+	chartDir := ""
+	for _, c := range componentCharts {
+		if c.component == component {
+			chartDir = c.chartDir
+		}
+	}
+	if chartDir == "" || !mce.Enabled(component) || !r.manages(component) {
+		message := fmt.Sprintf("Ignoring request to recreate %s: not an enabled component with resources managed by the operator", component)
+		log.Info(message)
+		r.Recorder.Event(mce, corev1.EventTypeWarning, "RecreateIgnored", message)
+		return false, nil
+	}
+
+	templates, err := r.renderComponentChart(mce, component, chartDir)
+	if err != nil {
+		return false, err
+	}
+	log.Info(fmt.Sprintf("Recreating resources of component %s", component))
+	for _, template := range templates {
+		if template.GetKind() == "CustomResourceDefinition" {
+			continue
+		}
+		if _, err := r.deleteTemplate(ctx, mce, template); err != nil {
+			r.Recorder.Event(mce, corev1.EventTypeWarning, "RecreateFailed",
+				fmt.Sprintf("Failed to delete %s %s of component %s: %s", template.GetKind(), template.GetName(), component, err.Error()))
+			return true, err
+		}
+	}
+	r.Recorder.Event(mce, corev1.EventTypeNormal, "ComponentRecreated", fmt.Sprintf("Deleted the resources of component %s to recreate them", component))
+	return true, nil
+}
@@ -0,0 +1,144 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// uidClient assigns a new UID to every object it creates and keeps it across updates, as the API
+// server does
+type uidClient struct {
+	client.Client
+	created *int
+}
+
+func (uc uidClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	*uc.created++
+	obj.SetUID(types.UID(fmt.Sprintf("uid-%d", *uc.created)))
+	return uc.Client.Create(ctx, obj, opts...)
+}
+
+func (uc uidClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if obj.GetUID() == "" {
+		existing := &unstructured.Unstructured{}
+		gvk, err := apiutil.GVKForObject(obj, uc.Scheme())
+		if err != nil {
+			return err
+		}
+		existing.SetGroupVersionKind(gvk)
+		if err := uc.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err == nil {
+			obj.SetUID(existing.GetUID())
+		}
+	}
+	return uc.Client.Update(ctx, obj, opts...)
+}
+
+func Test_recreateComponent(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+		Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+	}
+	s := newTestScheme()
+	created := 0
+	c := uidClient{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build(), created: &created}
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	deploymentUID := func(name string) types.UID {
+		t.Helper()
+		d := &appsv1.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "multicluster-engine"}, d); err != nil {
+			t.Fatalf("failed to get deployment %s: %v", name, err)
+		}
+		return d.UID
+	}
+	setAnnotation := func(value string) {
+		t.Helper()
+		got := &backplanev1.MultiClusterEngine{}
+		if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatalf("failed to get multiclusterengine: %v", err)
+		}
+		got.SetAnnotations(map[string]string{utils.AnnotationRecreate: value})
+		if err := c.Update(ctx, got); err != nil {
+			t.Fatalf("failed to update multiclusterengine: %v", err)
+		}
+	}
+
+	for pass := 0; pass < 3; pass++ {
+		reconcile()
+	}
+	controllerUID := deploymentUID("ocm-controller")
+	otherUID := deploymentUID("cluster-manager")
+
+	setAnnotation(backplanev1.ServerFoundation)
+	reconcile()
+
+	recreatedUID := deploymentUID("ocm-controller")
+	if recreatedUID == controllerUID {
+		t.Error("ocm-controller deployment was not recreated")
+	}
+	if uid := deploymentUID("cluster-manager"); uid != otherUID {
+		t.Error("cluster-manager deployment of another component was recreated")
+	}
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	if _, ok := got.GetAnnotations()[utils.AnnotationRecreate]; ok {
+		t.Error("recreate annotation was not removed")
+	}
+
+	// Later reconciles leave the recreated component in place
+	reconcile()
+	if uid := deploymentUID("ocm-controller"); uid != recreatedUID {
+		t.Error("ocm-controller deployment was recreated more than once")
+	}
+
+	// Unknown components are ignored and the annotation still removed
+	setAnnotation("not-a-component")
+	reconcile()
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	if _, ok := got.GetAnnotations()[utils.AnnotationRecreate]; ok {
+		t.Error("recreate annotation naming an unknown component was not removed")
+	}
+	if uid := deploymentUID("ocm-controller"); uid != recreatedUID {
+		t.Error("ocm-controller deployment was recreated for an unknown component")
+	}
+}
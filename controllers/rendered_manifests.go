@@ -0,0 +1,187 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	renderer "github.com/stolostron/backplane-operator/pkg/rendering"
+	"github.com/stolostron/backplane-operator/pkg/toggle"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+// renderedManifestsKey is the configmap key holding the rendered manifests as a multi-document YAML
+const renderedManifestsKey = "manifests.yaml"
+
+// componentCharts are the charts rendered for each toggleable component when it is enabled
+var componentCharts = []struct {
+	component string
+	chartDir  string
+}{
+	{backplanev1.ManagedServiceAccount, toggle.ManagedServiceAccountChartDir},
+	{backplanev1.HyperShift, toggle.HyperShiftChartDir},
+	{backplanev1.ConsoleMCE, toggle.ConsoleMCEChartsDir},
+	{backplanev1.Discovery, toggle.DiscoveryChartDir},
+	{backplanev1.Hive, toggle.HiveChartDir},
+	{backplanev1.AssistedService, toggle.AssistedServiceChartDir},
+	{backplanev1.ClusterLifecycle, toggle.ClusterLifecycleChartDir},
+	{backplanev1.ClusterManager, toggle.ClusterManagerChartDir},
+	{backplanev1.ServerFoundation, toggle.ServerFoundationChartDir},
+	{backplanev1.ClusterProxyAddon, toggle.ClusterProxyAddonDir},
+	{backplanev1.ImageBasedInstallOperator, toggle.ImageBasedInstallOperatorChartDir},
+}
+
+// renderedManifestsName returns the name of the configmap the rendered manifests of the
+// multiclusterengine are written to
+func renderedManifestsName(mce *backplanev1.MultiClusterEngine) string {
+	return mce.Name + "-rendered"
+}
+
+// renderComponentChart renders the chart of a toggleable component the way the component renders it
+// when it is ensured
+func (r *MultiClusterEngineReconciler) renderComponentChart(mce *backplanev1.MultiClusterEngine, component, chartDir string) ([]*unstructured.Unstructured, error) {
+	var templates []*unstructured.Unstructured
+	var errs []error
+	if component == backplanev1.AssistedService && mce.Spec.Overrides != nil && mce.Spec.Overrides.InfrastructureCustomNamespace != "" {
+		templates, errs = renderer.RenderChartWithNamespace(chartDir, mce, r.Images, mce.Spec.Overrides.InfrastructureCustomNamespace)
+	} else {
+		templates, errs = renderer.RenderChart(chartDir, mce, r.Images)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error rendering %s: %v", chartDir, errs)
+	}
+	return templates, nil
+}
+
+// renderManifests renders the charts the operator applies for the multiclusterengine, with all
+// overrides, the same way each component renders them when it is ensured. Manifests are sorted
+// by kind, namespace and name.
+func (r *MultiClusterEngineReconciler) renderManifests(ctx context.Context, mce *backplanev1.MultiClusterEngine) ([]*unstructured.Unstructured, error) {
+	ocpConsole, err := r.CheckConsole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, errs := renderer.RenderCharts(renderer.AlwaysChartsDir, mce, r.Images)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error rendering %s: %v", renderer.AlwaysChartsDir, errs)
+	}
+	for _, c := range componentCharts {
+		if !mce.Enabled(c.component) || !r.manages(c.component) {
+			continue
+		}
+		if c.component == backplanev1.ConsoleMCE && !ocpConsole {
+			continue
+		}
+
+		templates, err := r.renderComponentChart(mce, c.component, c.chartDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, template := range templates {
+			if c.component == backplanev1.ServerFoundation && !mce.Enabled(backplanev1.ServerFoundationProxy) && isProxyServerTemplate(template) {
+				continue
+			}
+			manifests = append(manifests, template)
+		}
+	}
+	if utils.NetworkPoliciesEnabled(mce) {
+		templates, errs := renderer.RenderChart(toggle.NetworkPoliciesChartDir, mce, r.Images)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("error rendering %s: %v", toggle.NetworkPoliciesChartDir, errs)
+		}
+		manifests = append(manifests, templates...)
+	}
+
+	sort.SliceStable(manifests, func(i, j int) bool {
+		a, b := manifests[i], manifests[j]
+		if a.GetKind() != b.GetKind() {
+			return a.GetKind() < b.GetKind()
+		}
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+	return manifests, nil
+}
+
+// ensureRenderedManifests writes the rendered manifests of the multiclusterengine to a configmap in
+// the operator namespace while the renderedManifests annotation is set, and removes the configmap
+// otherwise
+func (r *MultiClusterEngineReconciler) ensureRenderedManifests(ctx context.Context, mce *backplanev1.MultiClusterEngine) error {
+	log := log.FromContext(ctx)
+	namespace, err := utils.GetOperatorNamespace()
+	if err != nil {
+		return err
+	}
+	key := types.NamespacedName{Name: renderedManifestsName(mce), Namespace: namespace}
+
+	existing := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, key, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	if !utils.RenderedManifestsEnabled(mce) {
+		if !found {
+			return nil
+		}
+		log.Info(fmt.Sprintf("Removing rendered manifests configmap %s", key.Name))
+		if err := r.Client.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	manifests, err := r.renderManifests(ctx, mce)
+	if err != nil {
+		return err
+	}
+	docs := []string{}
+	for _, m := range manifests {
+		b, err := yaml.Marshal(m.Object)
+		if err != nil {
+			return fmt.Errorf("error converting %s %s to yaml: %w", m.GetKind(), m.GetName(), err)
+		}
+		docs = append(docs, string(b))
+	}
+	data := map[string]string{renderedManifestsKey: strings.Join(docs, "---\n")}
+
+	if found {
+		if reflect.DeepEqual(existing.Data, data) {
+			return nil
+		}
+		existing.Data = data
+		return r.Client.Update(ctx, existing)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    map[string]string{"backplaneconfig.name": mce.Name},
+		},
+		Data: data,
+	}
+	if err := ctrl.SetControllerReference(mce, cm, r.Scheme); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Writing rendered manifests to configmap %s", key.Name))
+	return r.Client.Create(ctx, cm)
+}
@@ -0,0 +1,88 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ensureRenderedManifests(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	ctx := context.Background()
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "multiclusterengine",
+			Annotations: map[string]string{utils.AnnotationRenderedManifests: "true"},
+		},
+		Spec: backplanev1.MultiClusterEngineSpec{
+			TargetNamespace: "multicluster-engine",
+			Overrides: &backplanev1.Overrides{
+				Components: []backplanev1.ComponentConfig{{Name: backplanev1.Discovery, Enabled: false}},
+			},
+		},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	cmKey := types.NamespacedName{Name: "multiclusterengine-rendered", Namespace: "default"}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, cmKey, cm); err != nil {
+		t.Fatalf("failed to get rendered manifests configmap: %v", err)
+	}
+	manifests := cm.Data[renderedManifestsKey]
+	for _, name := range []string{"cluster-manager", "ocm-controller", "managedcluster-import-controller-v2"} {
+		if !strings.Contains(manifests, "kind: Deployment") || !strings.Contains(manifests, "name: "+name+"\n") {
+			t.Errorf("rendered manifests do not contain deployment %s", name)
+		}
+	}
+	if strings.Contains(manifests, "name: discovery-operator\n") {
+		t.Error("rendered manifests contain the disabled discovery-operator")
+	}
+
+	// Removing the annotation removes the configmap
+	got := &backplanev1.MultiClusterEngine{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("failed to get multiclusterengine: %v", err)
+	}
+	got.SetAnnotations(nil)
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update multiclusterengine: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, cmKey, cm); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the rendered manifests configmap to be removed, got error %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_statusReplicaCounts(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	ctx := context.Background()
+	s := newTestScheme()
+	mce := newMonitoringTestMCE()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build()
+	r := newTestReconciler(s, c)
+	r.StatusManager.Reset("")
+	r.Images = testImages()
+
+	if _, err := r.ensureClusterLifecycle(ctx, mce); err != nil {
+		t.Fatalf("ensureClusterLifecycle() error = %v", err)
+	}
+
+	// One replica of every rendered deployment becomes ready
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.InNamespace(mce.Spec.TargetNamespace)); err != nil {
+		t.Fatalf("failed to list deployments: %v", err)
+	}
+	if len(deployments.Items) == 0 {
+		t.Fatalf("no deployments rendered")
+	}
+	wantTotal, wantReady := int32(0), int32(0)
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		wantTotal += replicas
+		wantReady++
+		d.Status.ReadyReplicas = 1
+		if err := c.Status().Update(ctx, d); err != nil {
+			t.Fatalf("failed to update deployment status: %v", err)
+		}
+	}
+
+	status := r.StatusManager.ReportStatus(*mce)
+	if status.TotalReplicas != wantTotal {
+		t.Errorf("TotalReplicas = %d, want %d", status.TotalReplicas, wantTotal)
+	}
+	if status.ReadyReplicas != wantReady {
+		t.Errorf("ReadyReplicas = %d, want %d", status.ReadyReplicas, wantReady)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// MinResyncPeriod is the shortest allowed ResyncPeriod, below which the periodic requeues would
+	// load the API server for little benefit
+	MinResyncPeriod = 5 * time.Second
+	// MaxResyncPeriod is the longest allowed ResyncPeriod, beyond which a component waiting on a
+	// prerequisite would go unnoticed for too long
+	MaxResyncPeriod = time.Hour
+)
+
+// ValidateResyncPeriod returns an error if the resync period is outside of MinResyncPeriod and
+// MaxResyncPeriod
+func ValidateResyncPeriod(period time.Duration) error {
+	if period < MinResyncPeriod || period > MaxResyncPeriod {
+		return fmt.Errorf("resync period %s must be between %s and %s", period, MinResyncPeriod, MaxResyncPeriod)
+	}
+	return nil
+}
+
+// resyncPeriod returns the period after which a multiclusterengine that is not yet available, or
+// waiting on a prerequisite, is reconciled again
+func (r *MultiClusterEngineReconciler) resyncPeriod() time.Duration {
+	if r.ResyncPeriod <= 0 {
+		return requeuePeriod
+	}
+	return r.ResyncPeriod
+}
@@ -0,0 +1,80 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateResyncPeriod(t *testing.T) {
+	tests := []struct {
+		period  time.Duration
+		wantErr bool
+	}{
+		{period: MinResyncPeriod},
+		{period: 10 * time.Minute},
+		{period: MaxResyncPeriod},
+		{period: time.Second, wantErr: true},
+		{period: 0, wantErr: true},
+		{period: -time.Minute, wantErr: true},
+		{period: 2 * time.Hour, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := ValidateResyncPeriod(tt.period); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateResyncPeriod(%s) error = %v, wantErr %v", tt.period, err, tt.wantErr)
+		}
+	}
+}
+
+func Test_resyncPeriod(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	tests := []struct {
+		name         string
+		resyncPeriod time.Duration
+		want         time.Duration
+	}{
+		{name: "default", want: requeuePeriod},
+		{name: "configured", resyncPeriod: 10 * time.Minute, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mce := newMonitoringTestMCE()
+			s := newTestScheme()
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+				mce,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+				&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+			).Build()
+			r := newTestReconciler(s, c)
+			r.ResyncPeriod = tt.resyncPeriod
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+			// The components are not available yet, so the multiclusterengine is requeued
+			result, err := r.Reconcile(ctx, req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if result.RequeueAfter != tt.want {
+				t.Errorf("Reconcile() requeued after %s, want %s", result.RequeueAfter, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newAPIService() *unstructured.Unstructured {
+	apiService := &unstructured.Unstructured{}
+	apiService.SetAPIVersion("apiregistration.k8s.io/v1")
+	apiService.SetKind("APIService")
+	return apiService
+}
+
+func Test_ensureServerFoundationWithoutProxyServer(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	ctx := context.Background()
+	mce := newMonitoringTestMCE()
+	mce.Spec.Overrides = &backplanev1.Overrides{
+		Components: []backplanev1.ComponentConfig{
+			{Name: backplanev1.ServerFoundation, Enabled: true},
+			{Name: backplanev1.ServerFoundationProxy, Enabled: true},
+		},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build()
+	r := newTestReconciler(s, c)
+	r.Images = testImages()
+	r.StatusManager.Reset("")
+
+	proxyObjects := []client.Object{
+		&appsv1.Deployment{},
+		&corev1.Service{},
+	}
+	proxyAPIServices := []string{"v1beta1.proxy.open-cluster-management.io", "v1.clusterview.open-cluster-management.io"}
+
+	if _, err := r.ensureServerFoundation(ctx, mce); err != nil {
+		t.Fatalf("ensureServerFoundation() error = %v", err)
+	}
+	for _, obj := range proxyObjects {
+		if err := c.Get(ctx, types.NamespacedName{Name: "ocm-proxyserver", Namespace: "multicluster-engine"}, obj); err != nil {
+			t.Fatalf("failed to get proxyserver %T: %v", obj, err)
+		}
+	}
+	for _, name := range proxyAPIServices {
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, newAPIService()); err != nil {
+			t.Fatalf("failed to get APIService %s: %v", name, err)
+		}
+	}
+
+	mce.Disable(backplanev1.ServerFoundationProxy)
+	if _, err := r.ensureServerFoundation(ctx, mce); err != nil {
+		t.Fatalf("ensureServerFoundation() error = %v", err)
+	}
+	for _, obj := range proxyObjects {
+		err := c.Get(ctx, types.NamespacedName{Name: "ocm-proxyserver", Namespace: "multicluster-engine"}, obj)
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("proxyserver %T still exists with the proxyserver disabled: %v", obj, err)
+		}
+	}
+	for _, name := range proxyAPIServices {
+		err := c.Get(ctx, types.NamespacedName{Name: name}, newAPIService())
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("APIService %s still exists with the proxyserver disabled: %v", name, err)
+		}
+	}
+
+	// The rest of the server foundation stays
+	for _, name := range []string{"ocm-controller", "ocm-webhook"} {
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "multicluster-engine"}, &appsv1.Deployment{}); err != nil {
+			t.Errorf("failed to get deployment %s: %v", name, err)
+		}
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "ocm-webhook", Namespace: "multicluster-engine"}, &corev1.Service{}); err != nil {
+		t.Errorf("failed to get the ocm-webhook service: %v", err)
+	}
+}
@@ -155,6 +155,9 @@ var _ = BeforeSuite(func() {
 		Client:        k8sManager.GetClient(),
 		Scheme:        k8sManager.GetScheme(),
 		StatusManager: &status.StatusTracker{Client: k8sManager.GetClient()},
+		Recorder:      k8sManager.GetEventRecorderFor("multiclusterengine-controller"),
+		// No controller makes the deployments of the test environment available
+		ComponentDependencies: map[string][]string{},
 	}
 	err = (reconciler).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
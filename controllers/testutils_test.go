@@ -0,0 +1,84 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// applyClient emulates server-side apply patches, which the fake client does not support,
+// with a create or update of the whole object
+type applyClient struct {
+	client.Client
+}
+
+func (ac applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return ac.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, ac.Client.Scheme())
+	if err != nil {
+		return err
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+	err = ac.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if apierrors.IsNotFound(err) {
+		return ac.Client.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+	// Like the API server, honor a resourceVersion sent with the apply as a precondition
+	if rv := obj.GetResourceVersion(); rv != "" && rv != existing.GetResourceVersion() {
+		return apierrors.NewConflict(gvk.GroupVersion().WithResource(gvk.Kind).GroupResource(), obj.GetName(),
+			errors.New("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return ac.Client.Update(ctx, obj)
+}
+
+// newTestScheme returns a scheme with the types the reconciler reads and writes, leaving out
+// optional APIs such as the Prometheus operator types
+func newTestScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(apixv1.AddToScheme(s))
+	utilruntime.Must(backplanev1.AddToScheme(s))
+	utilruntime.Must(configv1.AddToScheme(s))
+	return s
+}
+
+// newTestReconciler returns a reconciler backed by a fake client that supports apply patches
+func newTestReconciler(s *runtime.Scheme, c client.Client) *MultiClusterEngineReconciler {
+	ac := applyClient{Client: c}
+	return &MultiClusterEngineReconciler{
+		Client:        ac,
+		Scheme:        s,
+		StatusManager: &status.StatusTracker{Client: ac},
+		Recorder:      record.NewFakeRecorder(100),
+		// Deployments never become available with the fake client, so components are applied
+		// without waiting on their prerequisites
+		ComponentDependencies: map[string][]string{},
+
+		drift:          &driftTracker{},
+		imageOverrides: &imageOverrideCache{},
+		clusterVersion: &clusterVersionCache{},
+	}
+}
@@ -21,10 +21,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -44,7 +42,7 @@ func (r *MultiClusterEngineReconciler) ensureConsoleMCE(ctx context.Context, bac
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -60,7 +58,7 @@ func (r *MultiClusterEngineReconciler) ensureConsoleMCE(ctx context.Context, bac
 	err := r.Client.Get(ctx, namespacedName, consoleDeployment)
 	if err != nil {
 		log.Info("Failed to get console-mce deployment for addon. Requeuing.")
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 	for _, dc := range consoleDeployment.Status.Conditions {
 		if dc.Type == appsv1.DeploymentAvailable && dc.Status == corev1.ConditionTrue {
@@ -69,7 +67,7 @@ func (r *MultiClusterEngineReconciler) ensureConsoleMCE(ctx context.Context, bac
 	}
 
 	log.Info("MCE console is not yet available. Waiting to enable console plugin")
-	return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+	return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 }
 
 func (r *MultiClusterEngineReconciler) ensureNoConsoleMCE(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine, ocpConsole bool) (ctrl.Result, error) {
@@ -88,7 +86,7 @@ func (r *MultiClusterEngineReconciler) ensureNoConsoleMCE(ctx context.Context, b
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
@@ -124,7 +122,7 @@ func (r *MultiClusterEngineReconciler) ensureManagedServiceAccount(ctx context.C
 			for _, err := range errs {
 				log.Info(err.Error())
 			}
-			return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 		}
 
 		// Apply all CRDs
@@ -134,6 +132,13 @@ func (r *MultiClusterEngineReconciler) ensureManagedServiceAccount(ctx context.C
 				return result, err
 			}
 		}
+		established, err := r.crdsEstablished(ctx, backplanev1.ManagedServiceAccount)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !established {
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+		}
 
 		// Renders all templates from charts
 		chartPath := toggle.ManagedServiceAccountChartDir
@@ -142,7 +147,7 @@ func (r *MultiClusterEngineReconciler) ensureManagedServiceAccount(ctx context.C
 			for _, err := range errs {
 				log.Info(err.Error())
 			}
-			return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 		}
 
 		// Applies all templates
@@ -166,7 +171,7 @@ func (r *MultiClusterEngineReconciler) ensureNoManagedServiceAccount(ctx context
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(types.NamespacedName{Name: "managed-serviceaccount-addon-manager", Namespace: backplaneConfig.Spec.TargetNamespace}))
@@ -192,7 +197,7 @@ func (r *MultiClusterEngineReconciler) ensureNoManagedServiceAccount(ctx context
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Delete all CRDs
@@ -286,7 +291,7 @@ func (r *MultiClusterEngineReconciler) ensureDiscovery(ctx context.Context, back
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -310,7 +315,7 @@ func (r *MultiClusterEngineReconciler) ensureNoDiscovery(ctx context.Context, ba
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
@@ -339,7 +344,7 @@ func (r *MultiClusterEngineReconciler) ensureHive(ctx context.Context, backplane
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -350,7 +355,7 @@ func (r *MultiClusterEngineReconciler) ensureHive(ctx context.Context, backplane
 		}
 	}
 
-	hiveTemplate := hive.HiveConfig(backplaneConfig)
+	hiveTemplate := r.withPreferredVersion(hive.HiveConfig(backplaneConfig))
 	if err := ctrl.SetControllerReference(backplaneConfig, hiveTemplate, r.Scheme); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "Error setting controller reference on resource %s", hiveTemplate.GetName())
 	}
@@ -373,22 +378,22 @@ func (r *MultiClusterEngineReconciler) ensureNoHive(ctx context.Context, backpla
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
 	r.StatusManager.AddComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
 
 	// Delete hivconfig
-	hiveConfig := hive.HiveConfig(backplaneConfig)
+	hiveConfig := r.withPreferredVersion(hive.HiveConfig(backplaneConfig))
 	err := r.Client.Get(ctx, types.NamespacedName{Name: "hive"}, hiveConfig)
 	if err == nil { // If resource exists, delete
 		err := r.Client.Delete(ctx, hiveConfig)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: requeuePeriod}, err
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 		}
 	} else if err != nil && !apierrors.IsNotFound(err) {
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Deletes all templates
@@ -419,7 +424,7 @@ func (r *MultiClusterEngineReconciler) ensureAssistedService(ctx context.Context
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -448,7 +453,7 @@ func (r *MultiClusterEngineReconciler) ensureNoAssistedService(ctx context.Conte
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
@@ -469,9 +474,15 @@ func (r *MultiClusterEngineReconciler) ensureServerFoundation(ctx context.Contex
 	namespacedName := types.NamespacedName{Name: "ocm-controller", Namespace: backplaneConfig.Spec.TargetNamespace}
 	r.StatusManager.RemoveComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
 	r.StatusManager.AddComponent(toggle.EnabledStatus(namespacedName))
-	namespacedName = types.NamespacedName{Name: "ocm-proxyserver", Namespace: backplaneConfig.Spec.TargetNamespace}
-	r.StatusManager.RemoveComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
-	r.StatusManager.AddComponent(toggle.EnabledStatus(namespacedName))
+	proxyEnabled := backplaneConfig.Enabled(backplanev1.ServerFoundationProxy)
+	namespacedName = types.NamespacedName{Name: proxyServerName, Namespace: backplaneConfig.Spec.TargetNamespace}
+	if proxyEnabled {
+		r.StatusManager.RemoveComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
+		r.StatusManager.AddComponent(toggle.EnabledStatus(namespacedName))
+	} else {
+		r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
+		r.StatusManager.AddComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
+	}
 	namespacedName = types.NamespacedName{Name: "ocm-webhook", Namespace: backplaneConfig.Spec.TargetNamespace}
 	r.StatusManager.RemoveComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
 	r.StatusManager.AddComponent(toggle.EnabledStatus(namespacedName))
@@ -483,11 +494,27 @@ func (r *MultiClusterEngineReconciler) ensureServerFoundation(ctx context.Contex
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
-	// Applies all templates
+	if err := r.injectWebhookCABundle(ctx, backplaneConfig, templates); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineProgressing, metav1.ConditionFalse, status.RequirementsNotMetReason,
+				fmt.Sprintf("Could not find webhook TLS secret %s in namespace %s", backplaneConfig.WebhookTLSSecretName(), backplaneConfig.Spec.TargetNamespace)))
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
+	}
+
+	// Applies all templates, removing those of the proxyserver when it is disabled
 	for _, template := range templates {
+		if !proxyEnabled && isProxyServerTemplate(template) {
+			result, err := r.deleteTemplate(ctx, backplaneConfig, template)
+			if err != nil {
+				log.Error(err, fmt.Sprintf("Failed to delete template: %s", template.GetName()))
+				return result, err
+			}
+			continue
+		}
 		result, err := r.applyTemplate(ctx, backplaneConfig, template)
 		if err != nil {
 			return result, err
@@ -497,6 +524,22 @@ func (r *MultiClusterEngineReconciler) ensureServerFoundation(ctx context.Contex
 	return ctrl.Result{}, nil
 }
 
+// proxyServerName is the name of the ocm-proxyserver Deployment and Service
+const proxyServerName = "ocm-proxyserver"
+
+// isProxyServerTemplate returns true if the server foundation template belongs to the
+// ocm-proxyserver: its Deployment and Service, and the APIServices the Service backs
+func isProxyServerTemplate(template *unstructured.Unstructured) bool {
+	switch template.GetKind() {
+	case "Deployment", "Service":
+		return template.GetName() == proxyServerName
+	case "APIService":
+		service, _, _ := unstructured.NestedString(template.Object, "spec", "service", "name")
+		return service == proxyServerName
+	}
+	return false
+}
+
 func (r *MultiClusterEngineReconciler) ensureNoServerFoundation(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
@@ -506,13 +549,13 @@ func (r *MultiClusterEngineReconciler) ensureNoServerFoundation(ctx context.Cont
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	namespacedName := types.NamespacedName{Name: "ocm-controller", Namespace: backplaneConfig.Spec.TargetNamespace}
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
 	r.StatusManager.AddComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
-	namespacedName = types.NamespacedName{Name: "ocm-proxyserver", Namespace: backplaneConfig.Spec.TargetNamespace}
+	namespacedName = types.NamespacedName{Name: proxyServerName, Namespace: backplaneConfig.Spec.TargetNamespace}
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
 	r.StatusManager.AddComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
 	namespacedName = types.NamespacedName{Name: "ocm-webhook", Namespace: backplaneConfig.Spec.TargetNamespace}
@@ -551,7 +594,7 @@ func (r *MultiClusterEngineReconciler) ensureClusterLifecycle(ctx context.Contex
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -562,6 +605,10 @@ func (r *MultiClusterEngineReconciler) ensureClusterLifecycle(ctx context.Contex
 		}
 	}
 
+	if err := r.removeMovedServiceMonitors(ctx, backplaneConfig, templates); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -574,7 +621,7 @@ func (r *MultiClusterEngineReconciler) ensureNoClusterLifecycle(ctx context.Cont
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	namespacedName := types.NamespacedName{Name: "cluster-curator-controller", Namespace: backplaneConfig.Spec.TargetNamespace}
@@ -613,7 +660,7 @@ func (r *MultiClusterEngineReconciler) ensureClusterManager(ctx context.Context,
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -625,12 +672,14 @@ func (r *MultiClusterEngineReconciler) ensureClusterManager(ctx context.Context,
 	}
 
 	// Apply clustermanager
-	cmTemplate := foundation.ClusterManager(backplaneConfig, r.Images)
+	cmTemplate := r.withPreferredVersion(foundation.ClusterManager(backplaneConfig, r.Images))
 	if err := ctrl.SetControllerReference(backplaneConfig, cmTemplate, r.Scheme); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "Error setting controller reference on resource %s", cmTemplate.GetName())
 	}
-	force := true
-	err := r.Client.Patch(ctx, cmTemplate, client.Apply, &client.PatchOptions{Force: &force, FieldManager: "backplane-operator"})
+	if r.desiredUnchanged && r.matchesLive(ctx, cmTemplate) {
+		return ctrl.Result{}, nil
+	}
+	err := r.applyObject(ctx, cmTemplate, backplaneFieldManager)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "error applying object Name: %s Kind: %s", cmTemplate.GetName(), cmTemplate.GetKind())
 	}
@@ -648,7 +697,7 @@ func (r *MultiClusterEngineReconciler) ensureNoClusterManager(ctx context.Contex
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
@@ -659,31 +708,25 @@ func (r *MultiClusterEngineReconciler) ensureNoClusterManager(ctx context.Contex
 
 	// Delete clustermanager
 	clusterManager := &unstructured.Unstructured{}
-	clusterManager.SetGroupVersionKind(
-		schema.GroupVersionKind{
-			Group:   "operator.open-cluster-management.io",
-			Version: "v1",
-			Kind:    "ClusterManager",
-		},
-	)
+	clusterManager.SetGroupVersionKind(utils.PreferredGVK(r.Client.RESTMapper(), foundation.ClusterManagerGVK))
 	err := r.Client.Get(ctx, types.NamespacedName{Name: "cluster-manager"}, clusterManager)
 	if err == nil { // If resource exists, delete
 		err := r.Client.Delete(ctx, clusterManager)
 		if err != nil {
-			return ctrl.Result{RequeueAfter: requeuePeriod}, err
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 		}
 	} else if err != nil && !apierrors.IsNotFound(err) { // Return error, if error is not not found error
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
 	// Verify clustermanager namespace deleted
 	ocmHubNamespace := &corev1.Namespace{}
 	err = r.Client.Get(ctx, types.NamespacedName{Name: "open-cluster-management-hub"}, ocmHubNamespace)
 	if err == nil {
-		return ctrl.Result{RequeueAfter: requeuePeriod}, fmt.Errorf("waiting for 'open-cluster-management-hub' namespace to be terminated before proceeding with clustermanager cleanup")
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, fmt.Errorf("waiting for 'open-cluster-management-hub' namespace to be terminated before proceeding with clustermanager cleanup")
 	}
 	if err != nil && !apierrors.IsNotFound(err) { // Return error, if error is not not found error
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
 	// Deletes all templates
@@ -709,7 +752,7 @@ func (r *MultiClusterEngineReconciler) ensureHyperShift(ctx context.Context, bac
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -734,7 +777,7 @@ func (r *MultiClusterEngineReconciler) ensureNoHyperShift(ctx context.Context, b
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Deletes all templates
@@ -763,7 +806,7 @@ func (r *MultiClusterEngineReconciler) ensureClusterProxyAddon(ctx context.Conte
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	// Applies all templates
@@ -791,9 +834,79 @@ func (r *MultiClusterEngineReconciler) ensureNoClusterProxyAddon(ctx context.Con
 		for _, err := range errs {
 			log.Info(err.Error())
 		}
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
+	// Deletes all templates
+	for _, template := range templates {
+		result, err := r.deleteTemplate(ctx, backplaneConfig, template)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Failed to delete template: %s", template.GetName()))
+			return result, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *MultiClusterEngineReconciler) ensureImageBasedInstallOperator(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+	namespacedName := types.NamespacedName{Name: "image-based-install-operator", Namespace: backplaneConfig.Spec.TargetNamespace}
+	r.StatusManager.RemoveComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
+	r.StatusManager.AddComponent(toggle.EnabledStatus(namespacedName))
+
+	log := log.FromContext(ctx)
+
+	// Render CRD templates
+	crds, errs := renderer.RenderCRDs(toggle.ImageBasedInstallOperatorCRDPath)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Info(err.Error())
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
+	// Apply all CRDs
+	for _, crd := range crds {
+		result, err := r.applyTemplate(ctx, backplaneConfig, crd)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	templates, errs := renderer.RenderChart(toggle.ImageBasedInstallOperatorChartDir, backplaneConfig, r.Images)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Info(err.Error())
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
+	// Applies all templates
+	for _, template := range templates {
+		result, err := r.applyTemplate(ctx, backplaneConfig, template)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *MultiClusterEngineReconciler) ensureNoImageBasedInstallOperator(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	namespacedName := types.NamespacedName{Name: "image-based-install-operator", Namespace: backplaneConfig.Spec.TargetNamespace}
+
+	// Renders all templates from charts
+	templates, errs := renderer.RenderChart(toggle.ImageBasedInstallOperatorChartDir, backplaneConfig, r.Images)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Info(err.Error())
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
+	r.StatusManager.RemoveComponent(toggle.EnabledStatus(namespacedName))
+	r.StatusManager.AddComponent(toggle.DisabledStatus(namespacedName, []*unstructured.Unstructured{}))
+
 	// Deletes all templates
 	for _, template := range templates {
 		result, err := r.deleteTemplate(ctx, backplaneConfig, template)
@@ -802,24 +915,52 @@ func (r *MultiClusterEngineReconciler) ensureNoClusterProxyAddon(ctx context.Con
 			return result, err
 		}
 	}
+
+	// Render CRD templates
+	crds, errs := renderer.RenderCRDs(toggle.ImageBasedInstallOperatorCRDPath)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Info(err.Error())
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
+	// Delete all CRDs
+	for _, crd := range crds {
+		result, err := r.deleteTemplate(ctx, backplaneConfig, crd)
+		if err != nil {
+			log.Error(err, "Failed to delete CRD")
+			return result, err
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
 // Checks if OCP Console is enabled and return true if so. If <OCP v4.12, always return true
-// Otherwise check in the EnabledCapabilities spec for OCP console
+// Otherwise check in the EnabledCapabilities spec for OCP console. Always false off OpenShift.
+// While the ClusterVersion cannot be read the version and capabilities last read are used. The
+// console is only assumed to be present if they were never read, as it always is before OCP 4.12.
 func (r *MultiClusterEngineReconciler) CheckConsole(ctx context.Context) (bool, error) {
-	versionStatus := &configv1.ClusterVersion{}
-	err := r.Client.Get(ctx, types.NamespacedName{Name: "version"}, versionStatus)
-	if err != nil {
-		return false, err
-	}
-	ocpVersion, err := r.getClusterVersion(ctx)
-	if err != nil {
-		return false, err
+	if !r.onOpenShift() {
+		return false, nil
+	}
+	ocpVersion := r.clusterVersion.get()
+	capabilities, capabilitiesRead := r.clusterVersion.getCapabilities()
+	clusterVersion := &configv1.ClusterVersion{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: "version"}, clusterVersion); err == nil {
+		if version, err := clusterVersionOf(clusterVersion); err == nil {
+			ocpVersion = version
+			r.clusterVersion.set(version)
+		}
+		capabilities, capabilitiesRead = clusterVersion.Status.Capabilities.EnabledCapabilities, true
+		r.clusterVersion.setCapabilities(capabilities)
 	}
-	if hubOCPVersion, ok := os.LookupEnv("ACM_HUB_OCP_VERSION"); ok {
+	if hubOCPVersion, ok := os.LookupEnv("ACM_HUB_OCP_VERSION"); ok && hubOCPVersion != "" {
 		ocpVersion = hubOCPVersion
 	}
+	if ocpVersion == "" || !capabilitiesRead {
+		return true, nil
+	}
 	semverVersion, err := semver.NewVersion(ocpVersion)
 	if err != nil {
 		return false, fmt.Errorf("failed to convert ocp version to semver compatible value: %w", err)
@@ -834,7 +975,7 @@ func (r *MultiClusterEngineReconciler) CheckConsole(ctx context.Context) (bool,
 	if !constraint.Check(semverVersion) {
 		return true, nil
 	}
-	for _, v := range versionStatus.Status.Capabilities.EnabledCapabilities {
+	for _, v := range capabilities {
 		if v == "Console" {
 			return true, nil
 		}
@@ -868,10 +1009,11 @@ func (r *MultiClusterEngineReconciler) ensureLocalCluster(ctx context.Context, m
 		err := r.Client.Get(ctx, types.NamespacedName{Name: localNS.GetName()}, localNS)
 		if err == nil {
 			log.Info("Waiting on local cluster namespace to be removed before creating ManagedCluster CR", "Namespace", localNS.GetName())
-			return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+			return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 		} else if apierrors.IsNotFound(err) {
 			log.Info("Local cluster namespace does not exist. Creating ManagedCluster CR")
 			managedCluster = utils.NewManagedCluster()
+			utils.AddClusterScopedLabels(managedCluster, mce.Name)
 			err := r.Client.Create(ctx, managedCluster)
 			if err != nil {
 				log.Error(err, "Failed to create ManagedCluster CR")
@@ -884,7 +1026,7 @@ func (r *MultiClusterEngineReconciler) ensureLocalCluster(ctx context.Context, m
 		}
 	} else if err != nil {
 		log.Error(err, "Failed to get ManagedCluster CR")
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 
 	log.Info("Setting annotations on ManagedCluster CR")
@@ -936,7 +1078,7 @@ func (r *MultiClusterEngineReconciler) ensureNoLocalCluster(ctx context.Context,
 		log.Info("ManagedCluster CR has been removed")
 	} else if err != nil {
 		log.Error(err, "Failed to get ManagedCluster CR")
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	} else {
 		log.Info("Deleting ManagedCluster CR")
 		managedCluster = utils.NewManagedCluster()
@@ -957,7 +1099,7 @@ func (r *MultiClusterEngineReconciler) ensureNoLocalCluster(ctx context.Context,
 		)
 		r.StatusManager.AddCondition(condition)
 		log.Info(msg)
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	log.Info("Check if managed cluster namespace exists")
@@ -968,7 +1110,7 @@ func (r *MultiClusterEngineReconciler) ensureNoLocalCluster(ctx context.Context,
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "Failed to get managed cluster namespace")
-		return ctrl.Result{RequeueAfter: requeuePeriod}, err
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, err
 	}
 	log.Info("Managed cluster namespace still exists")
 
@@ -990,5 +1132,49 @@ func (r *MultiClusterEngineReconciler) ensureNoLocalCluster(ctx context.Context,
 	)
 	r.StatusManager.AddCondition(condition)
 	log.Info(msg)
-	return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+	return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+}
+
+func (r *MultiClusterEngineReconciler) ensureNetworkPolicies(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	templates, errs := renderer.RenderChart(toggle.NetworkPoliciesChartDir, backplaneConfig, r.Images)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Info(err.Error())
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
+	// Applies all templates
+	for _, template := range templates {
+		result, err := r.applyTemplate(ctx, backplaneConfig, template)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *MultiClusterEngineReconciler) ensureNoNetworkPolicies(ctx context.Context, backplaneConfig *backplanev1.MultiClusterEngine) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	templates, errs := renderer.RenderChart(toggle.NetworkPoliciesChartDir, backplaneConfig, r.Images)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Info(err.Error())
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
+	}
+
+	// Deletes all templates
+	for _, template := range templates {
+		result, err := r.deleteTemplate(ctx, backplaneConfig, template)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Failed to delete NetworkPolicy template: %s", template.GetName()))
+			return result, err
+		}
+	}
+	return ctrl.Result{}, nil
 }
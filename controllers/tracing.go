@@ -0,0 +1,37 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/stolostron/backplane-operator/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// traced runs a phase of the reconcile in a span of the given name
+func traced(ctx context.Context, name string, phase func(context.Context) (ctrl.Result, error), attrs ...attribute.KeyValue) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, name, attrs...)
+	result, err := phase(ctx)
+	endSpan(span, result, err)
+	return result, err
+}
+
+// endSpan records the outcome of a reconcile or of one of its phases and ends its span
+func endSpan(span trace.Span, result ctrl.Result, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(tracing.OutcomeKey.String(tracing.OutcomeError))
+	case result != (ctrl.Result{}):
+		span.SetAttributes(tracing.OutcomeKey.String(tracing.OutcomeRequeue))
+	default:
+		span.SetAttributes(tracing.OutcomeKey.String(tracing.OutcomeSuccess))
+	}
+	span.End()
+}
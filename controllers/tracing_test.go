@@ -0,0 +1,117 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/tracing"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, a := range span.Attributes {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func Test_reconcileSpans(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:test")
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx := context.Background()
+	mce := newMonitoringTestMCE()
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		mce,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+		&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+	).Build()
+	r := newTestReconciler(s, c)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+	// The first passes add the finalizer and defaults, later ones apply the components
+	for pass := 0; pass < 3; pass++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	spans := exporter.GetSpans()
+	byName := map[string][]tracetest.SpanStub{}
+	for _, span := range spans {
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+
+	reconciles := byName["Reconcile"]
+	if len(reconciles) != 3 {
+		t.Fatalf("got %d Reconcile spans, want 3", len(reconciles))
+	}
+	for _, span := range reconciles {
+		if name, _ := spanAttribute(span, tracing.MultiClusterEngineKey); name.AsString() != mce.Name {
+			t.Errorf("Reconcile span %s attribute = %q, want %q", tracing.MultiClusterEngineKey, name.AsString(), mce.Name)
+		}
+		if _, ok := spanAttribute(span, tracing.OutcomeKey); !ok {
+			t.Errorf("Reconcile span has no %s attribute", tracing.OutcomeKey)
+		}
+	}
+
+	for _, phase := range []string{"AdoptExistingSubcomponents", "DeployAlwaysSubcomponents", "EnsureToggleableComponents"} {
+		if len(byName[phase]) == 0 {
+			t.Errorf("no %s span recorded", phase)
+		}
+		for _, span := range byName[phase] {
+			if !isChildOfAny(span, reconciles) {
+				t.Errorf("%s span is not a child of a Reconcile span", phase)
+			}
+		}
+	}
+
+	var clusterManager *tracetest.SpanStub
+	for i, span := range byName["EnsureComponent"] {
+		if component, _ := spanAttribute(span, tracing.ComponentKey); component.AsString() == backplanev1.ClusterManager {
+			clusterManager = &byName["EnsureComponent"][i]
+		}
+	}
+	if clusterManager == nil {
+		t.Fatalf("no EnsureComponent span recorded for %s", backplanev1.ClusterManager)
+	}
+	if outcome, _ := spanAttribute(*clusterManager, tracing.OutcomeKey); outcome.AsString() != tracing.OutcomeSuccess {
+		t.Errorf("EnsureComponent span for %s outcome = %q, want %q", backplanev1.ClusterManager, outcome.AsString(), tracing.OutcomeSuccess)
+	}
+}
+
+func isChildOfAny(span tracetest.SpanStub, parents []tracetest.SpanStub) bool {
+	for _, p := range parents {
+		if span.Parent.SpanID() == p.SpanContext.SpanID() {
+			return true
+		}
+	}
+	return false
+}
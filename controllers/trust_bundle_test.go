@@ -0,0 +1,83 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTrustBundleTestMCE(disabled bool) *backplanev1.MultiClusterEngine {
+	return &backplanev1.MultiClusterEngine{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "multicluster.openshift.io/v1", Kind: "MultiClusterEngine"},
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine", UID: "test-uid"},
+		Spec: backplanev1.MultiClusterEngineSpec{
+			TargetNamespace: "multicluster-engine",
+			Overrides:       &backplanev1.Overrides{DisableTrustBundle: disabled},
+		},
+	}
+}
+
+func Test_ensureTrustBundleDisabled(t *testing.T) {
+	s := newTestScheme()
+	mce := newTrustBundleTestMCE(true)
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	ctx := context.Background()
+
+	if _, err := r.ensureTrustBundle(ctx, mce); err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+	err := r.Client.Get(ctx, trustBundleNamespacedName(mce), &corev1.ConfigMap{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected trust bundle configmap not to be created, got err %v", err)
+	}
+}
+
+func Test_ensureTrustBundleRemovesCreatedBundle(t *testing.T) {
+	s := newTestScheme()
+	mce := newTrustBundleTestMCE(false)
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build())
+	ctx := context.Background()
+
+	if _, err := r.ensureTrustBundle(ctx, mce); err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+	if err := r.Client.Get(ctx, trustBundleNamespacedName(mce), &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("expected trust bundle configmap to be created: %v", err)
+	}
+
+	mce.Spec.Overrides.DisableTrustBundle = true
+	if _, err := r.ensureTrustBundle(ctx, mce); err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+	err := r.Client.Get(ctx, trustBundleNamespacedName(mce), &corev1.ConfigMap{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected trust bundle configmap to be removed, got err %v", err)
+	}
+}
+
+func Test_ensureTrustBundleKeepsUnownedBundle(t *testing.T) {
+	s := newTestScheme()
+	mce := newTrustBundleTestMCE(true)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: defaultTrustBundleName, Namespace: "multicluster-engine"}}
+	r := newTestReconciler(s, fake.NewClientBuilder().WithScheme(s).WithObjects(mce, cm).Build())
+	ctx := context.Background()
+
+	result, err := r.ensureTrustBundle(ctx, mce)
+	if err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+	if result != (ctrl.Result{}) {
+		t.Errorf("ensureTrustBundle() result = %#v, want ctrl.Result{}", result)
+	}
+	if err := r.Client.Get(ctx, trustBundleNamespacedName(mce), &corev1.ConfigMap{}); err != nil {
+		t.Errorf("expected configmap not created by the operator to be kept: %v", err)
+	}
+}
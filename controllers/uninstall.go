@@ -78,7 +78,7 @@ func (r *MultiClusterEngineReconciler) ensureRemovalsGone(backplaneConfig *backp
 	}
 
 	if !allResourcesDeleted {
-		return ctrl.Result{RequeueAfter: requeuePeriod}, nil
+		return ctrl.Result{RequeueAfter: r.resyncPeriod()}, nil
 	}
 
 	return ctrl.Result{}, nil
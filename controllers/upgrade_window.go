@@ -0,0 +1,63 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// upgradeWindowRequeuePeriod is how often the upgrade window is checked while upgrades are deferred
+const upgradeWindowRequeuePeriod = time.Minute
+
+// deferImageUpgrades returns the images to deploy the components with. Outside the upgrade window
+// set on the multiclusterengine, images that changed since the components were last deployed are
+// held back to the deployed ones and a WaitingForUpgradeWindow condition is added. Images of
+// components deployed for the first time are never held back. Returns true if an upgrade is
+// deferred.
+func (r *MultiClusterEngineReconciler) deferImageUpgrades(ctx context.Context, mce *backplanev1.MultiClusterEngine, images map[string]string) (map[string]string, bool) {
+	window := utils.GetUpgradeWindow(mce)
+	changed := []string{}
+	for key, image := range images {
+		if deployed, ok := mce.Status.DeployedImages[key]; ok && deployed != image {
+			changed = append(changed, key)
+		}
+	}
+	if window == "" || len(changed) == 0 {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineWaitingForUpgradeWindow)
+		return images, false
+	}
+
+	w, err := utils.ParseUpgradeWindow(window)
+	if err != nil {
+		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineWaitingForUpgradeWindow,
+			metav1.ConditionTrue, status.InvalidUpgradeWindowReason, err.Error()))
+	} else if w.Contains(time.Now()) {
+		r.StatusManager.RemoveCondition(backplanev1.MultiClusterEngineWaitingForUpgradeWindow)
+		return images, false
+	} else {
+		r.StatusManager.AddCondition(status.NewCondition(backplanev1.MultiClusterEngineWaitingForUpgradeWindow,
+			metav1.ConditionTrue, status.OutsideUpgradeWindowReason,
+			fmt.Sprintf("Upgrading %d images is deferred until the upgrade window %s opens", len(changed), window)))
+	}
+
+	sort.Strings(changed)
+	log.FromContext(ctx).Info("Deferring image upgrades until the upgrade window", "window", window, "images", changed)
+	held := map[string]string{}
+	for key, image := range images {
+		held[key] = image
+	}
+	for _, key := range changed {
+		held[key] = mce.Status.DeployedImages[key]
+	}
+	return held, true
+}
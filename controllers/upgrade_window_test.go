@@ -0,0 +1,120 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_deferImageUpgrades(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("UNIT_TEST", "true")
+	deployedImages := map[string]string{}
+	for _, key := range utils.GetTestImages() {
+		t.Setenv("OPERAND_IMAGE_"+strings.ToUpper(key), "quay.io/test/test:new")
+		deployedImages[key] = "quay.io/test/test:old"
+	}
+
+	// Windows in UTC relative to now, an hour either side of it or starting in an hour
+	now := time.Now().UTC()
+	window := func(from, to time.Duration) string {
+		return now.Add(from).Format("15:04") + "-" + now.Add(to).Format("15:04")
+	}
+	tests := []struct {
+		name      string
+		window    string
+		wantImage string
+		deferred  bool
+	}{
+		{name: "no window", window: "", wantImage: "quay.io/test/test:new"},
+		{name: "inside window", window: window(-time.Hour, time.Hour), wantImage: "quay.io/test/test:new"},
+		{name: "outside window", window: window(time.Hour, 2*time.Hour), wantImage: "quay.io/test/test:old", deferred: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mce := &backplanev1.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengine"},
+				Spec:       backplanev1.MultiClusterEngineSpec{TargetNamespace: "multicluster-engine"},
+				Status: backplanev1.MultiClusterEngineStatus{
+					DeployedImages: deployedImages,
+					CurrentVersion: "0.0.1",
+				},
+			}
+			if tt.window != "" {
+				mce.SetAnnotations(map[string]string{utils.AnnotationUpgradeWindow: tt.window})
+			}
+			s := newTestScheme()
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+				mce,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "multicluster-engine"}},
+				&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}},
+			).Build()
+			r := newTestReconciler(s, c)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mce.Name}}
+
+			// The first passes add the finalizer and defaults, later ones apply the components
+			var result ctrl.Result
+			for pass := 0; pass < 3; pass++ {
+				var err error
+				if result, err = r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+			}
+
+			deployment := &appsv1.Deployment{}
+			if err := c.Get(ctx, types.NamespacedName{Name: "cluster-manager", Namespace: "multicluster-engine"}, deployment); err != nil {
+				t.Fatalf("failed to get cluster-manager deployment: %v", err)
+			}
+			if got := deployment.Spec.Template.Spec.Containers[0].Image; got != tt.wantImage {
+				t.Errorf("cluster-manager image = %s, want %s", got, tt.wantImage)
+			}
+
+			got := &backplanev1.MultiClusterEngine{}
+			if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+				t.Fatalf("failed to get multiclusterengine: %v", err)
+			}
+			var cond backplanev1.MultiClusterEngineCondition
+			found := false
+			for _, c := range got.Status.Conditions {
+				if c.Type == backplanev1.MultiClusterEngineWaitingForUpgradeWindow {
+					cond, found = c, true
+				}
+			}
+			if found != tt.deferred {
+				t.Errorf("WaitingForUpgradeWindow condition present = %v, want %v", found, tt.deferred)
+			}
+			if tt.deferred {
+				if cond.Reason != status.OutsideUpgradeWindowReason {
+					t.Errorf("WaitingForUpgradeWindow reason = %s, want %s", cond.Reason, status.OutsideUpgradeWindowReason)
+				}
+				if got.Status.DeployedImages[utils.GetTestImages()[0]] != "quay.io/test/test:old" {
+					t.Errorf("deployed images = %v, want the old images", got.Status.DeployedImages)
+				}
+				if got.Status.CurrentVersion != "0.0.1" {
+					t.Errorf("Status.CurrentVersion = %s, want the previous version while the upgrade is deferred", got.Status.CurrentVersion)
+				}
+				if result.RequeueAfter == 0 {
+					t.Error("expected a requeue to check the upgrade window again")
+				}
+			} else if got.Status.DeployedImages[utils.GetTestImages()[0]] != "quay.io/test/test:new" {
+				t.Errorf("deployed images = %v, want the new images", got.Status.DeployedImages)
+			}
+		})
+	}
+}
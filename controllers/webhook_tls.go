@@ -0,0 +1,64 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// webhookCAKey is the key of the CA certificate in an externally managed webhook TLS secret
+const webhookCAKey = "ca.crt"
+
+// injectWebhookCABundle sets the CA of the externally managed ocm-webhook TLS secret as the caBundle
+// of the webhook configurations among templates, taking the place of the service CA injection.
+// Returns an error if the secret does not exist.
+func (r *MultiClusterEngineReconciler) injectWebhookCABundle(ctx context.Context, mce *backplanev1.MultiClusterEngine, templates []*unstructured.Unstructured) error {
+	name := mce.WebhookTLSSecretName()
+	if name == "" {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: mce.Spec.TargetNamespace}, secret); err != nil {
+		return err
+	}
+	ca := secret.Data[webhookCAKey]
+	if len(ca) == 0 {
+		// The CA bundle is left to be injected by whatever manages the secret
+		return nil
+	}
+
+	for _, template := range templates {
+		if template.GetKind() != "ValidatingWebhookConfiguration" && template.GetKind() != "MutatingWebhookConfiguration" {
+			continue
+		}
+		webhooks, found, err := unstructured.NestedSlice(template.Object, "webhooks")
+		if err != nil {
+			return fmt.Errorf("unable to read webhooks of %s %s: %w", template.GetKind(), template.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		for i := range webhooks {
+			webhook, ok := webhooks[i].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("unable to read webhooks of %s %s", template.GetKind(), template.GetName())
+			}
+			if err := unstructured.SetNestedField(webhook, base64.StdEncoding.EncodeToString(ca), "clientConfig", "caBundle"); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedSlice(template.Object, webhooks, "webhooks"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
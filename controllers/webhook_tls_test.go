@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/status"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ensureServerFoundationWebhookTLS(t *testing.T) {
+	t.Setenv("DIRECTORY_OVERRIDE", "../")
+	t.Setenv("POD_NAMESPACE", "default")
+
+	ctx := context.Background()
+	mce := newMonitoringTestMCE()
+	mce.Spec.Overrides = &backplanev1.Overrides{
+		Webhook: &backplanev1.WebhookConfig{Port: 9443, TLSSecretName: "webhook-tls"},
+	}
+	s := newTestScheme()
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(mce).Build()
+	r := newTestReconciler(s, c)
+	r.Images = testImages()
+	r.StatusManager.Reset("")
+
+	// The externally managed secret does not exist yet
+	if _, err := r.ensureServerFoundation(ctx, mce); err == nil {
+		t.Fatal("ensureServerFoundation() error = nil, want an error while the webhook TLS secret is missing")
+	}
+	missing := false
+	for _, cond := range r.StatusManager.Conditions {
+		if cond.Type == backplanev1.MultiClusterEngineProgressing && cond.Reason == status.RequirementsNotMetReason {
+			missing = true
+		}
+	}
+	if !missing {
+		t.Errorf("expected a %s condition for the missing webhook TLS secret", status.RequirementsNotMetReason)
+	}
+
+	ca := []byte("-----BEGIN CERTIFICATE-----\ntest\n-----END CERTIFICATE-----\n")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-tls", Namespace: "multicluster-engine"},
+		Data:       map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key"), webhookCAKey: ca},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+	if _, err := r.ensureServerFoundation(ctx, mce); err != nil {
+		t.Fatalf("ensureServerFoundation() error = %v", err)
+	}
+
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "ocm-validating-webhook"}, webhook); err != nil {
+		t.Fatalf("failed to get validating webhook configuration: %v", err)
+	}
+	clientConfig := webhook.Webhooks[0].ClientConfig
+	if clientConfig.Service == nil || clientConfig.Service.Port == nil || *clientConfig.Service.Port != 9443 {
+		t.Errorf("webhook service port = %v, want 9443", clientConfig.Service)
+	}
+	if string(clientConfig.CABundle) != string(ca) {
+		t.Errorf("webhook caBundle = %q, want the ca.crt of the secret %q", clientConfig.CABundle, base64.StdEncoding.EncodeToString(ca))
+	}
+
+	service := &corev1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "ocm-webhook", Namespace: "multicluster-engine"}, service); err != nil {
+		t.Fatalf("failed to get webhook service: %v", err)
+	}
+	if service.Spec.Ports[0].Port != 9443 {
+		t.Errorf("webhook service port = %d, want 9443", service.Spec.Ports[0].Port)
+	}
+}
@@ -43,7 +43,11 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	hiveconfig "github.com/openshift/hive/apis/hive/v1"
+	"github.com/stolostron/backplane-operator/pkg/images"
+	"github.com/stolostron/backplane-operator/pkg/leaderelection"
+	"github.com/stolostron/backplane-operator/pkg/logging"
 	"github.com/stolostron/backplane-operator/pkg/status"
+	"github.com/stolostron/backplane-operator/pkg/tracing"
 	"github.com/stolostron/backplane-operator/pkg/version"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -58,6 +62,7 @@ import (
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	backplanev1beta1 "github.com/stolostron/backplane-operator/api/v1beta1"
 	"github.com/stolostron/backplane-operator/controllers"
 	clustermanager "open-cluster-management.io/api/operator/v1"
 	//+kubebuilder:scaffold:imports
@@ -66,6 +71,9 @@ import (
 const (
 	crdName = "multiclusterengines.multicluster.openshift.io"
 	crdsDir = "pkg/templates/crds"
+
+	// webhookServiceName is the service in front of the operator's webhook server
+	webhookServiceName = "multicluster-engine-operator-webhook-service"
 )
 
 var (
@@ -82,6 +90,8 @@ func init() {
 
 	utilruntime.Must(backplanev1.AddToScheme(scheme))
 
+	utilruntime.Must(backplanev1beta1.AddToScheme(scheme))
+
 	utilruntime.Must(apiregistrationv1.AddToScheme(scheme))
 
 	utilruntime.Must(admissionregistration.AddToScheme(scheme))
@@ -105,22 +115,117 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var logFormat string
+	var managedComponents string
+	var maxConcurrentReconciles int
+	var componentApplyWorkers int
+	var resyncPeriod time.Duration
+	var degradedGracePeriod time.Duration
+	var installTimeout time.Duration
+	var adminAddr string
+	var platform string
+	var leaderElection leaderelection.Config
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&adminAddr, "admin-bind-address", "127.0.0.1:8082",
+		fmt.Sprintf("The address the admin endpoint binds to. Set to 0 to disable it. Requests must carry the bearer token "+
+			"in the %s environment variable, or come from the loopback interface when it is not set.", controllers.AdminTokenEnvVar))
 	flag.BoolVar(&enableLeaderElection, "leader-elect", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&logFormat, "log-format", logging.FormatConsole,
+		fmt.Sprintf("The log encoding format. One of: %s, %s.", logging.FormatJSON, logging.FormatConsole))
+	flag.StringVar(&managedComponents, "managed-components", "",
+		"Comma-separated list of components to reconcile. Components not listed are neither created nor deleted. "+
+			"All components are reconciled when empty.")
+	flag.StringVar(&platform, "platform", os.Getenv("PLATFORM"),
+		fmt.Sprintf("The platform the operator runs on. One of: %s, %s. Integrations needing OpenShift APIs are skipped on %s. "+
+			"Defaults to the PLATFORM environment variable, then %s.", controllers.PlatformOpenShift, controllers.PlatformKubernetes,
+			controllers.PlatformKubernetes, controllers.PlatformOpenShift))
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of MultiClusterEngines that can be reconciled at the same time.")
+	flag.IntVar(&componentApplyWorkers, "component-apply-workers", 4,
+		"The number of components without dependencies on each other that are applied at the same time.")
+	flag.DurationVar(&resyncPeriod, "resync-period", 15*time.Second,
+		fmt.Sprintf("How often a MultiClusterEngine that is not yet available, or waits on a prerequisite, is reconciled again. "+
+			"Must be between %s and %s.", controllers.MinResyncPeriod, controllers.MaxResyncPeriod))
+	flag.DurationVar(&degradedGracePeriod, "degraded-grace-period", status.DefaultDegradedGracePeriod,
+		"How long components of an available MultiClusterEngine must be unavailable before it is reported as Degraded "+
+			"rather than Progressing. Zero reports it right away.")
+	flag.DurationVar(&installTimeout, "install-timeout", 0,
+		"How long after its creation a MultiClusterEngine that has never been available is reported as Failed. "+
+			"Installing is still retried. Zero never times out.")
+	flag.StringVar(&images.ManifestPath, "manifest-path", "",
+		fmt.Sprintf("Path of a JSON image manifest file whose images override those from the environment. "+
+			"Defaults to the %s environment variable.", images.ManifestPathEnvVar))
+	leaderElection.BindFlags(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	encoderOpts, err := logging.EncoderOpts(logFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts), encoderOpts))
+
+	if err := leaderElection.Validate(); err != nil {
+		setupLog.Error(err, "invalid leader election configuration")
+		os.Exit(1)
+	}
+
+	components, err := controllers.ParseManagedComponents(managedComponents)
+	if err != nil {
+		setupLog.Error(err, "invalid managed components")
+		os.Exit(1)
+	}
+	if components != nil {
+		setupLog.Info("Reconciling a subset of components", "components", components)
+	}
+	platform, err = controllers.ParsePlatform(platform)
+	if err != nil {
+		setupLog.Error(err, "invalid platform")
+		os.Exit(1)
+	}
+	if platform != controllers.PlatformOpenShift {
+		setupLog.Info("Skipping the OpenShift integrations", "platform", platform)
+	}
+	if maxConcurrentReconciles < 1 {
+		setupLog.Error(fmt.Errorf("got %d", maxConcurrentReconciles), "max-concurrent-reconciles must be at least 1")
+		os.Exit(1)
+	}
+	if componentApplyWorkers < 1 {
+		setupLog.Error(fmt.Errorf("got %d", componentApplyWorkers), "component-apply-workers must be at least 1")
+		os.Exit(1)
+	}
+	if err := controllers.ValidateResyncPeriod(resyncPeriod); err != nil {
+		setupLog.Error(err, "invalid resync-period")
+		os.Exit(1)
+	}
+	if degradedGracePeriod < 0 {
+		setupLog.Error(fmt.Errorf("got %s", degradedGracePeriod), "degraded-grace-period must not be negative")
+		os.Exit(1)
+	}
+	if installTimeout < 0 {
+		setupLog.Error(fmt.Errorf("got %s", installTimeout), "install-timeout must not be negative")
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if os.Getenv(tracing.EndpointEnvVar) != "" {
+		setupLog.Info("Exporting reconcile traces", "endpoint", os.Getenv(tracing.EndpointEnvVar))
+	}
 
 	ctrl.Log.WithName("Backplane Operator version").Info(fmt.Sprintf("%#v", version.Get()))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	mgrOptions := ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
@@ -128,21 +233,44 @@ func main() {
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "797f9276.open-cluster-management.io",
 		// LeaderElectionNamespace: "backplane-operator-system", // Ensure this is commented out. Uncomment only for running operator locally.
-	})
+	}
+	leaderElection.ApplyTo(&mgrOptions)
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	if err = (&controllers.MultiClusterEngineReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		StatusManager: &status.StatusTracker{Client: mgr.GetClient()},
-	}).SetupWithManager(mgr); err != nil {
+	statusTracker := &status.StatusTracker{
+		Client:              mgr.GetClient(),
+		APIReader:           mgr.GetAPIReader(),
+		DegradedGracePeriod: degradedGracePeriod,
+		InstallTimeout:      installTimeout,
+	}
+	reconciler := &controllers.MultiClusterEngineReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		StatusManager:           statusTracker,
+		Recorder:                mgr.GetEventRecorderFor("multiclusterengine-controller"),
+		ManagedComponents:       components,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		ComponentApplyWorkers:   componentApplyWorkers,
+		ResyncPeriod:            resyncPeriod,
+		Platform:                platform,
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MultiClusterEngine")
 		os.Exit(1)
 	}
 
+	if adminAddr != "0" {
+		if err := mgr.Add(reconciler.AdminServer(adminAddr, os.Getenv(controllers.AdminTokenEnvVar))); err != nil {
+			setupLog.Error(err, "unable to set up admin server")
+			os.Exit(1)
+		}
+	}
+
 	// Render CRD templates
 	crdsDir := crdsDir
 	crds, errs := renderer.RenderCRDs(crdsDir)
@@ -167,6 +295,10 @@ func main() {
 			setupLog.Error(err, "unable to ensure webhook", "webhook", "MultiClusterEngine")
 			os.Exit(1)
 		}
+		if err = ensureConversionWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to ensure conversion webhook", "webhook", "MultiClusterEngine")
+			os.Exit(1)
+		}
 
 		if err = (&backplanev1.MultiClusterEngine{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "MultiClusterEngine")
@@ -189,6 +321,12 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
 }
 
 func ensureCRD(mgr ctrl.Manager, crd *unstructured.Unstructured) error {
@@ -317,3 +455,69 @@ func ensureWebhooks(mgr ctrl.Manager) error {
 
 	return nil
 }
+
+// ensureConversionWebhook points the conversion of the MultiClusterEngine CRD at the operator's
+// webhook server, which converts between the served versions and the v1 storage version. The CA
+// bundle of the service is injected by the service CA operator.
+func ensureConversionWebhook(mgr ctrl.Manager) error {
+	ctx := context.Background()
+
+	deploymentNamespace, ok := os.LookupEnv("POD_NAMESPACE")
+	if !ok {
+		setupLog.Info("Failing due to being unable to locate webhook service namespace")
+		os.Exit(1)
+	}
+	path := "/convert"
+
+	// Wait for manager cache to start and update the CRD
+	maxAttempts := 10
+	go func() {
+		for i := 0; i < maxAttempts; i++ {
+			setupLog.Info("Ensuring conversion webhook is set on the CRD")
+			crd := &apixv1.CustomResourceDefinition{}
+			if err := mgr.GetClient().Get(ctx, types.NamespacedName{Name: crdName}, crd); err != nil {
+				setupLog.Error(err, "Failed to get CRD")
+				time.Sleep(5 * time.Second)
+				continue
+			}
+
+			annotations := crd.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations["service.beta.openshift.io/inject-cabundle"] = "true"
+			crd.SetAnnotations(annotations)
+
+			// Keep the CA bundle already injected, so conversion does not fail until it is injected again
+			var caBundle []byte
+			if conv := crd.Spec.Conversion; conv != nil && conv.Webhook != nil && conv.Webhook.ClientConfig != nil {
+				caBundle = conv.Webhook.ClientConfig.CABundle
+			}
+			crd.Spec.Conversion = &apixv1.CustomResourceConversion{
+				Strategy: apixv1.WebhookConverter,
+				Webhook: &apixv1.WebhookConversion{
+					ClientConfig: &apixv1.WebhookClientConfig{
+						Service: &apixv1.ServiceReference{
+							Name:      webhookServiceName,
+							Namespace: deploymentNamespace,
+							Path:      &path,
+						},
+						CABundle: caBundle,
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			}
+			if err := mgr.GetClient().Update(ctx, crd); err != nil {
+				setupLog.Error(err, "Error updating CRD conversion webhook")
+				time.Sleep(5 * time.Second)
+				continue
+			}
+			return
+		}
+
+		setupLog.Info("Unable to ensure conversion webhook in allotted time. Failing.")
+		os.Exit(1)
+	}()
+
+	return nil
+}
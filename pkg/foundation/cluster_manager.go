@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/yaml"
 
@@ -36,6 +37,10 @@ const (
 	ClusterManagementAddonKind    = "ClusterManagementAddOn"
 )
 
+// ClusterManagerGVK is the GVK the ClusterManager is built with. The version the API server prefers
+// is used in its place when applying it.
+var ClusterManagerGVK = schema.GroupVersionKind{Group: "operator.open-cluster-management.io", Version: "v1", Kind: "ClusterManager"}
+
 // RegistrationImage ...
 func RegistrationImage(overrides map[string]string) string {
 	return overrides[RegistrationImageKey]
@@ -63,8 +68,8 @@ func ClusterManager(m *v1.MultiClusterEngine, overrides map[string]string) *unst
 
 	cm := &ocmapiv1.ClusterManager{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "operator.open-cluster-management.io/v1",
-			Kind:       "ClusterManager",
+			APIVersion: ClusterManagerGVK.GroupVersion().String(),
+			Kind:       ClusterManagerGVK.Kind,
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "cluster-manager",
@@ -84,6 +89,7 @@ func ClusterManager(m *v1.MultiClusterEngine, overrides map[string]string) *unst
 	}
 
 	utils.AddBackplaneConfigLabels(cm, m.GetName())
+	utils.AddClusterScopedLabels(cm, m.GetName())
 	unstructured, err := utils.CoreToUnstructured(cm)
 	if err != nil {
 		log.Error(err, err.Error())
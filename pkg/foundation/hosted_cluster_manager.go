@@ -33,8 +33,8 @@ func HostedClusterManager(m *v1.MultiClusterEngine, overrides map[string]string)
 
 	cm := &ocmapiv1.ClusterManager{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "operator.open-cluster-management.io/v1",
-			Kind:       "ClusterManager",
+			APIVersion: ClusterManagerGVK.GroupVersion().String(),
+			Kind:       ClusterManagerGVK.Kind,
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: hostedName(m),
@@ -64,6 +64,7 @@ func HostedClusterManager(m *v1.MultiClusterEngine, overrides map[string]string)
 	}
 
 	utils.AddBackplaneConfigLabels(cm, m.GetName())
+	utils.AddClusterScopedLabels(cm, m.GetName())
 	unstructured, err := utils.CoreToUnstructured(cm)
 	if err != nil {
 		log.Error(err, err.Error())
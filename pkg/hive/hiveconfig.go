@@ -6,14 +6,19 @@ import (
 	v1 "github.com/stolostron/backplane-operator/api/v1"
 	"github.com/stolostron/backplane-operator/pkg/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// HiveConfigGVK is the GVK the HiveConfig is built with. The version the API server prefers is used
+// in its place when applying it.
+var HiveConfigGVK = schema.GroupVersionKind{Group: "hive.openshift.io", Version: "v1", Kind: "HiveConfig"}
+
 func HiveConfig(bpc *v1.MultiClusterEngine) *unstructured.Unstructured {
 
 	cm := &unstructured.Unstructured{
 		Object: map[string]interface{}{
-			"apiVersion": "hive.openshift.io/v1",
-			"kind":       "HiveConfig",
+			"apiVersion": HiveConfigGVK.GroupVersion().String(),
+			"kind":       HiveConfigGVK.Kind,
 			"metadata": map[string]interface{}{
 				"name": "hive",
 			},
@@ -22,6 +27,7 @@ func HiveConfig(bpc *v1.MultiClusterEngine) *unstructured.Unstructured {
 	}
 
 	utils.AddBackplaneConfigLabels(cm, bpc.GetName())
+	utils.AddClusterScopedLabels(cm, bpc.GetName())
 
 	return cm
 }
@@ -32,11 +32,62 @@ type ManifestImage struct {
 	ImageTag string `json:"image-tag"`
 }
 
-// GetImagesWithOverrides gets images from the environment, then updates them based on MCE annotations
+// ManifestPathEnvVar is the environment variable holding the path of an image manifest file
+const ManifestPathEnvVar = "MANIFEST_PATH"
+
+// ManifestPath is the path of an image manifest file, in the format of the image overrides
+// configmap, whose images take precedence over those from the environment. The MANIFEST_PATH
+// environment variable is used when unset.
+var ManifestPath string
+
+func manifestPath() string {
+	if ManifestPath != "" {
+		return ManifestPath
+	}
+	return os.Getenv(ManifestPathEnvVar)
+}
+
+// RelatedImagesPath is the path of a file listing the relatedImages of the operator's
+// ClusterServiceVersion, whose images take precedence over those from the environment. The
+// RELATED_IMAGES_PATH environment variable is used when unset.
+var RelatedImagesPath string
+
+func relatedImagesPath() string {
+	if RelatedImagesPath != "" {
+		return RelatedImagesPath
+	}
+	return os.Getenv(utils.RelatedImagesPathEnvVar)
+}
+
+// GetImagesWithOverrides gets images from the environment, then updates them based on the
+// relatedImages file, the image manifest file and MCE annotations, in that order of precedence
 func GetImagesWithOverrides(kubeclient client.Client, mce *backplanev1.MultiClusterEngine) (map[string]string, error) {
 	// Get images from environment
 	images := GetImages()
 
+	// Use the images the ClusterServiceVersion declares if a relatedImages file is configured
+	if path := relatedImagesPath(); path != "" {
+		relatedImages, err := utils.LoadRelatedImages(path)
+		if err != nil {
+			return nil, err
+		}
+		for key, image := range relatedImages {
+			images[key] = image
+		}
+	}
+
+	// Override images from the manifest file if one is configured
+	if path := manifestPath(); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read image manifest file: %w", err)
+		}
+		images, err = overrideImagesWithManifest(images, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse image manifest file %s: %w", path, err)
+		}
+	}
+
 	// Override image repository if dev annotation present
 	if imageRepo := utils.GetImageRepository(mce); imageRepo != "" {
 		images = OverrideImageRepository(images, imageRepo)
@@ -44,8 +95,12 @@ func GetImagesWithOverrides(kubeclient client.Client, mce *backplanev1.MultiClus
 
 	// Override individual images if dev configmap present
 	if cmName := utils.GetImageOverridesConfigmap(mce); cmName != "" {
+		namespace, err := utils.GetOperatorNamespace()
+		if err != nil {
+			return nil, fmt.Errorf("unable to look up image overrides configmap %s: %w", cmName, err)
+		}
 		configmap := &corev1.ConfigMap{}
-		err := kubeclient.Get(context.TODO(), types.NamespacedName{Name: cmName, Namespace: utils.OperatorNamespace()}, configmap)
+		err = kubeclient.Get(context.TODO(), types.NamespacedName{Name: cmName, Namespace: namespace}, configmap)
 		if err != nil {
 			return nil, err
 		}
@@ -107,19 +162,24 @@ func OverrideImagesWithConfigmap(images map[string]string, configmap *corev1.Con
 	}
 
 	for _, v := range configmap.Data {
-		var manifestImages []ManifestImage
-		err := json.Unmarshal([]byte(v), &manifestImages)
-		if err != nil {
-			return nil, err
-		}
+		return overrideImagesWithManifest(images, []byte(v))
+	}
+	return images, nil
+}
 
-		for _, manifestImage := range manifestImages {
-			if manifestImage.ImageDigest != "" {
-				images[manifestImage.ImageKey] = fmt.Sprintf("%s/%s@%s", manifestImage.ImageRemote, manifestImage.ImageName, manifestImage.ImageDigest)
-			} else if manifestImage.ImageTag != "" {
-				images[manifestImage.ImageKey] = fmt.Sprintf("%s/%s:%s", manifestImage.ImageRemote, manifestImage.ImageName, manifestImage.ImageTag)
-			}
+// overrideImagesWithManifest updates an image map with the images listed in a JSON image manifest
+func overrideImagesWithManifest(images map[string]string, manifest []byte) (map[string]string, error) {
+	var manifestImages []ManifestImage
+	err := json.Unmarshal(manifest, &manifestImages)
+	if err != nil {
+		return nil, err
+	}
 
+	for _, manifestImage := range manifestImages {
+		if manifestImage.ImageDigest != "" {
+			images[manifestImage.ImageKey] = fmt.Sprintf("%s/%s@%s", manifestImage.ImageRemote, manifestImage.ImageName, manifestImage.ImageDigest)
+		} else if manifestImage.ImageTag != "" {
+			images[manifestImage.ImageKey] = fmt.Sprintf("%s/%s:%s", manifestImage.ImageRemote, manifestImage.ImageName, manifestImage.ImageTag)
 		}
 	}
 	return images, nil
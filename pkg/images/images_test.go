@@ -3,11 +3,18 @@
 package images
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_GetImages(t *testing.T) {
@@ -147,3 +154,110 @@ func TestOverrideImagesWithConfigmap(t *testing.T) {
 		})
 	}
 }
+
+func TestGetImagesWithManifestFile(t *testing.T) {
+	t.Setenv("OPERAND_IMAGE_DISCOVERY_OPERATOR", "quay.io/stolostron/discovery-operator:env")
+	t.Setenv("OPERAND_IMAGE_CONSOLE_MCE", "quay.io/stolostron/console-mce:env")
+
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	err := os.WriteFile(manifest, []byte(`[
+		{
+			"image-key": "discovery_operator",
+			"image-name": "discovery-operator",
+			"image-remote": "quay.io/acm-d",
+			"image-digest": "sha256:1234"
+		}
+	]`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ManifestPathEnvVar, manifest)
+
+	c := fake.NewClientBuilder().Build()
+	got, err := GetImagesWithOverrides(c, &backplanev1.MultiClusterEngine{})
+	if err != nil {
+		t.Fatalf("GetImagesWithOverrides() error = %v", err)
+	}
+	want := map[string]string{
+		"discovery_operator": "quay.io/acm-d/discovery-operator@sha256:1234",
+		"console_mce":        "quay.io/stolostron/console-mce:env",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetImagesWithOverrides() = %v, want %v", got, want)
+	}
+
+	t.Setenv(ManifestPathEnvVar, filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := GetImagesWithOverrides(c, &backplanev1.MultiClusterEngine{}); err == nil {
+		t.Error("expected an error for a missing manifest file")
+	}
+}
+
+func TestGetImagesWithRelatedImages(t *testing.T) {
+	t.Setenv("OPERAND_IMAGE_DISCOVERY_OPERATOR", "quay.io/stolostron/discovery-operator:env")
+	t.Setenv("OPERAND_IMAGE_CONSOLE_MCE", "quay.io/stolostron/console-mce:env")
+
+	relatedImages := filepath.Join(t.TempDir(), "related-images.yaml")
+	err := os.WriteFile(relatedImages, []byte(`
+- name: discovery-operator
+  image: quay.io/stolostron/discovery-operator@sha256:1234
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(utils.RelatedImagesPathEnvVar, relatedImages)
+
+	c := fake.NewClientBuilder().Build()
+	got, err := GetImagesWithOverrides(c, &backplanev1.MultiClusterEngine{})
+	if err != nil {
+		t.Fatalf("GetImagesWithOverrides() error = %v", err)
+	}
+	want := map[string]string{
+		"discovery_operator": "quay.io/stolostron/discovery-operator@sha256:1234",
+		"console_mce":        "quay.io/stolostron/console-mce:env",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetImagesWithOverrides() = %v, want %v", got, want)
+	}
+
+	// The image manifest still takes precedence over the relatedImages
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	err = os.WriteFile(manifest, []byte(`[
+		{
+			"image-key": "discovery_operator",
+			"image-name": "discovery-operator",
+			"image-remote": "quay.io/acm-d",
+			"image-digest": "sha256:5678"
+		}
+	]`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ManifestPathEnvVar, manifest)
+	got, err = GetImagesWithOverrides(c, &backplanev1.MultiClusterEngine{})
+	if err != nil {
+		t.Fatalf("GetImagesWithOverrides() error = %v", err)
+	}
+	if got["discovery_operator"] != "quay.io/acm-d/discovery-operator@sha256:5678" {
+		t.Errorf("discovery_operator = %s, want the image from the manifest", got["discovery_operator"])
+	}
+}
+
+func TestGetImagesWithOverridesNamespaceUnset(t *testing.T) {
+	t.Setenv(utils.OperatorNamespaceEnvVar, "")
+	mce := &backplanev1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{utils.AnnotationImageOverridesCM: "overrides"},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "overrides", Namespace: "default"},
+	}).Build()
+
+	_, err := GetImagesWithOverrides(c, mce)
+	if !errors.Is(err, utils.ErrOperatorNamespaceUnset) {
+		t.Fatalf("GetImagesWithOverrides() error = %v, want %v", err, utils.ErrOperatorNamespaceUnset)
+	}
+	if !strings.Contains(err.Error(), utils.OperatorNamespaceEnvVar) {
+		t.Errorf("expected the error to name %s, got %v", utils.OperatorNamespaceEnvVar, err)
+	}
+}
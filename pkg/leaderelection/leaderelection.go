@@ -0,0 +1,53 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package leaderelection
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// Defaults match those of controller-runtime
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+)
+
+// Config holds the lease timings used for leader election
+type Config struct {
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// BindFlags registers the leader election timing flags on fs
+func (c *Config) BindFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&c.LeaseDuration, "leader-elect-lease-duration", DefaultLeaseDuration,
+		"The duration that non-leader candidates will wait before attempting to acquire leadership.")
+	fs.DurationVar(&c.RenewDeadline, "renew-deadline", DefaultRenewDeadline,
+		"The duration the acting leader will retry refreshing leadership before giving it up. Must be less than the lease duration.")
+	fs.DurationVar(&c.RetryPeriod, "retry-period", DefaultRetryPeriod,
+		"The duration leader election clients should wait between tries of actions.")
+}
+
+// Validate returns an error if the timings cannot produce a stable leader
+func (c *Config) Validate() error {
+	if c.LeaseDuration <= 0 || c.RenewDeadline <= 0 || c.RetryPeriod <= 0 {
+		return fmt.Errorf("leader election durations must be positive")
+	}
+	if c.RenewDeadline >= c.LeaseDuration {
+		return fmt.Errorf("renew deadline (%s) must be less than lease duration (%s)", c.RenewDeadline, c.LeaseDuration)
+	}
+	return nil
+}
+
+// ApplyTo sets the leader election timings on the manager options
+func (c *Config) ApplyTo(opts *ctrl.Options) {
+	opts.LeaseDuration = &c.LeaseDuration
+	opts.RenewDeadline = &c.RenewDeadline
+	opts.RetryPeriod = &c.RetryPeriod
+}
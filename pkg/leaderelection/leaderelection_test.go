@@ -0,0 +1,71 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package leaderelection
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestConfigFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			args: []string{},
+			want: Config{LeaseDuration: DefaultLeaseDuration, RenewDeadline: DefaultRenewDeadline, RetryPeriod: DefaultRetryPeriod},
+		},
+		{
+			name: "custom timings",
+			args: []string{"--leader-elect-lease-duration=30s", "--renew-deadline=20s", "--retry-period=5s"},
+			want: Config{LeaseDuration: 30 * time.Second, RenewDeadline: 20 * time.Second, RetryPeriod: 5 * time.Second},
+		},
+		{
+			name:    "renew deadline not less than lease",
+			args:    []string{"--leader-elect-lease-duration=10s", "--renew-deadline=10s"},
+			wantErr: true,
+		},
+		{
+			name:    "non-positive retry period",
+			args:    []string{"--retry-period=0s"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			c.BindFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			err := c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			opts := ctrl.Options{}
+			c.ApplyTo(&opts)
+			if *opts.LeaseDuration != tt.want.LeaseDuration {
+				t.Errorf("LeaseDuration = %v, want %v", *opts.LeaseDuration, tt.want.LeaseDuration)
+			}
+			if *opts.RenewDeadline != tt.want.RenewDeadline {
+				t.Errorf("RenewDeadline = %v, want %v", *opts.RenewDeadline, tt.want.RenewDeadline)
+			}
+			if *opts.RetryPeriod != tt.want.RetryPeriod {
+				t.Errorf("RetryPeriod = %v, want %v", *opts.RetryPeriod, tt.want.RetryPeriod)
+			}
+		})
+	}
+}
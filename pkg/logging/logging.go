@@ -0,0 +1,28 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package logging
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// FormatJSON encodes log entries as structured JSON
+	FormatJSON = "json"
+	// FormatConsole encodes log entries as human-readable text
+	FormatConsole = "console"
+)
+
+// EncoderOpts returns the zap option that selects the encoder for the given log format
+func EncoderOpts(format string) (zap.Opts, error) {
+	switch format {
+	case FormatJSON:
+		return zap.JSONEncoder(), nil
+	case FormatConsole:
+		return zap.ConsoleEncoder(), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be one of %s, %s", format, FormatJSON, FormatConsole)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestEncoderOpts(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		wantJSON bool
+		wantErr  bool
+	}{
+		{name: "json", format: FormatJSON, wantJSON: true},
+		{name: "console", format: FormatConsole, wantJSON: false},
+		{name: "invalid", format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, err := EncoderOpts(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EncoderOpts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			buf := &bytes.Buffer{}
+			logger := zap.New(zap.WriteTo(buf), opt)
+			logger.WithValues("multiClusterEngine", "test").Info("test message")
+
+			var entry map[string]interface{}
+			isJSON := json.Unmarshal(buf.Bytes(), &entry) == nil
+			if isJSON != tt.wantJSON {
+				t.Errorf("EncoderOpts(%q) produced JSON = %t, want %t: %s", tt.format, isJSON, tt.wantJSON, buf.String())
+			}
+			if isJSON && entry["multiClusterEngine"] != "test" {
+				t.Errorf("expected structured field multiClusterEngine in %s", buf.String())
+			}
+		})
+	}
+}
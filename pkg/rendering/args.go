@@ -0,0 +1,188 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// chartComponents maps toggle chart names to the component they deploy
+var chartComponents = map[string]string{
+	"assisted-service":             v1.AssistedService,
+	"cluster-lifecycle":            v1.ClusterLifecycle,
+	"cluster-manager":              v1.ClusterManager,
+	"cluster-proxy-addon":          v1.ClusterProxyAddon,
+	"console-mce":                  v1.ConsoleMCE,
+	"discovery-operator":           v1.Discovery,
+	"hive-operator":                v1.Hive,
+	"hypershift-preview":           v1.HyperShift,
+	"image-based-install-operator": v1.ImageBasedInstallOperator,
+	"managed-serviceaccount":       v1.ManagedServiceAccount,
+	"server-foundation":            v1.ServerFoundation,
+}
+
+// primaryContainer names the container that runs a component's own controller, and the Deployment
+// it belongs to
+type primaryContainer struct {
+	deployment string
+	container  string
+}
+
+// primaryContainers maps each toggle component to its primary container. Overrides meant for a
+// single container, such as the component image or args, only apply to it, as the other
+// Deployments of a chart run different images.
+var primaryContainers = map[string]primaryContainer{
+	v1.AssistedService:           {deployment: "infrastructure-operator", container: "manager"},
+	v1.ClusterLifecycle:          {deployment: "cluster-curator-controller", container: "cluster-curator-controller"},
+	v1.ClusterManager:            {deployment: "cluster-manager", container: "registration-operator"},
+	v1.ClusterProxyAddon:         {deployment: "cluster-proxy-addon-manager", container: "manager"},
+	v1.ConsoleMCE:                {deployment: "console-mce-console", container: "console"},
+	v1.Discovery:                 {deployment: "discovery-operator", container: "discovery-operator"},
+	v1.Hive:                      {deployment: "hive-operator", container: "hive-operator"},
+	v1.HyperShift:                {deployment: "hypershift-addon-manager", container: "hypershift-addon-manager"},
+	v1.ImageBasedInstallOperator: {deployment: "image-based-install-operator", container: "manager"},
+	v1.ManagedServiceAccount:     {deployment: "managed-serviceaccount-addon-manager", container: "manager"},
+	v1.ServerFoundation:          {deployment: "ocm-controller", container: "ocm-controller"},
+}
+
+// primaryContainerIndex returns the index of the component's primary container in the containers of
+// a Deployment, or -1 if the Deployment is not the component's primary Deployment
+func primaryContainerIndex(template *unstructured.Unstructured, component string, containers []interface{}) int {
+	primary, ok := primaryContainers[component]
+	if !ok || template.GetName() != primary.deployment {
+		return -1
+	}
+	for i, c := range containers {
+		if container, ok := c.(map[string]interface{}); ok && container["name"] == primary.container {
+			return i
+		}
+	}
+	return -1
+}
+
+// logLevelComponents are the components whose containers accept the klog verbosity flag
+var logLevelComponents = map[string]bool{
+	v1.ClusterManager:        true,
+	v1.ClusterProxyAddon:     true,
+	v1.ManagedServiceAccount: true,
+	v1.ServerFoundation:      true,
+}
+
+// logLevelArg returns the verbosity flag for the given level, using the single dash form when the
+// existing args already do
+func logLevelArg(args []string, level int32) string {
+	for _, a := range args {
+		if flagName(a) == "-v" {
+			return fmt.Sprintf("-v=%d", level)
+		}
+	}
+	return fmt.Sprintf("--v=%d", level)
+}
+
+// flagName returns the flag portion of an arg, e.g. "--feature-gates" for "--feature-gates=Foo=true".
+// Args that are not flags are returned unchanged.
+func flagName(arg string) string {
+	if !strings.HasPrefix(arg, "-") {
+		return arg
+	}
+	return strings.SplitN(arg, "=", 2)[0]
+}
+
+// mergeArgs appends overrides to args. An override for a flag already present replaces it in
+// place, and only the last override for a given flag is kept. A flag of args given in the
+// "--flag value" form is replaced together with its value.
+func mergeArgs(args, overrides []string) []string {
+	merged := append([]string{}, args...)
+	for _, o := range overrides {
+		replaced := false
+		for i, a := range merged {
+			if strings.HasPrefix(o, "-") && flagName(a) == flagName(o) {
+				if !strings.Contains(a, "=") && i+1 < len(merged) && !strings.HasPrefix(merged[i+1], "-") {
+					merged = append(merged[:i+1], merged[i+2:]...)
+				}
+				merged[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+// injectComponentArgs merges the log level and config values configured for a component into the
+// first container of each Deployment rendered by that component's chart, and the component args
+// into the component's primary container
+func injectComponentArgs(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	level := backplaneConfig.ComponentLogLevel(component)
+	if !logLevelComponents[component] {
+		level = nil
+	}
+	if level != nil && !v1.ValidLogLevel(*level) {
+		return fmt.Errorf("log level %d for %s must be between %d and %d", *level, component, v1.MinLogLevel, v1.MaxLogLevel)
+	}
+	configArgs, err := configValueArgs(component, template.GetName(), backplaneConfig.ComponentConfigValues(component))
+	if err != nil {
+		return err
+	}
+	componentArgs := backplaneConfig.ComponentArgs(component)
+	if level == nil && len(configArgs) == 0 && len(componentArgs) == 0 {
+		return nil
+	}
+
+	containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return fmt.Errorf("unable to find containers in deployment %s", template.GetName())
+	}
+	primary := primaryContainerIndex(template, component, containers)
+	for i := range containers {
+		if i != 0 && i != primary {
+			continue
+		}
+		container, ok := containers[i].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unable to read container in deployment %s", template.GetName())
+		}
+		args, _, err := unstructured.NestedStringSlice(container, "args")
+		if err != nil {
+			return fmt.Errorf("unable to read args of deployment %s: %w", template.GetName(), err)
+		}
+
+		// The log level and config values are merged first so that the same flag in the component
+		// args wins
+		overrides := []string{}
+		if i == 0 {
+			if level != nil {
+				overrides = append(overrides, logLevelArg(args, *level))
+			}
+			overrides = append(overrides, configArgs...)
+		}
+		if i == primary {
+			overrides = append(overrides, componentArgs...)
+		}
+		if len(overrides) == 0 {
+			continue
+		}
+
+		merged := []interface{}{}
+		for _, a := range mergeArgs(args, overrides) {
+			merged = append(merged, a)
+		}
+		container["args"] = merged
+		containers[i] = container
+	}
+	return unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", "containers")
+}
@@ -0,0 +1,50 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// injectComponentCommand replaces the command of a container of a Deployment rendered by a
+// component's chart with the command configured for the component. The named container is changed
+// wherever it is found, or the component's primary container when no container is named.
+func injectComponentCommand(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	containerName, command := backplaneConfig.ComponentCommand(component)
+	if len(command) == 0 {
+		return nil
+	}
+
+	containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return fmt.Errorf("unable to find containers in deployment %s", template.GetName())
+	}
+	primary := primaryContainerIndex(template, component, containers)
+	cmd := []interface{}{}
+	for _, c := range command {
+		cmd = append(cmd, c)
+	}
+	for i := range containers {
+		container, ok := containers[i].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unable to read container in deployment %s", template.GetName())
+		}
+		if (containerName == "" && i == primary) || (containerName != "" && container["name"] == containerName) {
+			container["command"] = cmd
+			// The args were meant for the replaced entrypoint
+			delete(container, "args")
+			containers[i] = container
+		}
+	}
+	return unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", "containers")
+}
@@ -0,0 +1,220 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// configValue describes a ConfigValues key supported by a component
+type configValue struct {
+	// deployment limits the value to one of the component's deployments when set
+	deployment string
+	// flag is the container flag set from the value
+	flag string
+	// env is the container environment variable set from the value. When neither flag nor env is
+	// set the value sets the replicas of the deployment instead.
+	env string
+	// validate checks the value before it is applied
+	validate func(string) error
+}
+
+const (
+	importControllerDeployment = "managedcluster-import-controller-v2"
+
+	// maxImportControllerWorkers bounds the import controller workers, beyond which the load on
+	// the API server outweighs the faster imports
+	maxImportControllerWorkers = 100
+)
+
+// componentConfigValues maps the ConfigValues keys supported by each component to what they set
+var componentConfigValues = map[string]map[string]configValue{
+	v1.Discovery: {
+		"workers": {flag: "--max-concurrent-reconciles", validate: positiveInt},
+	},
+	v1.ServerFoundation: {
+		"importControllerReplicas":      {deployment: importControllerDeployment, validate: positiveInt},
+		"importControllerWorkers":       {deployment: importControllerDeployment, env: "MAX_CONCURRENT_RECONCILES", validate: intBetween(1, maxImportControllerWorkers)},
+		"importControllerLeaseDuration": {deployment: importControllerDeployment, flag: "--leader-election-lease-duration", validate: positiveDuration},
+		"importControllerRenewDeadline": {deployment: importControllerDeployment, flag: "--leader-election-renew-deadline", validate: positiveDuration},
+		"importControllerRetryPeriod":   {deployment: importControllerDeployment, flag: "--leader-election-retry-period", validate: positiveDuration},
+	},
+}
+
+func positiveInt(value string) error {
+	if n, err := strconv.Atoi(value); err != nil || n < 1 {
+		return fmt.Errorf("must be a positive integer, got %q", value)
+	}
+	return nil
+}
+
+func intBetween(min, max int) func(string) error {
+	return func(value string) error {
+		if n, err := strconv.Atoi(value); err != nil || n < min || n > max {
+			return fmt.Errorf("must be an integer between %d and %d, got %q", min, max, value)
+		}
+		return nil
+	}
+}
+
+func positiveDuration(value string) error {
+	if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+		return fmt.Errorf("must be a positive duration such as 137s, got %q", value)
+	}
+	return nil
+}
+
+// unsupportedConfigValues returns the config values set for a component that it does not
+// support, sorted by key
+func unsupportedConfigValues(component string, values map[string]string) []string {
+	keys := []string{}
+	for k := range values {
+		if _, ok := componentConfigValues[component][k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// logUnsupportedConfigValues logs the config values set for the component of a chart that it does
+// not support. It runs once per chart render, as every deployment of the chart ignores them.
+func logUnsupportedConfigValues(chartName string, backplaneConfig *v1.MultiClusterEngine) {
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return
+	}
+	for _, k := range unsupportedConfigValues(component, backplaneConfig.ComponentConfigValues(component)) {
+		log.FromContext(context.Background()).Info(fmt.Sprintf("Ignoring unsupported config value %s for component %s", k, component))
+	}
+}
+
+// deploymentConfigValues returns the config values set for a component that apply to the given
+// deployment, sorted by key. Keys the component does not support are ignored.
+func deploymentConfigValues(component, deployment string, values map[string]string) ([]string, error) {
+	keys := []string{}
+	for k := range values {
+		cv, ok := componentConfigValues[component][k]
+		if !ok {
+			continue
+		}
+		if cv.deployment != "" && cv.deployment != deployment {
+			continue
+		}
+		if err := cv.validate(values[k]); err != nil {
+			return nil, fmt.Errorf("config value %s for %s %w", k, component, err)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// configValueArgs returns the container args for the config values set for a component that
+// apply to the given deployment
+func configValueArgs(component, deployment string, values map[string]string) ([]string, error) {
+	keys, err := deploymentConfigValues(component, deployment, values)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{}
+	for _, k := range keys {
+		if flag := componentConfigValues[component][k].flag; flag != "" {
+			args = append(args, fmt.Sprintf("%s=%s", flag, values[k]))
+		}
+	}
+	return args, nil
+}
+
+// injectConfigValueReplicas sets the replicas of a Deployment from the config values of the
+// component that rendered it
+func injectConfigValueReplicas(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	values := backplaneConfig.ComponentConfigValues(component)
+	keys, err := deploymentConfigValues(component, template.GetName(), values)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if cv := componentConfigValues[component][k]; cv.flag != "" || cv.env != "" {
+			continue
+		}
+		replicas, _ := strconv.ParseInt(values[k], 10, 64)
+		if err := unstructured.SetNestedField(template.Object, replicas, "spec", "replicas"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectConfigValueEnv sets the environment variables of the primary (first) container of a
+// Deployment from the config values of the component that rendered it, replacing any value the
+// template sets
+func injectConfigValueEnv(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	values := backplaneConfig.ComponentConfigValues(component)
+	keys, err := deploymentConfigValues(component, template.GetName(), values)
+	if err != nil {
+		return err
+	}
+	env := map[string]string{}
+	names := []string{}
+	for _, k := range keys {
+		if name := componentConfigValues[component][k].env; name != "" {
+			env[name] = values[k]
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return fmt.Errorf("unable to find containers in deployment %s", template.GetName())
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unable to read container in deployment %s", template.GetName())
+	}
+	vars, _, err := unstructured.NestedSlice(container, "env")
+	if err != nil {
+		return fmt.Errorf("unable to read env of deployment %s: %w", template.GetName(), err)
+	}
+
+	merged := []interface{}{}
+	for _, v := range vars {
+		if e, ok := v.(map[string]interface{}); ok {
+			if _, set := env[fmt.Sprint(e["name"])]; set {
+				continue
+			}
+		}
+		merged = append(merged, v)
+	}
+	for _, name := range names {
+		merged = append(merged, map[string]interface{}{"name": name, "value": env[name]})
+	}
+	container["env"] = merged
+	containers[0] = container
+	return unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", "containers")
+}
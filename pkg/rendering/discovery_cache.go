@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// discoveryDeployment is the Deployment and container of the discovery-operator
+	discoveryDeployment = "discovery-operator"
+	// discoveryCacheVolume is the volume holding the discovery-operator cache when nothing is
+	// mounted at scratchPath yet
+	discoveryCacheVolume = "discovery-cache"
+)
+
+// injectDiscoveryCache mounts the configured cache volume at /tmp in the discovery-operator. A volume
+// already mounted there, such as the emptyDir of a read-only root filesystem, is replaced.
+func injectDiscoveryCache(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" || template.GetName() != discoveryDeployment || chartComponents[chartName] != v1.Discovery {
+		return nil
+	}
+	source := backplaneConfig.DiscoveryCacheVolume()
+	if source == nil {
+		return nil
+	}
+	volume, err := runtime.DefaultUnstructuredConverter.ToUnstructured(source)
+	if err != nil {
+		return fmt.Errorf("unable to convert the discovery cache volume: %w", err)
+	}
+
+	containers, _, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return fmt.Errorf("unable to read containers of %s %s: %w", template.GetKind(), template.GetName(), err)
+	}
+	volumeName := ""
+	for i := range containers {
+		container, ok := containers[i].(map[string]interface{})
+		if !ok || container["name"] != discoveryDeployment {
+			continue
+		}
+		mounts, _, err := unstructured.NestedSlice(container, "volumeMounts")
+		if err != nil {
+			return fmt.Errorf("unable to read volumeMounts of %s %s: %w", template.GetKind(), template.GetName(), err)
+		}
+		for _, m := range mounts {
+			if mount, ok := m.(map[string]interface{}); ok && mount["mountPath"] == scratchPath {
+				volumeName, _ = mount["name"].(string)
+			}
+		}
+		if volumeName == "" {
+			volumeName = discoveryCacheVolume
+			mounts = append(mounts, map[string]interface{}{"name": volumeName, "mountPath": scratchPath})
+			if err := unstructured.SetNestedSlice(container, mounts, "volumeMounts"); err != nil {
+				return err
+			}
+		}
+	}
+	if volumeName == "" {
+		return fmt.Errorf("container %s not found in %s %s", discoveryDeployment, template.GetKind(), template.GetName())
+	}
+	if err := unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		return err
+	}
+
+	volume["name"] = volumeName
+	volumes, _, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "volumes")
+	if err != nil {
+		return fmt.Errorf("unable to read volumes of %s %s: %w", template.GetKind(), template.GetName(), err)
+	}
+	replaced := false
+	for i, v := range volumes {
+		if existing, ok := v.(map[string]interface{}); ok && existing["name"] == volumeName {
+			volumes[i] = volume
+			replaced = true
+		}
+	}
+	if !replaced {
+		volumes = append(volumes, volume)
+	}
+	return unstructured.SetNestedSlice(template.Object, volumes, "spec", "template", "spec", "volumes")
+}
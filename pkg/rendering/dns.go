@@ -0,0 +1,52 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// injectDNS sets the DNS policy and config from the overrides on the pods of a rendered pod template
+// and adds the host aliases. Template values are kept for anything left unset.
+func injectDNS(template *unstructured.Unstructured, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] || backplaneConfig.Spec.Overrides == nil {
+		return nil
+	}
+	overrides := backplaneConfig.Spec.Overrides
+
+	if overrides.DNSPolicy != "" {
+		if err := unstructured.SetNestedField(template.Object, string(overrides.DNSPolicy), "spec", "template", "spec", "dnsPolicy"); err != nil {
+			return err
+		}
+	}
+	if overrides.DNSConfig != nil {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(overrides.DNSConfig)
+		if err != nil {
+			return fmt.Errorf("unable to convert dnsConfig for %s %s: %w", template.GetKind(), template.GetName(), err)
+		}
+		if err := unstructured.SetNestedMap(template.Object, obj, "spec", "template", "spec", "dnsConfig"); err != nil {
+			return err
+		}
+	}
+	if len(overrides.HostAliases) > 0 {
+		hostAliases, _, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "hostAliases")
+		if err != nil {
+			return fmt.Errorf("unable to read hostAliases of %s %s: %w", template.GetKind(), template.GetName(), err)
+		}
+		for i := range overrides.HostAliases {
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&overrides.HostAliases[i])
+			if err != nil {
+				return fmt.Errorf("unable to convert hostAliases for %s %s: %w", template.GetKind(), template.GetName(), err)
+			}
+			hostAliases = append(hostAliases, obj)
+		}
+		if err := unstructured.SetNestedSlice(template.Object, hostAliases, "spec", "template", "spec", "hostAliases"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// podTemplateKinds are the kinds whose pod spec is found at spec.template.spec
+var podTemplateKinds = map[string]bool{
+	"DaemonSet":   true,
+	"Deployment":  true,
+	"Job":         true,
+	"ReplicaSet":  true,
+	"StatefulSet": true,
+}
+
+// rewriteImageRepository replaces everything before the image name with repo
+func rewriteImageRepository(image, repo string) string {
+	i := strings.LastIndex(image, "/")
+	if i < 0 {
+		return fmt.Sprintf("%s/%s", repo, image)
+	}
+	return fmt.Sprintf("%s%s", repo, image[i:])
+}
+
+// injectImageRepository applies the imageRepository annotation to every container and init container
+// of a rendered pod template. Images from the image manifest already have the repository applied,
+// or were replaced through the image overrides configmap, and are left as they are.
+func injectImageRepository(template *unstructured.Unstructured, backplaneConfig *v1.MultiClusterEngine, images map[string]string) error {
+	repo := utils.GetImageRepository(backplaneConfig)
+	if repo == "" || !podTemplateKinds[template.GetKind()] {
+		return nil
+	}
+
+	resolved := map[string]bool{}
+	for _, image := range images {
+		resolved[image] = true
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", field)
+		if err != nil {
+			return fmt.Errorf("unable to read %s of %s %s: %w", field, template.GetKind(), template.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("unable to read %s of %s %s", field, template.GetKind(), template.GetName())
+			}
+			image, ok := container["image"].(string)
+			if !ok || image == "" || resolved[image] {
+				continue
+			}
+			container["image"] = rewriteImageRepository(image, repo)
+		}
+		if err := unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectComponentImage replaces the image of the primary container of a component with the image
+// pinned for that component, if any. The other containers of the component's chart run different
+// images and are left unchanged.
+func injectComponentImage(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	image := backplaneConfig.ComponentImage(component)
+	if image == "" {
+		return nil
+	}
+	if !v1.ValidImageReference(image) {
+		return fmt.Errorf("image %s for %s is not a valid image reference", image, component)
+	}
+
+	containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return fmt.Errorf("unable to find containers in deployment %s", template.GetName())
+	}
+	primary := primaryContainerIndex(template, component, containers)
+	if primary < 0 {
+		return nil
+	}
+	container, ok := containers[primary].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unable to read container in deployment %s", template.GetName())
+	}
+	container["image"] = image
+	containers[primary] = container
+	return unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", "containers")
+}
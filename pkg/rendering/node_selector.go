@@ -0,0 +1,26 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// injectNodeSelector replaces the nodeSelector of a rendered pod template with the one configured
+// for its component. Pods of components without one keep the global nodeSelector from the chart
+// values.
+func injectNodeSelector(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	nodeSelector := backplaneConfig.ComponentNodeSelector(component)
+	if len(nodeSelector) == 0 {
+		return nil
+	}
+	return unstructured.SetNestedStringMap(template.Object, nodeSelector, "spec", "template", "spec", "nodeSelector")
+}
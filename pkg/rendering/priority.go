@@ -0,0 +1,42 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// injectPriorityClass sets the priority class configured for a component on the pods of a rendered
+// Deployment. Deployments from charts not belonging to a component use the global priority class.
+func injectPriorityClass(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" || backplaneConfig.Spec.Overrides == nil {
+		return nil
+	}
+
+	priorityClassName := backplaneConfig.Spec.Overrides.PriorityClassName
+	if component, ok := chartComponents[chartName]; ok {
+		priorityClassName = backplaneConfig.ComponentPriorityClassName(component)
+	}
+	if priorityClassName == "" {
+		return nil
+	}
+	return unstructured.SetNestedField(template.Object, priorityClassName, "spec", "template", "spec", "priorityClassName")
+}
+
+// injectTerminationGracePeriod sets the termination grace period configured for a component on the
+// pods of a rendered pod template. Charts not belonging to a component use the global grace period.
+func injectTerminationGracePeriod(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] || backplaneConfig.Spec.Overrides == nil {
+		return nil
+	}
+
+	gracePeriod := backplaneConfig.Spec.Overrides.TerminationGracePeriodSeconds
+	if component, ok := chartComponents[chartName]; ok {
+		gracePeriod = backplaneConfig.ComponentTerminationGracePeriodSeconds(component)
+	}
+	if gracePeriod == nil {
+		return nil
+	}
+	return unstructured.SetNestedField(template.Object, *gracePeriod, "spec", "template", "spec", "terminationGracePeriodSeconds")
+}
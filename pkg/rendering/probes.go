@@ -0,0 +1,67 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// injectProbes merges the probe timings configured for a component into the probes of the
+// containers of its rendered pod templates. Probes a container does not define are not added, as
+// the override carries no handler for them.
+func injectProbes(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	probes := backplaneConfig.ComponentProbes(component)
+	if probes == nil {
+		return nil
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", field)
+		if err != nil {
+			return fmt.Errorf("unable to read %s of %s %s: %w", field, template.GetKind(), template.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("unable to read %s of %s %s", field, template.GetKind(), template.GetName())
+			}
+			mergeProbeTimings(container, "startupProbe", probes.Startup)
+			mergeProbeTimings(container, "livenessProbe", probes.Liveness)
+			mergeProbeTimings(container, "readinessProbe", probes.Readiness)
+		}
+		if err := unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeProbeTimings sets the configured timings on the named probe of the container, if it has one
+func mergeProbeTimings(container map[string]interface{}, probeName string, timings *v1.ProbeTimings) {
+	probe, ok := container[probeName].(map[string]interface{})
+	if !ok || timings == nil {
+		return
+	}
+	if timings.InitialDelaySeconds != nil {
+		probe["initialDelaySeconds"] = int64(*timings.InitialDelaySeconds)
+	}
+	if timings.TimeoutSeconds != nil {
+		probe["timeoutSeconds"] = int64(*timings.TimeoutSeconds)
+	}
+	if timings.FailureThreshold != nil {
+		probe["failureThreshold"] = int64(*timings.FailureThreshold)
+	}
+}
@@ -43,12 +43,21 @@ type Global struct {
 }
 
 type HubConfig struct {
-	NodeSelector         map[string]string `json:"nodeSelector" structs:"nodeSelector"`
-	ProxyConfigs         map[string]string `json:"proxyConfigs" structs:"proxyConfigs"`
-	ReplicaCount         int               `json:"replicaCount" structs:"replicaCount"`
-	Tolerations          []Toleration      `json:"tolerations" structs:"tolerations"`
-	OCPVersion           string            `json:"ocpVersion" structs:"ocpVersion"`
-	ClusterIngressDomain string            `json:"clusterIngressDomain" structs:"clusterIngressDomain"`
+	NodeSelector                  map[string]string `json:"nodeSelector" structs:"nodeSelector"`
+	ProxyConfigs                  map[string]string `json:"proxyConfigs" structs:"proxyConfigs"`
+	ReplicaCount                  int               `json:"replicaCount" structs:"replicaCount"`
+	Tolerations                   []Toleration      `json:"tolerations" structs:"tolerations"`
+	OCPVersion                    string            `json:"ocpVersion" structs:"ocpVersion"`
+	ClusterIngressDomain          string            `json:"clusterIngressDomain" structs:"clusterIngressDomain"`
+	DisableTrustBundle            bool              `json:"disableTrustBundle" structs:"disableTrustBundle"`
+	InfrastructureCA              string            `json:"infrastructureCA" structs:"infrastructureCA"`
+	RegistriesConf                string            `json:"registriesConf" structs:"registriesConf"`
+	EgressAllowlist               string            `json:"egressAllowlist" structs:"egressAllowlist"`
+	WebhookPort                   int32             `json:"webhookPort" structs:"webhookPort"`
+	WebhookTLSSecret              string            `json:"webhookTLSSecret" structs:"webhookTLSSecret"`
+	ProxyServerServiceType        string            `json:"proxyServerServiceType" structs:"proxyServerServiceType"`
+	ProxyServerServiceAnnotations map[string]string `json:"proxyServerServiceAnnotations" structs:"proxyServerServiceAnnotations"`
+	MonitoringNamespace           string            `json:"monitoringNamespace" structs:"monitoringNamespace"`
 }
 
 type Toleration struct {
@@ -238,6 +247,7 @@ func renderTemplates(chartPath string, backplaneConfig *v1.MultiClusterEngine, i
 		log.Info(fmt.Sprintf("error loading chart: %s", chart.Name()))
 		return nil, append(errs, err)
 	}
+	logUnsupportedConfigValues(chart.Name(), backplaneConfig)
 	valuesYaml := &Values{}
 	injectValuesOverrides(valuesYaml, backplaneConfig, images)
 	helmEngine := engine.Engine{
@@ -267,9 +277,73 @@ func renderTemplates(chartPath string, backplaneConfig *v1.MultiClusterEngine, i
 
 		utils.AddBackplaneConfigLabels(unstructured, backplaneConfig.Name)
 
+		if err := injectImageRepository(unstructured, backplaneConfig, images); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectComponentImage(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectComponentArgs(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectComponentCommand(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectConfigValueReplicas(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectConfigValueEnv(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectUpdateStrategy(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectPriorityClass(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectNodeSelector(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectTopologySpreadConstraints(unstructured, backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectDNS(unstructured, backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectAutomountServiceAccountToken(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		injectServiceAccountAnnotations(unstructured, chart.Name(), backplaneConfig)
+		if err := injectSidecars(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectSecurityContext(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectReadOnlyRootFilesystem(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectDiscoveryCache(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectHostNamespaces(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectTerminationGracePeriod(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		if err := injectProbes(unstructured, chart.Name(), backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+
+		if err := injectMaintenanceReplicas(unstructured, backplaneConfig); err != nil {
+			return nil, append(errs, err)
+		}
+		injectServiceMonitorMetadata(unstructured, backplaneConfig)
+
 		// Add namespace to namespaced resources
 		switch unstructured.GetKind() {
-		case "Deployment", "ServiceAccount", "Role", "RoleBinding", "Service", "ConfigMap", "Route":
+		case "Deployment", "ServiceAccount", "Role", "RoleBinding", "Service", "ConfigMap", "Route", "NetworkPolicy":
 			unstructured.SetNamespace(backplaneConfig.Spec.TargetNamespace)
 		}
 		templates = append(templates, unstructured)
@@ -278,6 +352,44 @@ func renderTemplates(chartPath string, backplaneConfig *v1.MultiClusterEngine, i
 	return templates, errs
 }
 
+// injectMaintenanceReplicas scales a rendered Deployment to zero while in maintenance mode
+func injectMaintenanceReplicas(template *unstructured.Unstructured, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" || !utils.IsMaintenanceMode(backplaneConfig) {
+		return nil
+	}
+	return unstructured.SetNestedField(template.Object, int64(0), "spec", "replicas")
+}
+
+// injectServiceMonitorMetadata adds the configured labels and annotations to a rendered ServiceMonitor
+// without overwriting any set by the chart or the operator
+func injectServiceMonitorMetadata(template *unstructured.Unstructured, backplaneConfig *v1.MultiClusterEngine) {
+	if template.GetKind() != "ServiceMonitor" || backplaneConfig.Spec.Overrides == nil || backplaneConfig.Spec.Overrides.ServiceMonitor == nil {
+		return
+	}
+	config := backplaneConfig.Spec.Overrides.ServiceMonitor
+	template.SetLabels(mergeMissing(template.GetLabels(), config.Labels))
+	template.SetAnnotations(mergeMissing(template.GetAnnotations(), config.Annotations))
+}
+
+// mergeMissing returns existing with any keys from extra it does not already have
+func mergeMissing(existing, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return existing
+	}
+	merged := map[string]string{}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	for k, v := range existing {
+		merged[k] = v
+	}
+	return merged
+}
+
+// servingCertAnnotation requests a serving certificate from the service CA. It is set by the
+// templates and cannot be overridden.
+const servingCertAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+
 func injectValuesOverrides(values *Values, backplaneConfig *v1.MultiClusterEngine, images map[string]string) {
 
 	values.Global.ImageOverrides = images
@@ -286,7 +398,8 @@ func injectValuesOverrides(values *Values, backplaneConfig *v1.MultiClusterEngin
 
 	values.Global.Namespace = backplaneConfig.Spec.TargetNamespace
 
-	values.Global.PullSecret = backplaneConfig.Spec.ImagePullSecret
+	// An unresolvable pull secret fails validation before anything is rendered
+	values.Global.PullSecret, _ = utils.ImagePullSecretName(backplaneConfig)
 
 	values.HubConfig.ReplicaCount = utils.DefaultReplicaCount(backplaneConfig)
 
@@ -298,6 +411,25 @@ func injectValuesOverrides(values *Values, backplaneConfig *v1.MultiClusterEngin
 		values.HubConfig.Tolerations = convertTolerations(utils.DefaultTolerations())
 	}
 
+	values.HubConfig.DisableTrustBundle = utils.IsTrustBundleDisabled(backplaneConfig)
+
+	if backplaneConfig.Spec.Overrides != nil {
+		values.HubConfig.InfrastructureCA = backplaneConfig.Spec.Overrides.InfrastructureCAConfigMap
+		values.HubConfig.RegistriesConf = backplaneConfig.Spec.Overrides.RegistriesConfConfigMap
+		values.HubConfig.EgressAllowlist = backplaneConfig.Spec.Overrides.DiscoveryEgressAllowlistConfigMap
+	}
+
+	values.HubConfig.WebhookPort = backplaneConfig.WebhookPort()
+	values.HubConfig.WebhookTLSSecret = backplaneConfig.WebhookTLSSecretName()
+	values.HubConfig.MonitoringNamespace = backplaneConfig.MonitoringNamespace()
+	values.HubConfig.ProxyServerServiceType = string(backplaneConfig.ProxyServerServiceType())
+	values.HubConfig.ProxyServerServiceAnnotations = map[string]string{}
+	for k, v := range backplaneConfig.ProxyServerServiceAnnotations() {
+		if k != servingCertAnnotation {
+			values.HubConfig.ProxyServerServiceAnnotations[k] = v
+		}
+	}
+
 	values.Org = "open-cluster-management"
 
 	values.HubConfig.OCPVersion = os.Getenv("ACM_HUB_OCP_VERSION")
@@ -7,14 +7,18 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	backplane "github.com/stolostron/backplane-operator/api/v1"
 	"github.com/stolostron/backplane-operator/pkg/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -277,3 +281,2305 @@ func TestRenderCRDs(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderDisableTrustBundle(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	for _, disabled := range []bool{false, true} {
+		testBackplane := &backplane.MultiClusterEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+			Spec: backplane.MultiClusterEngineSpec{
+				TargetNamespace: "default",
+				Overrides:       &backplane.Overrides{DisableTrustBundle: disabled},
+			},
+		}
+		templates, errs := RenderChart("pkg/templates/charts/toggle/discovery-operator", testBackplane, testImages)
+		if len(errs) > 0 {
+			t.Fatalf("failed to render discovery chart: %v", errs)
+		}
+
+		for _, template := range templates {
+			if template.GetKind() != "Deployment" {
+				continue
+			}
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+				t.Fatalf(err.Error())
+			}
+			mounted := false
+			for _, v := range deployment.Spec.Template.Spec.Volumes {
+				if v.Name == "trusted-ca-bundle" {
+					mounted = true
+				}
+			}
+			if mounted == disabled {
+				t.Errorf("disableTrustBundle=%t: trusted-ca-bundle volume present = %t", disabled, mounted)
+			}
+		}
+	}
+}
+
+func TestRenderInfrastructureCA(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	for _, configMap := range []string{"", "mirror-ca"} {
+		testBackplane := &backplane.MultiClusterEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+			Spec: backplane.MultiClusterEngineSpec{
+				TargetNamespace: "default",
+				Overrides:       &backplane.Overrides{InfrastructureCAConfigMap: configMap},
+			},
+		}
+		templates, errs := RenderChart("pkg/templates/charts/toggle/assisted-service", testBackplane, testImages)
+		if len(errs) > 0 {
+			t.Fatalf("failed to render assisted-service chart: %v", errs)
+		}
+
+		found := false
+		for _, template := range templates {
+			if template.GetKind() != "Deployment" || template.GetName() != "infrastructure-operator" {
+				continue
+			}
+			found = true
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+				t.Fatalf(err.Error())
+			}
+			podSpec := deployment.Spec.Template.Spec
+
+			volume := false
+			for _, v := range podSpec.Volumes {
+				if v.Name == "infrastructure-ca" && v.ConfigMap != nil && v.ConfigMap.Name == configMap {
+					volume = true
+				}
+			}
+			mounted, certDir := false, false
+			for _, m := range podSpec.Containers[0].VolumeMounts {
+				if m.Name == "infrastructure-ca" {
+					mounted = true
+				}
+			}
+			for _, e := range podSpec.Containers[0].Env {
+				if e.Name == "SSL_CERT_DIR" && strings.Contains(e.Value, "/etc/infrastructure-ca") {
+					certDir = true
+				}
+			}
+			want := configMap != ""
+			if volume != want || mounted != want || certDir != want {
+				t.Errorf("infrastructureCAConfigMap=%q: volume = %t, mount = %t, SSL_CERT_DIR = %t, want %t", configMap, volume, mounted, certDir, want)
+			}
+		}
+		if !found {
+			t.Fatal("infrastructure-operator deployment not rendered")
+		}
+	}
+}
+
+func TestRenderRegistriesConf(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	tests := []struct {
+		name       string
+		overrides  backplane.Overrides
+		wantVolume bool
+	}{
+		{name: "unset"},
+		{name: "set", overrides: backplane.Overrides{RegistriesConfConfigMap: "mirrors"}, wantVolume: true},
+		{
+			name:       "set with infrastructure CA",
+			overrides:  backplane.Overrides{RegistriesConfConfigMap: "mirrors", InfrastructureCAConfigMap: "mirror-ca"},
+			wantVolume: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides := tt.overrides
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					TargetNamespace: "default",
+					Overrides:       &overrides,
+				},
+			}
+			templates, errs := RenderChart("pkg/templates/charts/toggle/assisted-service", testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render assisted-service chart: %v", errs)
+			}
+
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != "infrastructure-operator" {
+					continue
+				}
+				found = true
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				podSpec := deployment.Spec.Template.Spec
+
+				volume := false
+				for _, v := range podSpec.Volumes {
+					if v.Name == "registries-conf" && v.ConfigMap != nil && v.ConfigMap.Name == overrides.RegistriesConfConfigMap {
+						volume = true
+					}
+				}
+				mounted, caMounted := false, false
+				for _, m := range podSpec.Containers[0].VolumeMounts {
+					if m.Name == "registries-conf" && m.MountPath == "/etc/containers/registries.conf" && m.SubPath == "registries.conf" {
+						mounted = true
+					}
+					if m.Name == "infrastructure-ca" {
+						caMounted = true
+					}
+				}
+				if volume != tt.wantVolume || mounted != tt.wantVolume {
+					t.Errorf("volume = %t, mount = %t, want %t", volume, mounted, tt.wantVolume)
+				}
+				if want := overrides.InfrastructureCAConfigMap != ""; caMounted != want {
+					t.Errorf("infrastructure-ca mount = %t, want %t", caMounted, want)
+				}
+			}
+			if !found {
+				t.Fatal("infrastructure-operator deployment not rendered")
+			}
+		})
+	}
+}
+
+func TestRenderDiscoveryEgressAllowlist(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	for _, disableTrustBundle := range []bool{false, true} {
+		testBackplane := &backplane.MultiClusterEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+			Spec: backplane.MultiClusterEngineSpec{
+				TargetNamespace: "default",
+				Overrides: &backplane.Overrides{
+					DiscoveryEgressAllowlistConfigMap: "egress-allowlist",
+					DisableTrustBundle:                disableTrustBundle,
+				},
+			},
+		}
+
+		charts, err := os.ReadDir("../../" + chartsDir)
+		if err != nil {
+			t.Fatalf("failed to list charts: %v", err)
+		}
+		wired := []string{}
+		for _, chart := range charts {
+			templates, errs := RenderChart(chartsDir+"/"+chart.Name(), testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", chart.Name(), errs)
+			}
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" {
+					continue
+				}
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				podSpec := deployment.Spec.Template.Spec
+				volume, mounted := false, false
+				for _, v := range podSpec.Volumes {
+					if v.ConfigMap != nil && v.ConfigMap.Name == "egress-allowlist" {
+						volume = true
+					}
+				}
+				for _, c := range podSpec.Containers {
+					for _, m := range c.VolumeMounts {
+						if m.Name == "egress-allowlist" {
+							mounted = true
+						}
+					}
+				}
+				if volume || mounted {
+					wired = append(wired, deployment.Name)
+				}
+				if deployment.Name == "discovery-operator" && (!volume || !mounted) {
+					t.Errorf("disableTrustBundle=%t: discovery-operator volume = %t, mount = %t, want both", disableTrustBundle, volume, mounted)
+				}
+			}
+		}
+		if !reflect.DeepEqual(wired, []string{"discovery-operator"}) {
+			t.Errorf("disableTrustBundle=%t: egress allowlist wired into %v, want only discovery-operator", disableTrustBundle, wired)
+		}
+	}
+}
+
+func TestRenderWebhookConfig(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	tests := []struct {
+		name       string
+		webhook    *backplane.WebhookConfig
+		wantPort   int64
+		wantSecret string
+	}{
+		{name: "default", wantPort: 443, wantSecret: "ocm-webhook"},
+		{name: "custom", webhook: &backplane.WebhookConfig{Port: 9443, TLSSecretName: "webhook-tls"}, wantPort: 9443, wantSecret: "webhook-tls"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					TargetNamespace: "default",
+					Overrides:       &backplane.Overrides{Webhook: tt.webhook},
+				},
+			}
+			templates, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart: %v", errs)
+			}
+			// The service CA only generates and injects the certificate without an external secret
+			serviceCA := tt.webhook == nil
+
+			rendered := map[string]bool{}
+			for _, template := range templates {
+				switch template.GetKind() + "/" + template.GetName() {
+				case "Service/ocm-webhook":
+					rendered[template.GetKind()] = true
+					ports, _, _ := unstructured.NestedSlice(template.Object, "spec", "ports")
+					if port := ports[0].(map[string]interface{})["port"]; port != tt.wantPort {
+						t.Errorf("service port = %v, want %d", port, tt.wantPort)
+					}
+					if _, ok := template.GetAnnotations()["service.beta.openshift.io/serving-cert-secret-name"]; ok != serviceCA {
+						t.Errorf("service serving-cert annotation present = %t, want %t", ok, serviceCA)
+					}
+				case "ValidatingWebhookConfiguration/ocm-validating-webhook", "MutatingWebhookConfiguration/ocm-mutating-webhook":
+					rendered[template.GetKind()] = true
+					webhooks, _, _ := unstructured.NestedSlice(template.Object, "webhooks")
+					port, _, _ := unstructured.NestedInt64(webhooks[0].(map[string]interface{}), "clientConfig", "service", "port")
+					if port != tt.wantPort {
+						t.Errorf("%s port = %d, want %d", template.GetKind(), port, tt.wantPort)
+					}
+					if _, ok := template.GetAnnotations()["service.beta.openshift.io/inject-cabundle"]; ok != serviceCA {
+						t.Errorf("%s inject-cabundle annotation present = %t, want %t", template.GetKind(), ok, serviceCA)
+					}
+				case "Deployment/ocm-webhook":
+					rendered[template.GetKind()] = true
+					deployment := &appsv1.Deployment{}
+					if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+						t.Fatalf(err.Error())
+					}
+					if secret := deployment.Spec.Template.Spec.Volumes[0].Secret.SecretName; secret != tt.wantSecret {
+						t.Errorf("webhook cert secret = %q, want %q", secret, tt.wantSecret)
+					}
+				}
+			}
+			if len(rendered) != 4 {
+				t.Errorf("rendered webhook resources = %v, want the Service, Deployment and both webhook configurations", rendered)
+			}
+		})
+	}
+}
+
+func TestRenderComponentArgs(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{
+						Name:    backplane.ClusterManager,
+						Enabled: true,
+						Args:    []string{"--feature-gates=Foo=false", "--feature-gates=Foo=true"},
+					},
+					{
+						Name:    backplane.ClusterLifecycle,
+						Enabled: true,
+						Args:    []string{"--max-concurrent-reconciles=5"},
+					},
+				},
+			},
+		},
+	}
+	templates, errs := RenderChart("pkg/templates/charts/toggle/cluster-manager", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render cluster-manager chart: %v", errs)
+	}
+
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		want := []string{"/registration-operator", "hub", "--feature-gates=Foo=true"}
+		if got := deployment.Spec.Template.Spec.Containers[0].Args; !reflect.DeepEqual(got, want) {
+			t.Errorf("cluster-manager args = %v, want %v", got, want)
+		}
+	}
+
+	// The args only go to the primary container of the component, as the other deployments of the
+	// chart run different images
+	templates, errs = RenderChart("pkg/templates/charts/toggle/cluster-lifecycle", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render cluster-lifecycle chart: %v", errs)
+	}
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			want := deployment.Name == "cluster-curator-controller" && c.Name == "cluster-curator-controller"
+			if got := strings.Contains(strings.Join(c.Args, " "), "--max-concurrent-reconciles=5"); got != want {
+				t.Errorf("%s container %s args = %v, want component args %t", deployment.Name, c.Name, c.Args, want)
+			}
+		}
+	}
+}
+
+func TestRenderLogLevel(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	globalLevel, foundationLevel := int32(2), int32(6)
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				LogLevel: &globalLevel,
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.ServerFoundation, Enabled: true, LogLevel: &foundationLevel},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath  string
+		deployment string
+		want       string
+	}{
+		{chartPath: "pkg/templates/charts/toggle/server-foundation", deployment: "ocm-controller", want: "--v=6"},
+		{chartPath: "pkg/templates/charts/toggle/server-foundation", deployment: "ocm-proxyserver", want: "--v=6"},
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", deployment: "cluster-manager", want: "--v=2"},
+		// Components without the verbosity flag are left unchanged
+		{chartPath: "pkg/templates/charts/toggle/discovery-operator", deployment: "discovery-operator", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deployment, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != tt.deployment {
+					continue
+				}
+				found = true
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				verbosity := ""
+				for _, a := range deployment.Spec.Template.Spec.Containers[0].Args {
+					if flagName(a) == "--v" || flagName(a) == "-v" {
+						verbosity = a
+					}
+				}
+				if verbosity != tt.want {
+					t.Errorf("%s verbosity flag = %q, want %q", tt.deployment, verbosity, tt.want)
+				}
+			}
+			if !found {
+				t.Fatalf("deployment %s not rendered", tt.deployment)
+			}
+		})
+	}
+
+	invalid := int32(11)
+	testBackplane.Spec.Overrides.Components[0].LogLevel = &invalid
+	if _, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages); len(errs) == 0 {
+		t.Error("expected an error rendering an out of range log level")
+	}
+}
+
+func TestRenderConfigValues(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	values := map[string]string{"workers": "8", "unknown": "1"}
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Discovery, Enabled: true, ConfigValues: values},
+					{Name: backplane.ServerFoundation, Enabled: true, ConfigValues: values},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath  string
+		deployment string
+		want       string
+	}{
+		{chartPath: "pkg/templates/charts/toggle/discovery-operator", deployment: "discovery-operator", want: "--max-concurrent-reconciles=8"},
+		// Keys a component does not support are ignored
+		{chartPath: "pkg/templates/charts/toggle/server-foundation", deployment: "ocm-controller", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deployment, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != tt.deployment {
+					continue
+				}
+				found = true
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				workers := ""
+				for _, a := range deployment.Spec.Template.Spec.Containers[0].Args {
+					if flagName(a) == "--max-concurrent-reconciles" {
+						workers = a
+					}
+				}
+				if workers != tt.want {
+					t.Errorf("%s workers flag = %q, want %q", tt.deployment, workers, tt.want)
+				}
+			}
+			if !found {
+				t.Fatalf("deployment %s not rendered", tt.deployment)
+			}
+		})
+	}
+
+	values["workers"] = "0"
+	if _, errs := RenderChart("pkg/templates/charts/toggle/discovery-operator", testBackplane, testImages); len(errs) == 0 {
+		t.Error("expected an error rendering a non-positive worker count")
+	}
+}
+
+func TestUnsupportedConfigValues(t *testing.T) {
+	values := map[string]string{"workers": "8", "unknown": "1", "importControllerWorkers": "25"}
+	tests := []struct {
+		component string
+		want      []string
+	}{
+		{component: backplane.Discovery, want: []string{"importControllerWorkers", "unknown"}},
+		{component: backplane.ServerFoundation, want: []string{"unknown", "workers"}},
+		{component: backplane.Hive, want: []string{"importControllerWorkers", "unknown", "workers"}},
+	}
+	for _, tt := range tests {
+		if got := unsupportedConfigValues(tt.component, values); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("unsupportedConfigValues(%s) = %v, want %v", tt.component, got, tt.want)
+		}
+	}
+}
+
+func TestRenderImportControllerConfigValues(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{
+						Name:    backplane.ServerFoundation,
+						Enabled: true,
+						ConfigValues: map[string]string{
+							"importControllerReplicas":      "3",
+							"importControllerLeaseDuration": "137s",
+							"importControllerRenewDeadline": "107s",
+							"importControllerRetryPeriod":   "26s",
+						},
+					},
+				},
+			},
+		},
+	}
+	wantArgs := []string{
+		"--leader-election-lease-duration=137s",
+		"--leader-election-renew-deadline=107s",
+		"--leader-election-retry-period=26s",
+	}
+
+	templates, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render chart: %v", errs)
+	}
+	found := false
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		leaderElectionArgs := []string{}
+		for _, a := range deployment.Spec.Template.Spec.Containers[0].Args {
+			if strings.HasPrefix(a, "--leader-election-") {
+				leaderElectionArgs = append(leaderElectionArgs, a)
+			}
+		}
+
+		if deployment.Name != "managedcluster-import-controller-v2" {
+			if len(leaderElectionArgs) > 0 {
+				t.Errorf("deployment %s has import controller args %v", deployment.Name, leaderElectionArgs)
+			}
+			if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 3 {
+				t.Errorf("deployment %s has the import controller replicas", deployment.Name)
+			}
+			continue
+		}
+		found = true
+		if !reflect.DeepEqual(leaderElectionArgs, wantArgs) {
+			t.Errorf("import controller leader election args = %v, want %v", leaderElectionArgs, wantArgs)
+		}
+		if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 3 {
+			t.Errorf("import controller replicas = %v, want 3", deployment.Spec.Replicas)
+		}
+	}
+	if !found {
+		t.Fatal("managedcluster-import-controller-v2 deployment not rendered")
+	}
+
+	testBackplane.Spec.Overrides.Components[0].ConfigValues["importControllerLeaseDuration"] = "137"
+	if _, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages); len(errs) == 0 {
+		t.Error("expected an error rendering a lease duration without a unit")
+	}
+}
+
+func TestRenderImportControllerWorkers(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{
+						Name:         backplane.ServerFoundation,
+						Enabled:      true,
+						ConfigValues: map[string]string{"importControllerWorkers": "25"},
+					},
+				},
+			},
+		},
+	}
+
+	templates, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render chart: %v", errs)
+	}
+	found := false
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		workers := []string{}
+		for _, e := range deployment.Spec.Template.Spec.Containers[0].Env {
+			if e.Name == "MAX_CONCURRENT_RECONCILES" {
+				workers = append(workers, e.Value)
+			}
+		}
+
+		if deployment.Name != "managedcluster-import-controller-v2" {
+			if len(workers) > 0 {
+				t.Errorf("deployment %s has the import controller workers %v", deployment.Name, workers)
+			}
+			continue
+		}
+		found = true
+		if !reflect.DeepEqual(workers, []string{"25"}) {
+			t.Errorf("import controller MAX_CONCURRENT_RECONCILES = %v, want [25]", workers)
+		}
+	}
+	if !found {
+		t.Fatal("managedcluster-import-controller-v2 deployment not rendered")
+	}
+
+	for _, workers := range []string{"0", "101", "ten"} {
+		testBackplane.Spec.Overrides.Components[0].ConfigValues["importControllerWorkers"] = workers
+		if _, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages); len(errs) == 0 {
+			t.Errorf("expected an error rendering %q import controller workers", workers)
+		}
+	}
+}
+
+func Test_mergeArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		overrides []string
+		want      []string
+	}{
+		{
+			name:      "append new flag",
+			args:      []string{"hub", "--v=2"},
+			overrides: []string{"--feature-gates=Foo=true"},
+			want:      []string{"hub", "--v=2", "--feature-gates=Foo=true"},
+		},
+		{
+			name:      "replace existing flag",
+			args:      []string{"hub", "--feature-gates=Foo=false", "--v=2"},
+			overrides: []string{"--feature-gates=Foo=true"},
+			want:      []string{"hub", "--feature-gates=Foo=true", "--v=2"},
+		},
+		{
+			name:      "replace flag with separate value",
+			args:      []string{"--log-level", "info", "--v=2"},
+			overrides: []string{"--log-level=debug"},
+			want:      []string{"--log-level=debug", "--v=2"},
+		},
+		{
+			name:      "duplicate overrides keep last",
+			args:      []string{"hub"},
+			overrides: []string{"--v=4", "--v=6"},
+			want:      []string{"hub", "--v=6"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeArgs(tt.args, tt.overrides); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUpdateStrategy(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	maxUnavailable := intstr.FromInt(1)
+	maxSurge := intstr.FromString("50%")
+	configured := &appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
+		},
+	}
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace:    "default",
+			AvailabilityConfig: backplane.HABasic,
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.ClusterManager, Enabled: true, UpdateStrategy: configured},
+				},
+			},
+		},
+	}
+
+	renderStrategy := func(chartPath string) appsv1.DeploymentStrategy {
+		templates, errs := RenderChart(chartPath, testBackplane, testImages)
+		if len(errs) > 0 {
+			t.Fatalf("failed to render chart %s: %v", chartPath, errs)
+		}
+		for _, template := range templates {
+			if template.GetKind() != "Deployment" {
+				continue
+			}
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+				t.Fatalf(err.Error())
+			}
+			return deployment.Spec.Strategy
+		}
+		t.Fatalf("no deployment rendered from chart %s", chartPath)
+		return appsv1.DeploymentStrategy{}
+	}
+
+	if got := renderStrategy("pkg/templates/charts/toggle/cluster-manager"); !reflect.DeepEqual(got, *configured) {
+		t.Errorf("cluster-manager strategy = %+v, want %+v", got, *configured)
+	}
+
+	got := renderStrategy("pkg/templates/charts/toggle/discovery-operator")
+	if got.RollingUpdate == nil || got.RollingUpdate.MaxUnavailable.IntValue() != 0 || got.RollingUpdate.MaxSurge.IntValue() != 1 {
+		t.Errorf("discovery-operator strategy = %+v, want maxUnavailable=0 and maxSurge=1 for basic availability", got)
+	}
+
+	if got := renderStrategy("pkg/templates/charts/toggle/hive-operator"); got.Type != appsv1.RecreateDeploymentStrategyType {
+		t.Errorf("hive-operator strategy = %+v, want Recreate to be kept", got)
+	}
+
+	testBackplane.Spec.Overrides.Components = []backplane.ComponentConfig{
+		{Name: backplane.ClusterManager, Enabled: true, UpdateStrategy: &appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}},
+	}
+	got = renderStrategy("pkg/templates/charts/toggle/cluster-manager")
+	if got.Type != appsv1.RecreateDeploymentStrategyType || got.RollingUpdate != nil {
+		t.Errorf("cluster-manager strategy = %+v, want Recreate without rollingUpdate parameters", got)
+	}
+}
+
+func TestRenderMaintenanceMode(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	for _, maintenance := range []bool{true, false} {
+		testBackplane := &backplane.MultiClusterEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+			Spec: backplane.MultiClusterEngineSpec{
+				TargetNamespace: "default",
+				Overrides:       &backplane.Overrides{MaintenanceMode: maintenance},
+			},
+		}
+		templates, errs := RenderCharts(chartsDir, testBackplane, testImages)
+		if len(errs) > 0 {
+			t.Fatalf("failed to render charts: %v", errs)
+		}
+
+		for _, template := range templates {
+			if template.GetKind() != "Deployment" {
+				continue
+			}
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+				t.Fatalf(err.Error())
+			}
+			scaledDown := deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0
+			if scaledDown != maintenance {
+				t.Errorf("maintenanceMode=%t: deployment %s replicas = %v", maintenance, deployment.Name, deployment.Spec.Replicas)
+			}
+		}
+	}
+}
+
+func TestRenderServiceMonitorMetadata(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				ServiceMonitor: &backplane.ServiceMonitorConfig{
+					Labels: map[string]string{
+						"openshift.io/user-monitoring": "true",
+						"backplaneconfig.name":         "overridden",
+					},
+					Annotations: map[string]string{"example.com/scrape": "true"},
+				},
+			},
+		},
+	}
+	templates, errs := RenderChart("pkg/templates/charts/toggle/cluster-lifecycle", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render cluster-lifecycle chart: %v", errs)
+	}
+
+	found := false
+	for _, template := range templates {
+		if template.GetKind() != "ServiceMonitor" {
+			continue
+		}
+		found = true
+		if got := template.GetLabels()["openshift.io/user-monitoring"]; got != "true" {
+			t.Errorf("ServiceMonitor %s scrape label = %q, want %q", template.GetName(), got, "true")
+		}
+		if got := template.GetLabels()["backplaneconfig.name"]; got != "testBackplane" {
+			t.Errorf("ServiceMonitor %s operator label = %q, want it kept as %q", template.GetName(), got, "testBackplane")
+		}
+		if got := template.GetAnnotations()["example.com/scrape"]; got != "true" {
+			t.Errorf("ServiceMonitor %s annotation = %q, want %q", template.GetName(), got, "true")
+		}
+	}
+	if !found {
+		t.Fatalf("no ServiceMonitor rendered")
+	}
+}
+
+func TestRenderPriorityClass(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				PriorityClassName: "backplane-critical",
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.ClusterManager, Enabled: true, PriorityClassName: "system-cluster-critical"},
+				},
+			},
+		},
+	}
+
+	tests := map[string]string{
+		"pkg/templates/charts/toggle/cluster-manager":    "system-cluster-critical",
+		"pkg/templates/charts/toggle/discovery-operator": "backplane-critical",
+	}
+	for chartPath, want := range tests {
+		templates, errs := RenderChart(chartPath, testBackplane, testImages)
+		if len(errs) > 0 {
+			t.Fatalf("failed to render chart %s: %v", chartPath, errs)
+		}
+		for _, template := range templates {
+			if template.GetKind() != "Deployment" {
+				continue
+			}
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+				t.Fatalf(err.Error())
+			}
+			if got := deployment.Spec.Template.Spec.PriorityClassName; got != want {
+				t.Errorf("deployment %s priorityClassName = %q, want %q", deployment.Name, got, want)
+			}
+		}
+	}
+}
+
+func TestRenderTopologySpreadConstraints(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	zoneSpread := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+
+	tests := []struct {
+		name         string
+		availability backplane.AvailabilityType
+		want         bool
+	}{
+		{name: "high availability", availability: backplane.HAHigh, want: true},
+		{name: "basic availability", availability: backplane.HABasic, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					TargetNamespace:    "default",
+					AvailabilityConfig: tt.availability,
+					Overrides: &backplane.Overrides{
+						TopologySpreadConstraints: []corev1.TopologySpreadConstraint{zoneSpread},
+					},
+				},
+			}
+
+			templates, errs := RenderChart("pkg/templates/charts/toggle/cluster-lifecycle", testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart: %v", errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != "clusterclaims-controller" {
+					continue
+				}
+				found = true
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				podSpec := deployment.Spec.Template.Spec
+
+				if !tt.want {
+					if len(podSpec.TopologySpreadConstraints) != 0 {
+						t.Errorf("single replica deployment has topology spread constraints %v", podSpec.TopologySpreadConstraints)
+					}
+					return
+				}
+				if len(podSpec.TopologySpreadConstraints) != 1 {
+					t.Fatalf("topologySpreadConstraints = %v, want the zone constraint", podSpec.TopologySpreadConstraints)
+				}
+				got := podSpec.TopologySpreadConstraints[0]
+				if got.TopologyKey != zoneSpread.TopologyKey || got.MaxSkew != zoneSpread.MaxSkew || got.WhenUnsatisfiable != zoneSpread.WhenUnsatisfiable {
+					t.Errorf("topologySpreadConstraint = %v, want %v", got, zoneSpread)
+				}
+				if !reflect.DeepEqual(got.LabelSelector, deployment.Spec.Selector) {
+					t.Errorf("labelSelector = %v, want the deployment selector %v", got.LabelSelector, deployment.Spec.Selector)
+				}
+				if podSpec.Affinity == nil || podSpec.Affinity.PodAntiAffinity == nil {
+					t.Error("expected the default pod anti-affinity to be kept")
+				}
+			}
+			if !found {
+				t.Fatal("clusterclaims-controller deployment not rendered")
+			}
+		})
+	}
+}
+
+func Test_injectImageRepository(t *testing.T) {
+	mirror := "mirror.example.com:5000/ocm"
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "testBackplane",
+			Annotations: map[string]string{utils.AnnotationImageRepo: mirror},
+		},
+	}
+	// Images resolved from the manifest already carry the repository or a configmap override
+	images := map[string]string{"registration_operator": "quay.io/override/registration-operator@sha256:abc123"}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "init", Image: "registry.redhat.io/ubi8/ubi-minimal:latest"},
+					},
+					Containers: []corev1.Container{
+						{Name: "manager", Image: "quay.io/override/registration-operator@sha256:abc123"},
+						{Name: "proxy", Image: "quay.io/brancz/kube-rbac-proxy:v0.13.0"},
+						{Name: "shell", Image: "busybox"},
+					},
+				},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &unstructured.Unstructured{Object: obj}
+
+	if err := injectImageRepository(template, testBackplane, images); err != nil {
+		t.Fatalf("injectImageRepository() error = %v", err)
+	}
+
+	got := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, got); err != nil {
+		t.Fatal(err)
+	}
+	podSpec := got.Spec.Template.Spec
+	if want := mirror + "/ubi-minimal:latest"; podSpec.InitContainers[0].Image != want {
+		t.Errorf("init container image = %q, want %q", podSpec.InitContainers[0].Image, want)
+	}
+	wantContainers := []string{
+		"quay.io/override/registration-operator@sha256:abc123",
+		mirror + "/kube-rbac-proxy:v0.13.0",
+		mirror + "/busybox",
+	}
+	for i, want := range wantContainers {
+		if podSpec.Containers[i].Image != want {
+			t.Errorf("container %s image = %q, want %q", podSpec.Containers[i].Name, podSpec.Containers[i].Image, want)
+		}
+	}
+}
+
+func TestRenderDNSConfig(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	ndots := "2"
+	dnsConfig := &corev1.PodDNSConfig{
+		Nameservers: []string{"10.0.0.10"},
+		Searches:    []string{"corp.example.com"},
+		Options:     []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}},
+	}
+	tests := []struct {
+		name       string
+		overrides  *backplane.Overrides
+		wantPolicy corev1.DNSPolicy
+		wantConfig *corev1.PodDNSConfig
+	}{
+		{
+			name:       "custom dns",
+			overrides:  &backplane.Overrides{DNSPolicy: corev1.DNSClusterFirstWithHostNet, DNSConfig: dnsConfig},
+			wantPolicy: corev1.DNSClusterFirstWithHostNet,
+			wantConfig: dnsConfig,
+		},
+		{
+			// The policy from the template is kept
+			name:       "unset",
+			overrides:  &backplane.Overrides{},
+			wantPolicy: corev1.DNSClusterFirst,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					TargetNamespace: "default",
+					Overrides:       tt.overrides,
+				},
+			}
+			templates, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart: %v", errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != "ocm-controller" {
+					continue
+				}
+				found = true
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				podSpec := deployment.Spec.Template.Spec
+				if podSpec.DNSPolicy != tt.wantPolicy {
+					t.Errorf("deployment %s dnsPolicy = %q, want %q", deployment.Name, podSpec.DNSPolicy, tt.wantPolicy)
+				}
+				if !reflect.DeepEqual(podSpec.DNSConfig, tt.wantConfig) {
+					t.Errorf("deployment %s dnsConfig = %v, want %v", deployment.Name, podSpec.DNSConfig, tt.wantConfig)
+				}
+			}
+			if !found {
+				t.Fatal("ocm-controller deployment not rendered")
+			}
+		})
+	}
+}
+
+func TestRenderAutomountServiceAccountToken(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	disabled := false
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				AutomountServiceAccountToken: &disabled,
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Discovery, Enabled: true, AutomountServiceAccountToken: &disabled},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath  string
+		deployment string
+		want       *bool
+	}{
+		{chartPath: "pkg/templates/charts/toggle/console-mce", deployment: "console-mce-console", want: &disabled},
+		// Components that need the token keep it unless configured themselves
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", deployment: "cluster-manager", want: nil},
+		{chartPath: "pkg/templates/charts/toggle/discovery-operator", deployment: "discovery-operator", want: &disabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deployment, func(t *testing.T) {
+			deployment := renderDeployment(t, tt.chartPath, testBackplane, tt.deployment)
+			if got := deployment.Spec.Template.Spec.AutomountServiceAccountToken; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s automountServiceAccountToken = %v, want %v", tt.deployment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTerminationGracePeriod(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	global, clusterManager := int64(45), int64(120)
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				TerminationGracePeriodSeconds: &global,
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.ClusterManager, Enabled: true, TerminationGracePeriodSeconds: &clusterManager},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath  string
+		deployment string
+		want       int64
+	}{
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", deployment: "cluster-manager", want: clusterManager},
+		{chartPath: "pkg/templates/charts/toggle/discovery-operator", deployment: "discovery-operator", want: global},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deployment, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != tt.deployment {
+					continue
+				}
+				found = true
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				got := deployment.Spec.Template.Spec.TerminationGracePeriodSeconds
+				if got == nil || *got != tt.want {
+					t.Errorf("%s terminationGracePeriodSeconds = %v, want %d", tt.deployment, got, tt.want)
+				}
+			}
+			if !found {
+				t.Fatalf("deployment %s not rendered", tt.deployment)
+			}
+		})
+	}
+}
+
+func TestRenderReadOnlyRootFilesystem(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				SecurityContext: &backplane.SecurityContextOverride{ReadOnlyRootFilesystem: true},
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Hive, Enabled: true, WritableRootFilesystem: true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath  string
+		deployment string
+		readOnly   bool
+	}{
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", deployment: "cluster-manager", readOnly: true},
+		{chartPath: "pkg/templates/charts/toggle/hive-operator", deployment: "hive-operator", readOnly: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deployment, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != tt.deployment {
+					continue
+				}
+				found = true
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+
+				scratchVolumes := 0
+				for _, v := range deployment.Spec.Template.Spec.Volumes {
+					if v.Name == scratchVolume && v.EmptyDir != nil {
+						scratchVolumes++
+					}
+				}
+				for _, c := range deployment.Spec.Template.Spec.Containers {
+					scratchMounted := false
+					for _, m := range c.VolumeMounts {
+						if m.Name == scratchVolume && m.MountPath == "/tmp" {
+							scratchMounted = true
+						}
+					}
+					if !tt.readOnly {
+						if scratchMounted {
+							t.Errorf("container %s of opted out %s has the scratch volume mounted", c.Name, tt.deployment)
+						}
+						continue
+					}
+					if c.SecurityContext == nil || c.SecurityContext.ReadOnlyRootFilesystem == nil || !*c.SecurityContext.ReadOnlyRootFilesystem {
+						t.Errorf("container %s of %s does not have a read-only root filesystem", c.Name, tt.deployment)
+					}
+					if !scratchMounted {
+						t.Errorf("container %s of %s does not mount the scratch volume at /tmp", c.Name, tt.deployment)
+					}
+				}
+				if want := map[bool]int{true: 1, false: 0}[tt.readOnly]; scratchVolumes != want {
+					t.Errorf("%s has %d scratch volumes, want %d", tt.deployment, scratchVolumes, want)
+				}
+			}
+			if !found {
+				t.Fatalf("deployment %s not rendered", tt.deployment)
+			}
+		})
+	}
+}
+
+func TestRenderHostNamespacesDisabled(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				SecurityContext: &backplane.SecurityContextOverride{DisableHostNamespaces: true},
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.ClusterLifecycle, Enabled: true, AllowHostNamespaces: true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath  string
+		deployment string
+		disabled   bool
+	}{
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", deployment: "cluster-manager", disabled: true},
+		{chartPath: "pkg/templates/charts/toggle/server-foundation", deployment: "ocm-controller", disabled: true},
+		{chartPath: "pkg/templates/charts/toggle/cluster-lifecycle", deployment: "clusterlifecycle-state-metrics-v2", disabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deployment, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" || template.GetName() != tt.deployment {
+					continue
+				}
+				found = true
+				for _, field := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+					value, set, err := unstructured.NestedBool(template.Object, "spec", "template", "spec", field)
+					if err != nil {
+						t.Fatalf("unable to read %s of %s: %v", field, tt.deployment, err)
+					}
+					if tt.disabled && (!set || value) {
+						t.Errorf("%s of %s = %v (set: %v), want explicitly false", field, tt.deployment, value, set)
+					}
+					if !tt.disabled && set {
+						t.Errorf("%s of %s is set for a component allowing host namespaces", field, tt.deployment)
+					}
+				}
+			}
+			if !found {
+				t.Fatalf("deployment %s not rendered", tt.deployment)
+			}
+		})
+	}
+}
+
+func TestRenderImagePullSecretFromEnv(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+	t.Setenv("PULL_SECRET_NAME", "tenant-a-pull-secret")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			ImagePullSecret: "$(PULL_SECRET_NAME)",
+		},
+	}
+
+	templates, errs := RenderChart("pkg/templates/charts/toggle/cluster-manager", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render chart: %v", errs)
+	}
+	found := false
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		found = true
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		secrets := deployment.Spec.Template.Spec.ImagePullSecrets
+		if len(secrets) != 1 || secrets[0].Name != "tenant-a-pull-secret" {
+			t.Errorf("imagePullSecrets of %s = %v, want the resolved tenant-a-pull-secret", deployment.Name, secrets)
+		}
+	}
+	if !found {
+		t.Fatalf("no deployment rendered")
+	}
+}
+
+func TestRenderServiceAccountAnnotations(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	roleARN := "arn:aws:iam::123456789012:role/discovery"
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Discovery, Enabled: true, ServiceAccountAnnotations: map[string]string{
+						"eks.amazonaws.com/role-arn": roleARN,
+						"operator-managed":           "overridden",
+					}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath      string
+		serviceAccount string
+		want           string
+	}{
+		{chartPath: "pkg/templates/charts/toggle/discovery-operator", serviceAccount: "discovery-operator", want: roleARN},
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", serviceAccount: "cluster-manager", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.serviceAccount, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			found := false
+			for _, template := range templates {
+				if template.GetKind() != "ServiceAccount" || template.GetName() != tt.serviceAccount {
+					continue
+				}
+				found = true
+				if got := template.GetAnnotations()["eks.amazonaws.com/role-arn"]; got != tt.want {
+					t.Errorf("role-arn annotation of %s = %q, want %q", tt.serviceAccount, got, tt.want)
+				}
+			}
+			if !found {
+				t.Fatalf("service account %s not rendered", tt.serviceAccount)
+			}
+		})
+	}
+
+	// Annotations set by the operator are not overwritten
+	sa := &unstructured.Unstructured{}
+	sa.SetKind("ServiceAccount")
+	sa.SetName("discovery-operator")
+	sa.SetAnnotations(map[string]string{"operator-managed": "kept"})
+	injectServiceAccountAnnotations(sa, "discovery-operator", testBackplane)
+	if got := sa.GetAnnotations(); got["operator-managed"] != "kept" || got["eks.amazonaws.com/role-arn"] != roleARN {
+		t.Errorf("annotations = %v, want the operator annotation kept and the role-arn added", got)
+	}
+}
+
+func TestRenderSidecars(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	sidecar := corev1.Container{Name: "log-agent", Image: "quay.io/org/log-agent:1.0"}
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Discovery, Enabled: true, Sidecars: []corev1.Container{sidecar}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath   string
+		wantSidecar bool
+	}{
+		{chartPath: "pkg/templates/charts/toggle/discovery-operator", wantSidecar: true},
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", wantSidecar: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.chartPath, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" {
+					continue
+				}
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf("failed to convert deployment %s: %v", template.GetName(), err)
+				}
+				containers := deployment.Spec.Template.Spec.Containers
+				last := containers[len(containers)-1]
+				if got := last.Name == sidecar.Name && last.Image == sidecar.Image; got != tt.wantSidecar {
+					t.Errorf("sidecar in deployment %s = %v, want %v", template.GetName(), got, tt.wantSidecar)
+				}
+				if tt.wantSidecar && len(containers) < 2 {
+					t.Errorf("sidecar replaced the containers of deployment %s", template.GetName())
+				}
+			}
+		})
+	}
+
+	// A sidecar named like a container of the component is rejected
+	testBackplane.Spec.Overrides.Components[0].Sidecars = []corev1.Container{{Name: "discovery-operator", Image: sidecar.Image}}
+	if _, errs := RenderChart("pkg/templates/charts/toggle/discovery-operator", testBackplane, testImages); len(errs) == 0 {
+		t.Error("expected an error for a sidecar colliding with a container of the component")
+	}
+}
+
+func TestRenderDiscoveryCache(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	sizeLimit := resource.MustParse("5Gi")
+	tests := []struct {
+		name     string
+		readOnly bool
+		cache    *backplane.DiscoveryCacheConfig
+		want     corev1.VolumeSource
+	}{
+		{
+			name:     "default read-only scratch volume",
+			readOnly: true,
+			want:     corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		},
+		{
+			name:     "claim replaces the scratch volume",
+			readOnly: true,
+			cache:    &backplane.DiscoveryCacheConfig{PersistentVolumeClaim: "discovery-cache"},
+			want: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "discovery-cache"},
+			},
+		},
+		{
+			name:  "sized emptyDir",
+			cache: &backplane.DiscoveryCacheConfig{SizeLimit: &sizeLimit},
+			want:  corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &sizeLimit}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					TargetNamespace: "default",
+					Overrides: &backplane.Overrides{
+						SecurityContext: &backplane.SecurityContextOverride{ReadOnlyRootFilesystem: tt.readOnly},
+						DiscoveryCache:  tt.cache,
+					},
+				},
+			}
+			templates, errs := RenderChart("pkg/templates/charts/toggle/discovery-operator", testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render discovery-operator chart: %v", errs)
+			}
+			var deployment *appsv1.Deployment
+			for _, template := range templates {
+				if template.GetKind() == "Deployment" && template.GetName() == "discovery-operator" {
+					deployment = &appsv1.Deployment{}
+					if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+						t.Fatalf("failed to convert deployment: %v", err)
+					}
+				}
+			}
+			if deployment == nil {
+				t.Fatal("discovery-operator deployment not rendered")
+			}
+
+			mounted := ""
+			for _, m := range deployment.Spec.Template.Spec.Containers[0].VolumeMounts {
+				if m.MountPath == "/tmp" {
+					if mounted != "" {
+						t.Errorf("more than one volume mounted at /tmp")
+					}
+					mounted = m.Name
+				}
+			}
+			if mounted == "" {
+				t.Fatal("no volume mounted at /tmp")
+			}
+			found := false
+			for _, v := range deployment.Spec.Template.Spec.Volumes {
+				if v.Name != mounted {
+					continue
+				}
+				found = true
+				if !reflect.DeepEqual(v.VolumeSource, tt.want) {
+					t.Errorf("volume %s = %+v, want %+v", v.Name, v.VolumeSource, tt.want)
+				}
+			}
+			if !found {
+				t.Errorf("volume %s mounted at /tmp is not defined", mounted)
+			}
+		})
+	}
+}
+
+func TestRenderFSGroup(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	globalGroup, componentGroup := int64(1000650000), int64(2000)
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Discovery, Enabled: true, FSGroup: &componentGroup},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		chartPath   string
+		globalGroup *int64
+		want        *int64
+	}{
+		{name: "component", chartPath: "pkg/templates/charts/toggle/discovery-operator", want: &componentGroup},
+		{name: "component over global", chartPath: "pkg/templates/charts/toggle/discovery-operator", globalGroup: &globalGroup, want: &componentGroup},
+		{name: "global", chartPath: "pkg/templates/charts/toggle/cluster-manager", globalGroup: &globalGroup, want: &globalGroup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane.Spec.Overrides.SecurityContext = nil
+			if tt.globalGroup != nil {
+				testBackplane.Spec.Overrides.SecurityContext = &backplane.SecurityContextOverride{FSGroup: tt.globalGroup}
+			}
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" {
+					continue
+				}
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf("failed to convert deployment %s: %v", template.GetName(), err)
+				}
+				podSecurityContext := deployment.Spec.Template.Spec.SecurityContext
+				if podSecurityContext == nil || !reflect.DeepEqual(podSecurityContext.FSGroup, tt.want) {
+					t.Errorf("deployment %s pod securityContext = %v, want fsGroup %d", template.GetName(), podSecurityContext, *tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderComponentNodeSelector(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	globalSelector := map[string]string{"node-role.kubernetes.io/infra": ""}
+	componentSelector := map[string]string{"discovery": "true"}
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			NodeSelector:    globalSelector,
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Discovery, Enabled: true, NodeSelector: componentSelector},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		chartPath string
+		want      map[string]string
+	}{
+		{chartPath: "pkg/templates/charts/toggle/discovery-operator", want: componentSelector},
+		{chartPath: "pkg/templates/charts/toggle/cluster-manager", want: globalSelector},
+	}
+	for _, tt := range tests {
+		t.Run(tt.chartPath, func(t *testing.T) {
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" {
+					continue
+				}
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf("failed to convert deployment %s: %v", template.GetName(), err)
+				}
+				if got := deployment.Spec.Template.Spec.NodeSelector; !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("deployment %s nodeSelector = %v, want %v", template.GetName(), got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderComponentCommand(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	command := []string{"sleep", "infinity"}
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.Discovery, Enabled: true, Command: command},
+					{Name: backplane.ClusterLifecycle, Enabled: true, Command: command},
+				},
+			},
+		},
+	}
+
+	// Without a container name only the primary container of the component is overridden
+	tests := []struct {
+		name       string
+		chartPath  string
+		allowed    bool
+		deployment string
+	}{
+		{name: "allowed", chartPath: "pkg/templates/charts/toggle/discovery-operator", allowed: true, deployment: "discovery-operator"},
+		{name: "not allowed", chartPath: "pkg/templates/charts/toggle/discovery-operator"},
+		{name: "other component", chartPath: "pkg/templates/charts/toggle/cluster-manager", allowed: true},
+		{name: "primary deployment", chartPath: "pkg/templates/charts/toggle/cluster-lifecycle", allowed: true, deployment: "cluster-curator-controller"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane.SetAnnotations(nil)
+			if tt.allowed {
+				testBackplane.SetAnnotations(map[string]string{backplane.AnnotationAllowCommandOverride: "true"})
+			}
+			templates, errs := RenderChart(tt.chartPath, testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart %s: %v", tt.chartPath, errs)
+			}
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" {
+					continue
+				}
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf("failed to convert deployment %s: %v", template.GetName(), err)
+				}
+				for i, container := range deployment.Spec.Template.Spec.Containers {
+					want := template.GetName() == tt.deployment && i == 0
+					if got := reflect.DeepEqual(container.Command, command); got != want {
+						t.Errorf("deployment %s container %s command = %v, want overridden %v", template.GetName(), container.Name, container.Command, want)
+					}
+					if want && len(container.Args) != 0 {
+						t.Errorf("deployment %s args = %v, want none with the command overridden", template.GetName(), container.Args)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRenderProbes(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	delay, failureThreshold := int32(60), int32(10)
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{
+						Name:    backplane.ClusterManager,
+						Enabled: true,
+						Probes: &backplane.ProbeOverrides{
+							Readiness: &backplane.ProbeTimings{InitialDelaySeconds: &delay, FailureThreshold: &failureThreshold},
+							Startup:   &backplane.ProbeTimings{InitialDelaySeconds: &delay},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	templates, errs := RenderChart("pkg/templates/charts/toggle/cluster-manager", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render cluster-manager chart: %v", errs)
+	}
+	found := false
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" || template.GetName() != "cluster-manager" {
+			continue
+		}
+		found = true
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		container := deployment.Spec.Template.Spec.Containers[0]
+		readiness := container.ReadinessProbe
+		if readiness == nil || readiness.InitialDelaySeconds != delay || readiness.FailureThreshold != failureThreshold {
+			t.Errorf("cluster-manager readinessProbe = %v, want initialDelaySeconds %d and failureThreshold %d", readiness, delay, failureThreshold)
+		}
+		if readiness != nil && (readiness.HTTPGet == nil || readiness.HTTPGet.Path != "/healthz") {
+			t.Errorf("cluster-manager readinessProbe handler = %v, want the template handler kept", readiness.ProbeHandler)
+		}
+		if container.StartupProbe != nil {
+			t.Errorf("cluster-manager startupProbe = %v, want none added", container.StartupProbe)
+		}
+	}
+	if !found {
+		t.Fatal("cluster-manager deployment not rendered")
+	}
+
+	// Other components keep the timings of their templates
+	templates, errs = RenderChart("pkg/templates/charts/toggle/discovery-operator", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render discovery-operator chart: %v", errs)
+	}
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		if readiness := deployment.Spec.Template.Spec.Containers[0].ReadinessProbe; readiness != nil && readiness.InitialDelaySeconds != 5 {
+			t.Errorf("discovery-operator readinessProbe initialDelaySeconds = %d, want 5 from the template", readiness.InitialDelaySeconds)
+		}
+	}
+}
+
+func Test_injectSecurityContext(t *testing.T) {
+	templateUser, overrideUser, overrideGroup := int64(1001), int64(1000650000), int64(1000650000)
+	newTemplate := func() *unstructured.Unstructured {
+		deployment := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						SecurityContext: &corev1.PodSecurityContext{RunAsUser: &templateUser, FSGroup: &templateUser},
+						InitContainers: []corev1.Container{
+							{Name: "init", SecurityContext: &corev1.SecurityContext{RunAsUser: &templateUser}},
+						},
+						Containers: []corev1.Container{
+							{Name: "manager", SecurityContext: &corev1.SecurityContext{RunAsUser: &templateUser}},
+							{Name: "proxy"},
+						},
+					},
+				},
+			},
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+
+	tests := []struct {
+		name      string
+		override  *backplane.SecurityContextOverride
+		wantUser  *int64
+		wantGroup *int64
+	}{
+		{
+			name:      "unset",
+			wantUser:  &templateUser,
+			wantGroup: &templateUser,
+		},
+		{
+			name:     "clear",
+			override: &backplane.SecurityContextOverride{ClearUserIDs: true},
+		},
+		{
+			name:      "set",
+			override:  &backplane.SecurityContextOverride{RunAsUser: &overrideUser, FSGroup: &overrideGroup},
+			wantUser:  &overrideUser,
+			wantGroup: &overrideGroup,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					Overrides: &backplane.Overrides{SecurityContext: tt.override},
+				},
+			}
+			template := newTemplate()
+			if err := injectSecurityContext(template, "", testBackplane); err != nil {
+				t.Fatalf("injectSecurityContext() error = %v", err)
+			}
+
+			got := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, got); err != nil {
+				t.Fatal(err)
+			}
+			podSpec := got.Spec.Template.Spec
+			if !reflect.DeepEqual(podSpec.SecurityContext.RunAsUser, tt.wantUser) {
+				t.Errorf("pod runAsUser = %v, want %v", podSpec.SecurityContext.RunAsUser, tt.wantUser)
+			}
+			if !reflect.DeepEqual(podSpec.SecurityContext.FSGroup, tt.wantGroup) {
+				t.Errorf("pod fsGroup = %v, want %v", podSpec.SecurityContext.FSGroup, tt.wantGroup)
+			}
+			for _, c := range append(podSpec.InitContainers, podSpec.Containers[0]) {
+				if !reflect.DeepEqual(c.SecurityContext.RunAsUser, tt.wantUser) {
+					t.Errorf("container %s runAsUser = %v, want %v", c.Name, c.SecurityContext.RunAsUser, tt.wantUser)
+				}
+			}
+			if podSpec.Containers[1].SecurityContext != nil {
+				t.Errorf("container %s securityContext = %v, want none", podSpec.Containers[1].Name, podSpec.Containers[1].SecurityContext)
+			}
+		})
+	}
+}
+
+func TestRenderImagePullPolicy(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides:       &backplane.Overrides{ImagePullPolicy: corev1.PullNever},
+		},
+	}
+
+	charts, err := os.ReadDir("../../" + chartsDir)
+	if err != nil {
+		t.Fatalf("failed to list charts: %v", err)
+	}
+	// Preloaded images are never pulled, so every container must carry the policy
+	deployments := 0
+	for _, chart := range charts {
+		chartPath := chartsDir + "/" + chart.Name()
+		templates, errs := RenderChart(chartPath, testBackplane, testImages)
+		if len(errs) > 0 {
+			t.Fatalf("failed to render chart %s: %v", chartPath, errs)
+		}
+		for _, template := range templates {
+			if template.GetKind() != "Deployment" {
+				continue
+			}
+			deployments++
+			deployment := &appsv1.Deployment{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+				t.Fatalf(err.Error())
+			}
+			podSpec := deployment.Spec.Template.Spec
+			for _, c := range append(podSpec.InitContainers, podSpec.Containers...) {
+				if c.ImagePullPolicy != corev1.PullNever {
+					t.Errorf("%s container %s imagePullPolicy = %q, want %q", deployment.Name, c.Name, c.ImagePullPolicy, corev1.PullNever)
+				}
+			}
+		}
+	}
+	if deployments == 0 {
+		t.Fatal("no deployments rendered")
+	}
+}
+
+func TestRenderComponentImage(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+	pinned := "quay.io/stolostron/registration-operator@sha256:8fab4d788241bf364dbc1b8c1ea5ccf18d3145a640dbd456b0dc7ba204e36819"
+
+	testBackplane := &backplane.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "testBackplane",
+			Annotations: map[string]string{utils.AnnotationImageRepo: "mirror.example.com/stolostron"},
+		},
+		Spec: backplane.MultiClusterEngineSpec{
+			TargetNamespace: "default",
+			Overrides: &backplane.Overrides{
+				Components: []backplane.ComponentConfig{
+					{Name: backplane.ClusterManager, Enabled: true, Image: pinned},
+				},
+			},
+		},
+	}
+
+	templates, errs := RenderChart("pkg/templates/charts/toggle/cluster-manager", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render cluster-manager chart: %v", errs)
+	}
+	found := false
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" || template.GetName() != "cluster-manager" {
+			continue
+		}
+		found = true
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		if got := deployment.Spec.Template.Spec.Containers[0].Image; got != pinned {
+			t.Errorf("cluster-manager image = %s, want %s", got, pinned)
+		}
+	}
+	if !found {
+		t.Fatal("cluster-manager deployment not rendered")
+	}
+
+	// Only the primary container is pinned, as the other deployments of the chart run different
+	// images
+	testBackplane.Spec.Overrides.Components = append(testBackplane.Spec.Overrides.Components,
+		backplane.ComponentConfig{Name: backplane.ServerFoundation, Enabled: true, Image: pinned})
+	templates, errs = RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages)
+	if len(errs) > 0 {
+		t.Fatalf("failed to render server-foundation chart: %v", errs)
+	}
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf(err.Error())
+		}
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			want := deployment.Name == "ocm-controller" && c.Name == "ocm-controller"
+			if got := c.Image == pinned; got != want {
+				t.Errorf("%s container %s image = %s, want pinned %t", deployment.Name, c.Name, c.Image, want)
+			}
+		}
+	}
+
+	testBackplane.Spec.Overrides.Components[0].Image = "quay.io/Invalid Image@sha256:abc"
+	if _, errs := RenderChart("pkg/templates/charts/toggle/cluster-manager", testBackplane, testImages); len(errs) == 0 {
+		t.Error("expected an error rendering an invalid component image")
+	}
+}
+
+func TestRenderProxyServerService(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+	lbAnnotation := "service.beta.kubernetes.io/aws-load-balancer-internal"
+
+	tests := []struct {
+		name            string
+		proxyServer     *backplane.ProxyServerConfig
+		wantType        corev1.ServiceType
+		wantLBAnnotated bool
+	}{
+		{
+			name:        "default",
+			proxyServer: nil,
+			wantType:    corev1.ServiceTypeClusterIP,
+		},
+		{
+			name: "load balancer",
+			proxyServer: &backplane.ProxyServerConfig{
+				ServiceType: corev1.ServiceTypeLoadBalancer,
+				ServiceAnnotations: map[string]string{
+					lbAnnotation: "true",
+					"service.beta.openshift.io/serving-cert-secret-name": "other",
+				},
+			},
+			wantType:        corev1.ServiceTypeLoadBalancer,
+			wantLBAnnotated: true,
+		},
+		{
+			name:        "back to cluster IP",
+			proxyServer: &backplane.ProxyServerConfig{ServiceType: corev1.ServiceTypeClusterIP},
+			wantType:    corev1.ServiceTypeClusterIP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					TargetNamespace: "default",
+					Overrides:       &backplane.Overrides{ProxyServer: tt.proxyServer},
+				},
+			}
+			templates, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render server-foundation chart: %v", errs)
+			}
+			var service *corev1.Service
+			for _, template := range templates {
+				if template.GetKind() != "Service" || template.GetName() != "ocm-proxyserver" {
+					continue
+				}
+				service = &corev1.Service{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, service); err != nil {
+					t.Fatalf(err.Error())
+				}
+			}
+			if service == nil {
+				t.Fatal("ocm-proxyserver service not rendered")
+			}
+			if service.Spec.Type != tt.wantType {
+				t.Errorf("ocm-proxyserver type = %s, want %s", service.Spec.Type, tt.wantType)
+			}
+			if _, ok := service.Annotations[lbAnnotation]; ok != tt.wantLBAnnotated {
+				t.Errorf("ocm-proxyserver annotations = %v, want load balancer annotation %v", service.Annotations, tt.wantLBAnnotated)
+			}
+			if got := service.Annotations["service.beta.openshift.io/serving-cert-secret-name"]; got != "ocm-proxyserver" {
+				t.Errorf("ocm-proxyserver serving cert annotation = %s, want ocm-proxyserver", got)
+			}
+		})
+	}
+}
+
+func TestRenderHostAliases(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../../")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+	hostAliases := []corev1.HostAlias{
+		{IP: "10.0.0.20", Hostnames: []string{"registry.corp.example.com", "mirror.corp.example.com"}},
+	}
+
+	tests := []struct {
+		name      string
+		overrides *backplane.Overrides
+		want      []corev1.HostAlias
+	}{
+		{name: "host aliases", overrides: &backplane.Overrides{HostAliases: hostAliases}, want: hostAliases},
+		{name: "unset", overrides: &backplane.Overrides{}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testBackplane := &backplane.MultiClusterEngine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testBackplane"},
+				Spec: backplane.MultiClusterEngineSpec{
+					TargetNamespace: "default",
+					Overrides:       tt.overrides,
+				},
+			}
+			templates, errs := RenderChart("pkg/templates/charts/toggle/server-foundation", testBackplane, testImages)
+			if len(errs) > 0 {
+				t.Fatalf("failed to render chart: %v", errs)
+			}
+			deployments := 0
+			for _, template := range templates {
+				if template.GetKind() != "Deployment" {
+					continue
+				}
+				deployments++
+				deployment := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+					t.Fatalf(err.Error())
+				}
+				if got := deployment.Spec.Template.Spec.HostAliases; !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("deployment %s hostAliases = %v, want %v", deployment.Name, got, tt.want)
+				}
+			}
+			if deployments == 0 {
+				t.Fatal("no deployments rendered")
+			}
+		})
+	}
+}
+
+// renderTestImages returns a test image for every image key
+func renderTestImages() map[string]string {
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
+	return testImages
+}
+
+// renderDeployment renders a chart for the multiclusterengine and returns the named Deployment
+func renderDeployment(t *testing.T, chartPath string, mce *backplane.MultiClusterEngine, name string) *appsv1.Deployment {
+	t.Helper()
+	templates, errs := RenderChart(chartPath, mce, renderTestImages())
+	if len(errs) > 0 {
+		t.Fatalf("failed to render chart %s: %v", chartPath, errs)
+	}
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" || template.GetName() != name {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+			t.Fatalf("failed to convert deployment %s: %v", name, err)
+		}
+		return deployment
+	}
+	t.Fatalf("deployment %s not rendered from chart %s", name, chartPath)
+	return nil
+}
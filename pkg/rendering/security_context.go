@@ -0,0 +1,179 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// injectSecurityContext applies the securityContext override to the pod and containers of a
+// rendered pod template. User IDs are cleared first, so that values set in the override are
+// applied on top. The fsGroup of the chart's component takes precedence over the global one.
+func injectSecurityContext(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] || backplaneConfig.Spec.Overrides == nil {
+		return nil
+	}
+	override := backplaneConfig.Spec.Overrides.SecurityContext
+	if override == nil {
+		override = &v1.SecurityContextOverride{}
+	}
+	fsGroup := override.FSGroup
+	if component, ok := chartComponents[chartName]; ok {
+		fsGroup = backplaneConfig.ComponentFSGroup(component)
+	}
+	podSecurityContext := []string{"spec", "template", "spec", "securityContext"}
+
+	if override.ClearUserIDs {
+		unstructured.RemoveNestedField(template.Object, append(podSecurityContext, "runAsUser")...)
+		unstructured.RemoveNestedField(template.Object, append(podSecurityContext, "fsGroup")...)
+	}
+	if override.RunAsUser != nil {
+		if err := unstructured.SetNestedField(template.Object, *override.RunAsUser, append(podSecurityContext, "runAsUser")...); err != nil {
+			return err
+		}
+	}
+	if fsGroup != nil {
+		if err := unstructured.SetNestedField(template.Object, *fsGroup, append(podSecurityContext, "fsGroup")...); err != nil {
+			return err
+		}
+	}
+
+	if !override.ClearUserIDs && override.RunAsUser == nil {
+		return nil
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", field)
+		if err != nil {
+			return fmt.Errorf("unable to read %s of %s %s: %w", field, template.GetKind(), template.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("unable to read %s of %s %s", field, template.GetKind(), template.GetName())
+			}
+			if _, ok, _ := unstructured.NestedFieldNoCopy(container, "securityContext", "runAsUser"); !ok {
+				continue
+			}
+			if override.RunAsUser == nil {
+				unstructured.RemoveNestedField(container, "securityContext", "runAsUser")
+			} else if err := unstructured.SetNestedField(container, *override.RunAsUser, "securityContext", "runAsUser"); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scratchVolume is the emptyDir mounted at scratchPath in containers running with a read-only root
+// filesystem
+const (
+	scratchVolume = "tmp-scratch"
+	scratchPath   = "/tmp"
+)
+
+// injectReadOnlyRootFilesystem sets readOnlyRootFilesystem on the containers of a rendered pod
+// template when the component runs with a read-only root filesystem, and mounts an emptyDir at /tmp
+// in the containers that do not mount anything there. Charts that do not belong to a component
+// follow the global setting.
+func injectReadOnlyRootFilesystem(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] || backplaneConfig.Spec.Overrides == nil || backplaneConfig.Spec.Overrides.SecurityContext == nil {
+		return nil
+	}
+	readOnly := backplaneConfig.Spec.Overrides.SecurityContext.ReadOnlyRootFilesystem
+	if component, ok := chartComponents[chartName]; ok {
+		readOnly = backplaneConfig.ComponentReadOnlyRootFilesystem(component)
+	}
+	if !readOnly {
+		return nil
+	}
+
+	scratchMounted := false
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", field)
+		if err != nil {
+			return fmt.Errorf("unable to read %s of %s %s: %w", field, template.GetKind(), template.GetName(), err)
+		}
+		if !found {
+			continue
+		}
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("unable to read %s of %s %s", field, template.GetKind(), template.GetName())
+			}
+			if err := unstructured.SetNestedField(container, true, "securityContext", "readOnlyRootFilesystem"); err != nil {
+				return err
+			}
+
+			mounts, _, err := unstructured.NestedSlice(container, "volumeMounts")
+			if err != nil {
+				return fmt.Errorf("unable to read volumeMounts of %s %s: %w", template.GetKind(), template.GetName(), err)
+			}
+			if hasMountPath(mounts, scratchPath) {
+				continue
+			}
+			mounts = append(mounts, map[string]interface{}{"name": scratchVolume, "mountPath": scratchPath})
+			if err := unstructured.SetNestedSlice(container, mounts, "volumeMounts"); err != nil {
+				return err
+			}
+			scratchMounted = true
+		}
+		if err := unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", field); err != nil {
+			return err
+		}
+	}
+	if !scratchMounted {
+		return nil
+	}
+
+	volumes, _, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "volumes")
+	if err != nil {
+		return fmt.Errorf("unable to read volumes of %s %s: %w", template.GetKind(), template.GetName(), err)
+	}
+	volumes = append(volumes, map[string]interface{}{"name": scratchVolume, "emptyDir": map[string]interface{}{}})
+	return unstructured.SetNestedSlice(template.Object, volumes, "spec", "template", "spec", "volumes")
+}
+
+// hostNamespaceFields are the pod spec fields sharing a host namespace with the pod
+var hostNamespaceFields = []string{"hostNetwork", "hostPID", "hostIPC"}
+
+// injectHostNamespaces sets hostNetwork, hostPID and hostIPC to false on a rendered pod template
+// when host namespaces are disabled for its component. Charts that do not belong to a component
+// follow the global setting.
+func injectHostNamespaces(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] || backplaneConfig.Spec.Overrides == nil || backplaneConfig.Spec.Overrides.SecurityContext == nil {
+		return nil
+	}
+	disabled := backplaneConfig.Spec.Overrides.SecurityContext.DisableHostNamespaces
+	if component, ok := chartComponents[chartName]; ok {
+		disabled = backplaneConfig.ComponentHostNamespacesDisabled(component)
+	}
+	if !disabled {
+		return nil
+	}
+	for _, field := range hostNamespaceFields {
+		if err := unstructured.SetNestedField(template.Object, false, "spec", "template", "spec", field); err != nil {
+			return fmt.Errorf("unable to set %s of %s %s: %w", field, template.GetKind(), template.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// hasMountPath returns true if one of the volume mounts is mounted at the path
+func hasMountPath(mounts []interface{}, path string) bool {
+	for _, m := range mounts {
+		if mount, ok := m.(map[string]interface{}); ok && mount["mountPath"] == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// serviceAccountTokenComponents are the components whose pods reach the API server with their
+// service account token. The global AutomountServiceAccountToken override is not applied to them.
+var serviceAccountTokenComponents = map[string]bool{
+	v1.AssistedService:           true,
+	v1.ClusterLifecycle:          true,
+	v1.ClusterManager:            true,
+	v1.ClusterProxyAddon:         true,
+	v1.Discovery:                 true,
+	v1.Hive:                      true,
+	v1.HyperShift:                true,
+	v1.ImageBasedInstallOperator: true,
+	v1.ManagedServiceAccount:     true,
+	v1.ServerFoundation:          true,
+}
+
+// injectAutomountServiceAccountToken sets automountServiceAccountToken on the pods of a rendered pod
+// template. A value configured for the component always applies, while the global value skips
+// the components that need the token.
+func injectAutomountServiceAccountToken(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !podTemplateKinds[template.GetKind()] || backplaneConfig.Spec.Overrides == nil {
+		return nil
+	}
+
+	automount := backplaneConfig.Spec.Overrides.AutomountServiceAccountToken
+	if component, ok := chartComponents[chartName]; ok {
+		if serviceAccountTokenComponents[component] {
+			automount = nil
+		}
+		if c := backplaneConfig.ComponentAutomountServiceAccountToken(component); c != nil {
+			automount = c
+		}
+	}
+	if automount == nil {
+		return nil
+	}
+	return unstructured.SetNestedField(template.Object, *automount, "spec", "template", "spec", "automountServiceAccountToken")
+}
+
+// injectServiceAccountAnnotations adds the annotations configured for the component to a rendered
+// ServiceAccount. Annotations already set by the template are not overwritten.
+func injectServiceAccountAnnotations(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) {
+	if template.GetKind() != "ServiceAccount" {
+		return
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return
+	}
+	configured := backplaneConfig.ComponentServiceAccountAnnotations(component)
+	if len(configured) == 0 {
+		return
+	}
+	annotations := template.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range configured {
+		if _, set := annotations[k]; !set {
+			annotations[k] = v
+		}
+	}
+	template.SetAnnotations(annotations)
+}
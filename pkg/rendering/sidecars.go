@@ -0,0 +1,68 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// sidecarKinds are the pod template kinds that sidecars are added to. Jobs are left out, as a
+// sidecar that keeps running would prevent them from completing.
+var sidecarKinds = map[string]bool{
+	"DaemonSet":   true,
+	"Deployment":  true,
+	"StatefulSet": true,
+}
+
+// injectSidecars appends the sidecar containers configured for the component to the containers of
+// a rendered pod template. A sidecar named like a container of the pod is an error, as it would
+// either be rejected by the API server or hide the component's container.
+func injectSidecars(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if !sidecarKinds[template.GetKind()] {
+		return nil
+	}
+	component, ok := chartComponents[chartName]
+	if !ok {
+		return nil
+	}
+	sidecars := backplaneConfig.ComponentSidecars(component)
+	if len(sidecars) == 0 {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", field)
+		if err != nil {
+			return fmt.Errorf("unable to read %s of %s %s: %w", field, template.GetKind(), template.GetName(), err)
+		}
+		for _, c := range containers {
+			if container, ok := c.(map[string]interface{}); ok {
+				if name, ok := container["name"].(string); ok {
+					names[name] = true
+				}
+			}
+		}
+	}
+
+	containers, _, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return fmt.Errorf("unable to read containers of %s %s: %w", template.GetKind(), template.GetName(), err)
+	}
+	for i := range sidecars {
+		if names[sidecars[i].Name] {
+			return fmt.Errorf("sidecar %s of component %s collides with a container of %s %s", sidecars[i].Name, component, template.GetKind(), template.GetName())
+		}
+		names[sidecars[i].Name] = true
+		sidecar, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&sidecars[i])
+		if err != nil {
+			return fmt.Errorf("unable to convert sidecar %s of component %s: %w", sidecars[i].Name, component, err)
+		}
+		containers = append(containers, sidecar)
+	}
+	return unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", "containers")
+}
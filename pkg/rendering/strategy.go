@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// defaultUpdateStrategy returns the deployment strategy used when a component does not configure one.
+// With Basic availability components run a single replica, so a replacement pod is surged in and
+// must be ready before the old one is taken down.
+func defaultUpdateStrategy(backplaneConfig *v1.MultiClusterEngine) *appsv1.DeploymentStrategy {
+	if backplaneConfig.Spec.AvailabilityConfig != v1.HABasic {
+		return nil
+	}
+	maxUnavailable := intstr.FromInt(0)
+	maxSurge := intstr.FromInt(1)
+	return &appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
+		},
+	}
+}
+
+// injectUpdateStrategy sets the strategy of a rendered Deployment to the one configured for its
+// component, falling back to the availability based default. Deployments whose template requires
+// a strategy other than RollingUpdate, such as Recreate, only take a configured strategy.
+func injectUpdateStrategy(template *unstructured.Unstructured, chartName string, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" {
+		return nil
+	}
+
+	var strategy *appsv1.DeploymentStrategy
+	if component, ok := chartComponents[chartName]; ok {
+		strategy = backplaneConfig.ComponentUpdateStrategy(component)
+	}
+	if strategy == nil {
+		strategyType, _, _ := unstructured.NestedString(template.Object, "spec", "strategy", "type")
+		if strategyType != "" && strategyType != string(appsv1.RollingUpdateDeploymentStrategyType) {
+			return nil
+		}
+		strategy = defaultUpdateStrategy(backplaneConfig)
+	}
+	if strategy == nil {
+		return nil
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(strategy)
+	if err != nil {
+		return fmt.Errorf("unable to convert update strategy for deployment %s: %w", template.GetName(), err)
+	}
+	return unstructured.SetNestedMap(template.Object, obj, "spec", "strategy")
+}
@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package renderer
+
+import (
+	"fmt"
+
+	v1 "github.com/stolostron/backplane-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// injectTopologySpreadConstraints adds the configured topology spread constraints to the pods of a
+// rendered Deployment running more than one replica. Constraints from the template are kept unless
+// a configured constraint uses the same topology key.
+func injectTopologySpreadConstraints(template *unstructured.Unstructured, backplaneConfig *v1.MultiClusterEngine) error {
+	if template.GetKind() != "Deployment" || backplaneConfig.Spec.Overrides == nil ||
+		len(backplaneConfig.Spec.Overrides.TopologySpreadConstraints) == 0 {
+		return nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, deployment); err != nil {
+		return fmt.Errorf("unable to read deployment %s: %w", template.GetName(), err)
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas < 2 {
+		return nil
+	}
+
+	configured := map[string]bool{}
+	for _, c := range backplaneConfig.Spec.Overrides.TopologySpreadConstraints {
+		configured[c.TopologyKey] = true
+	}
+
+	constraints := []interface{}{}
+	for _, c := range deployment.Spec.Template.Spec.TopologySpreadConstraints {
+		if configured[c.TopologyKey] {
+			continue
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&c)
+		if err != nil {
+			return fmt.Errorf("unable to convert topology spread constraint for deployment %s: %w", template.GetName(), err)
+		}
+		constraints = append(constraints, obj)
+	}
+	for _, c := range backplaneConfig.Spec.Overrides.TopologySpreadConstraints {
+		constraint := c.DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = deployment.Spec.Selector.DeepCopy()
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(constraint)
+		if err != nil {
+			return fmt.Errorf("unable to convert topology spread constraint for deployment %s: %w", template.GetName(), err)
+		}
+		constraints = append(constraints, obj)
+	}
+	return unstructured.SetNestedSlice(template.Object, constraints, "spec", "template", "spec", "topologySpreadConstraints")
+}
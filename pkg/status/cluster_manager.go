@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	bpv1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -43,6 +44,7 @@ func (cms ClusterManagerStatus) Status(k8sClient client.Client) bpv1.ComponentCo
 			},
 		},
 	}
+	cm.SetGroupVersionKind(utils.PreferredGVK(k8sClient.RESTMapper(), cm.GroupVersionKind()))
 	err := k8sClient.Get(context.TODO(), cms.NamespacedName, cm)
 	if err != nil && !apierrors.IsNotFound(err) {
 		fmt.Println("Err getting cluster manager", err)
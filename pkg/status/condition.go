@@ -15,6 +15,17 @@ const (
 	DeployFailedReason = "FailedDeployingComponent"
 	// DeploySuccessReason is when all component have been deployed
 	DeploySuccessReason = "ComponentsDeployed"
+	// ComponentsRollingOutReason is when all components have been deployed and are waiting to
+	// become available
+	ComponentsRollingOutReason = "ComponentsRollingOut"
+	// ImagePullBackOffReason is when a pod of a component cannot pull its image, usually because of a
+	// misconfigured registry or pull secret
+	ImagePullBackOffReason = "ImagePullBackOff"
+	// CrashLoopBackOffReason is when a container of a component keeps crashing and is restarted
+	// with a back-off
+	CrashLoopBackOffReason = "CrashLoopBackOff"
+	// AsExpectedReason is when the multiclusterengine is not degraded
+	AsExpectedReason = "AsExpected"
 	// RequirementsNotMetReason is when there is something missing or misconfigured
 	// that is preventing progress
 	RequirementsNotMetReason = "RequirementsNotMet"
@@ -30,6 +41,59 @@ const (
 	NamespaceTerminatingReason = "ManagedClusterNamespaceTerminating"
 	// PausedReason is added when the multiclusterengine is paused
 	PausedReason = "Paused"
+	// CRDNotFoundReason is added when a resource is skipped because the API serving it is not installed
+	CRDNotFoundReason = "CRDNotFound"
+
+	// DriftCorrectedReason is added when a managed resource modified outside the operator is restored
+	DriftCorrectedReason = "ResourceModified"
+
+	// PriorityClassNotFoundReason is added when a configured priority class does not exist
+	PriorityClassNotFoundReason = "PriorityClassNotFound"
+
+	// CRDsEstablishedReason is when the CRDs of every enabled component are established
+	CRDsEstablishedReason = "CRDsEstablished"
+
+	// CRDsNotEstablishedReason is when components wait for their CRDs to be established
+	CRDsNotEstablishedReason = "WaitingForCRDs"
+
+	// PermissionsDeniedReason is when the operator is forbidden from applying component resources
+	PermissionsDeniedReason = "PermissionsDenied"
+
+	// PermissionsGrantedReason is when every component resource could be applied
+	PermissionsGrantedReason = "PermissionsGranted"
+
+	// OutsideUpgradeWindowReason is added when image upgrades are deferred until the upgrade window
+	OutsideUpgradeWindowReason = "OutsideUpgradeWindow"
+
+	// InvalidUpgradeWindowReason is added when the upgrade window cannot be parsed, in which case
+	// image upgrades are deferred until it is fixed
+	InvalidUpgradeWindowReason = "InvalidUpgradeWindow"
+
+	// ConfigMapNotFoundReason is added when a referenced configmap does not exist
+	ConfigMapNotFoundReason = "ConfigMapNotFound"
+
+	// ProtectedNamespaceReason is added when the target namespace is a protected namespace
+	ProtectedNamespaceReason = "ProtectedNamespace"
+
+	// UnknownComponentReason is added when a component override names a component that does not exist
+	UnknownComponentReason = "UnknownComponent"
+
+	// TargetNamespaceConflictReason is added when another multiclusterengine owns the target namespace
+	TargetNamespaceConflictReason = "TargetNamespaceConflict"
+
+	// ClusterVersionUnreadableReason is added when the ClusterVersion cannot be read
+	ClusterVersionUnreadableReason = "ClusterVersionUnreadable"
+
+	// DowngradeBlockedReason is added when a downgrade of the components is refused
+	DowngradeBlockedReason = "DowngradeBlocked"
+
+	// UnsupportedOnPlatformReason is added when enabled components are skipped because the cluster
+	// cannot support them
+	UnsupportedOnPlatformReason = "UnsupportedOnPlatform"
+
+	// InstallTimedOutReason is added when the components did not become available within the
+	// install timeout
+	InstallTimedOutReason = "InstallTimedOut"
 )
 
 // NewCondition creates a new condition.
@@ -47,7 +111,7 @@ func NewCondition(condType v1.MultiClusterEngineConditionType, status metav1.Con
 // SetCondition sets the status condition. It either overwrites the existing one or creates a new one.
 func setCondition(conditions []v1.MultiClusterEngineCondition, c v1.MultiClusterEngineCondition) []v1.MultiClusterEngineCondition {
 	currentCond := getCondition(conditions, c.Type)
-	if currentCond != nil && currentCond.Status == c.Status && currentCond.Reason == c.Reason {
+	if currentCond != nil && currentCond.Status == c.Status && currentCond.Reason == c.Reason && currentCond.Message == c.Message {
 		// Condition already present
 		return conditions
 	}
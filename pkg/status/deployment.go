@@ -42,7 +42,88 @@ func (ds DeploymentStatus) Status(k8sClient client.Client) bpv1.ComponentConditi
 		return unknownStatus(ds.GetName(), ds.GetKind())
 	}
 
-	return mapDeployment(deploy)
+	ret := mapDeployment(deploy)
+	ret.Progress = deploymentProgress(deploy)
+	ret.UpToDate = deploymentUpToDate(deploy)
+	ret.DesiredReplicas = 1
+	if deploy.Spec.Replicas != nil {
+		ret.DesiredReplicas = *deploy.Spec.Replicas
+	}
+	ret.ReadyReplicas = deploy.Status.ReadyReplicas
+	if reason, message := podFailure(k8sClient, deploy); reason != "" {
+		ret.Available = false
+		ret.Reason = reason
+		ret.Message = message
+	}
+	return ret
+}
+
+// podFailure returns ImagePullBackOffReason and a message naming a pod of the deployment that cannot
+// pull the image of one of its containers, or CrashLoopBackOffReason and a message naming a pod and
+// container that keeps crashing. Returns empty strings if no pod is stuck that way. The deployment
+// itself may still be available in that case, e.g. while a rollout to a new image is stuck.
+func podFailure(k8sClient client.Client, d *appsv1.Deployment) (string, string) {
+	if d.Spec.Selector == nil {
+		return "", ""
+	}
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return "", ""
+	}
+	pods := &corev1.PodList{}
+	if err := k8sClient.List(context.TODO(), pods, client.InNamespace(d.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		fmt.Println("Err listing pods of deployment", err)
+		return "", ""
+	}
+	crashLoop := ""
+	for _, pod := range pods.Items {
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, cs := range statuses {
+				if cs.State.Waiting == nil {
+					continue
+				}
+				switch cs.State.Waiting.Reason {
+				case "ImagePullBackOff", "ErrImagePull":
+					// A missing image is the more actionable failure
+					return ImagePullBackOffReason, fmt.Sprintf("Pod %s cannot pull image %s of container %s: %s", pod.Name, cs.Image, cs.Name, cs.State.Waiting.Message)
+				case "CrashLoopBackOff":
+					if crashLoop == "" {
+						crashLoop = fmt.Sprintf("Container %s of pod %s is crash looping after %d restarts: %s", cs.Name, pod.Name, cs.RestartCount, cs.State.Waiting.Message)
+					}
+				}
+			}
+		}
+	}
+	if crashLoop != "" {
+		return CrashLoopBackOffReason, crashLoop
+	}
+	return "", ""
+}
+
+// deploymentProgress returns the percentage of the deployment's desired replicas that are updated
+// to its latest pod template. A rollout the deployment controller has not observed yet has made no
+// progress.
+func deploymentProgress(d *appsv1.Deployment) *int32 {
+	progress := int32(0)
+	if d.Status.ObservedGeneration < d.Generation {
+		return &progress
+	}
+	desired := int32(1)
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+	progress = 100
+	if desired > 0 && d.Status.UpdatedReplicas < desired {
+		progress = d.Status.UpdatedReplicas * 100 / desired
+	}
+	return &progress
+}
+
+// deploymentUpToDate returns whether the deployment controller has observed the latest generation of
+// the deployment's spec
+func deploymentUpToDate(d *appsv1.Deployment) *bool {
+	upToDate := d.Status.ObservedGeneration >= d.Generation
+	return &upToDate
 }
 
 func mapDeployment(ds *appsv1.Deployment) bpv1.ComponentCondition {
@@ -2,12 +2,16 @@
 package status
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	bpv1 "github.com/stolostron/backplane-operator/api/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_mapDeployment(t *testing.T) {
@@ -103,3 +107,259 @@ func Test_mapDeployment(t *testing.T) {
 		})
 	}
 }
+
+func Test_deploymentProgress(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+	tests := []struct {
+		name string
+		ds   *appsv1.Deployment
+		want int32
+	}{
+		{
+			name: "partially rolled out",
+			ds: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: replicas(4)},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 1},
+			},
+			want: 25,
+		},
+		{
+			name: "rolled out with surge",
+			ds: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: replicas(2)},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 3},
+			},
+			want: 100,
+		},
+		{
+			name: "default replicas",
+			ds: &appsv1.Deployment{
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 1},
+			},
+			want: 100,
+		},
+		{
+			name: "scaled to zero",
+			ds: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: replicas(0)},
+			},
+			want: 100,
+		},
+		{
+			name: "new generation not observed",
+			ds: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: replicas(2)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deploymentProgress(tt.ds); got == nil || *got != tt.want {
+				t.Errorf("deploymentProgress() = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeploymentStatus_Progress(t *testing.T) {
+	replicas := int32(3)
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			UpdatedReplicas: 2,
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(deploy).Build()
+
+	got := DeploymentStatus{NamespacedName: types.NamespacedName{Name: "test-deployment", Namespace: "test"}}.Status(c)
+	if got.Progress == nil || *got.Progress != 66 {
+		t.Errorf("Status() progress = %v, want 66", got.Progress)
+	}
+}
+
+func TestDeploymentStatus_ImagePullBackOff(t *testing.T) {
+	labels := map[string]string{"app": "test-deployment"}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment-abc", Namespace: "test", Labels: labels},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "manager",
+				Image: "registry.example.com/test:missing",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image",
+				}},
+			}},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(deploy, pod).Build()
+
+	ds := DeploymentStatus{NamespacedName: types.NamespacedName{Name: "test-deployment", Namespace: "test"}}
+	got := ds.Status(c)
+	if got.Available || got.Reason != ImagePullBackOffReason {
+		t.Errorf("Status() available = %v, reason = %s, want unavailable with reason %s", got.Available, got.Reason, ImagePullBackOffReason)
+	}
+
+	tracker := StatusTracker{Client: c}
+	tracker.AddComponent(ds)
+	tracker.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionTrue, DeploySuccessReason, "All components deployed"))
+	status := tracker.ReportStatus(bpv1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	if status.Phase == bpv1.MultiClusterEnginePhaseAvailable {
+		t.Errorf("ReportStatus() phase = %v with a pod in ImagePullBackOff", status.Phase)
+	}
+	degraded := getCondition(status.Conditions, bpv1.MultiClusterEngineDegraded)
+	if degraded == nil || degraded.Status != metav1.ConditionTrue || degraded.Reason != ImagePullBackOffReason {
+		t.Errorf("ReportStatus() degraded condition = %v, want reason %s", degraded, ImagePullBackOffReason)
+	}
+
+	// The deployment is available once the image can be pulled
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	if err := c.Update(context.TODO(), pod); err != nil {
+		t.Fatalf("failed to update pod: %v", err)
+	}
+	if got := ds.Status(c); !got.Available {
+		t.Errorf("Status() available = false, reason = %s, want available", got.Reason)
+	}
+}
+
+func TestStatusTracker_PodsListedThroughAPIReader(t *testing.T) {
+	labels := map[string]string{"app": "test-deployment"}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment-abc", Namespace: "test", Labels: labels},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "manager",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}},
+		},
+	}
+	// The pod is only known to the API reader, as it would be without a pod informer in the cache
+	cached := fake.NewClientBuilder().WithObjects(deploy).Build()
+	apiReader := fake.NewClientBuilder().WithObjects(deploy, pod).Build()
+
+	ds := DeploymentStatus{NamespacedName: types.NamespacedName{Name: "test-deployment", Namespace: "test"}}
+	tracker := StatusTracker{Client: cached, APIReader: apiReader}
+	tracker.AddComponent(ds)
+	status := tracker.ReportStatus(bpv1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	if len(status.Components) != 1 || status.Components[0].Reason != ImagePullBackOffReason {
+		t.Errorf("ReportStatus() components = %+v, want the pod listed through the API reader to be in %s", status.Components, ImagePullBackOffReason)
+	}
+	if tracker.ComponentsAvailable(ds) {
+		t.Error("ComponentsAvailable() = true, want the pod listed through the API reader to be stuck")
+	}
+}
+
+func TestDeploymentStatus_CrashLoopBackOff(t *testing.T) {
+	labels := map[string]string{"app": "test-deployment"}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse, Reason: "MinimumReplicasUnavailable"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment-abc", Namespace: "test", Labels: labels},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "sidecar", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{
+					Name:         "manager",
+					RestartCount: 7,
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "CrashLoopBackOff",
+						Message: "back-off 5m0s restarting failed container",
+					}},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(deploy, pod).Build()
+
+	ds := DeploymentStatus{NamespacedName: types.NamespacedName{Name: "test-deployment", Namespace: "test"}}
+	got := ds.Status(c)
+	if got.Available || got.Reason != CrashLoopBackOffReason {
+		t.Errorf("Status() available = %v, reason = %s, want unavailable with reason %s", got.Available, got.Reason, CrashLoopBackOffReason)
+	}
+
+	tracker := StatusTracker{Client: c}
+	tracker.AddComponent(ds)
+	status := tracker.ReportStatus(bpv1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	crashLooping := getCondition(status.Conditions, bpv1.MultiClusterEngineComponentCrashLooping)
+	if crashLooping == nil || crashLooping.Status != metav1.ConditionTrue || crashLooping.Reason != CrashLoopBackOffReason {
+		t.Fatalf("ReportStatus() crash looping condition = %v, want reason %s", crashLooping, CrashLoopBackOffReason)
+	}
+	for _, name := range []string{"test-deployment", "manager"} {
+		if !strings.Contains(crashLooping.Message, name) {
+			t.Errorf("crash looping message %q does not name %s", crashLooping.Message, name)
+		}
+	}
+	if strings.Contains(crashLooping.Message, "sidecar") {
+		t.Errorf("crash looping message %q names a running container", crashLooping.Message)
+	}
+
+	// The condition is removed once the container runs again
+	pod.Status.ContainerStatuses[1].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	if err := c.Update(context.TODO(), pod); err != nil {
+		t.Fatalf("failed to update pod: %v", err)
+	}
+	status = tracker.ReportStatus(bpv1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "test"}, Status: status})
+	if cond := getCondition(status.Conditions, bpv1.MultiClusterEngineComponentCrashLooping); cond != nil {
+		t.Errorf("ReportStatus() kept the crash looping condition: %s", cond.Message)
+	}
+}
+
+func TestDeploymentStatus_UpToDate(t *testing.T) {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test", Generation: 3},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(deploy).Build()
+	ds := DeploymentStatus{NamespacedName: types.NamespacedName{Name: "test-deployment", Namespace: "test"}}
+
+	got := ds.Status(c)
+	if got.UpToDate == nil || *got.UpToDate {
+		t.Errorf("Status() upToDate = %v, want false for a stale observedGeneration", got.UpToDate)
+	}
+
+	deploy.Status.ObservedGeneration = 3
+	if err := c.Status().Update(context.TODO(), deploy); err != nil {
+		t.Fatalf("failed to update deployment status: %v", err)
+	}
+	got = ds.Status(c)
+	if got.UpToDate == nil || !*got.UpToDate {
+		t.Errorf("Status() upToDate = %v, want true once the generation is observed", got.UpToDate)
+	}
+}
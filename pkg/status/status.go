@@ -2,18 +2,48 @@
 package status
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
 	bpv1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
 	"github.com/stolostron/backplane-operator/pkg/version"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// DefaultDegradedGracePeriod is how long components of an available multiclusterengine may be
+// unavailable before it is reported as Degraded
+const DefaultDegradedGracePeriod = 5 * time.Minute
+
 type StatusTracker struct {
 	Client     client.Client
 	UID        string
 	Components []StatusReporter
 	Conditions []bpv1.MultiClusterEngineCondition
+	Images     map[string]string
+
+	// APIReader, when set, lists the pods of deployments straight from the API server. Listing
+	// them through the cache of Client would start an informer holding every pod in the cluster.
+	APIReader client.Reader
+
+	// DegradedGracePeriod is how long components must be continuously unavailable before a
+	// multiclusterengine that was available is reported as Degraded rather than Progressing, so
+	// that rolling restarts do not flap the phase. Zero reports Degraded right away.
+	DegradedGracePeriod time.Duration
+
+	// InstallTimeout is how long after its creation a multiclusterengine that has never been
+	// available is reported as Failed rather than Installing. Zero never times out.
+	InstallTimeout time.Duration
+
+	// mu guards the tracked components, conditions and images, which components applied in
+	// parallel add to
+	mu sync.Mutex
 }
 
 // Flush out any cached data being tracked, and assigns the tracker to a UID
@@ -21,10 +51,23 @@ func (sm *StatusTracker) Reset(uid string) {
 	sm.UID = uid
 	sm.Components = []StatusReporter{}
 	sm.Conditions = []bpv1.MultiClusterEngineCondition{}
+	sm.Images = map[string]string{}
+}
+
+// Records the image a component is deployed with
+func (sm *StatusTracker) AddImage(component, image string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.Images == nil {
+		sm.Images = map[string]string{}
+	}
+	sm.Images[component] = image
 }
 
 // Adds a StatusReporter to the list of statuses to watch
 func (sm *StatusTracker) AddComponent(sr StatusReporter) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	for _, c := range sm.Components {
 		if c.GetName() == sr.GetName() &&
 			c.GetNamespace() == sr.GetNamespace() &&
@@ -38,6 +81,8 @@ func (sm *StatusTracker) AddComponent(sr StatusReporter) {
 
 // Removes a StatusReporter from the list of statuses to watch
 func (sm *StatusTracker) RemoveComponent(sr StatusReporter) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	for i, c := range sm.Components {
 		if c.GetName() == sr.GetName() &&
 			c.GetNamespace() == sr.GetNamespace() &&
@@ -49,45 +94,140 @@ func (sm *StatusTracker) RemoveComponent(sr StatusReporter) {
 }
 
 func (sm *StatusTracker) AddCondition(c bpv1.MultiClusterEngineCondition) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	sm.Conditions = setCondition(sm.Conditions, c)
 }
 
+// Removes a condition of the given type if it is being tracked
+func (sm *StatusTracker) RemoveCondition(condType bpv1.MultiClusterEngineConditionType) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.Conditions = filterOutCondition(sm.Conditions, condType)
+}
+
 func (sm *StatusTracker) ReportStatus(mce bpv1.MultiClusterEngine) bpv1.MultiClusterEngineStatus {
 	components := sm.reportComponents()
 
 	// Infer available condition from component health
 	if allComponentsReady(components) {
-		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineAvailable, metav1.ConditionTrue, ComponentsAvailableReason, ""))
-
+		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineAvailable, metav1.ConditionTrue, ComponentsAvailableReason, "All components are available"))
 	} else {
-		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineAvailable, metav1.ConditionFalse, ComponentsUnavailableReason, ""))
+		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineAvailable, metav1.ConditionFalse, ComponentsUnavailableReason, unavailableMessage(components)))
 	}
 
+	phase := sm.reportPhase(mce, components, sm.reportConditions())
+	sm.reportOperatorConditions(phase, components)
+	sm.reportCrashLooping(components)
 	conditions := sm.reportConditions()
-	phase := sm.reportPhase(mce, components, conditions)
 
 	currentVersion := mce.Status.CurrentVersion
 	if phase == bpv1.MultiClusterEnginePhaseAvailable {
 		currentVersion = version.Version
 	}
 
+	totalReplicas, readyReplicas := summarizeReplicas(components)
 	return bpv1.MultiClusterEngineStatus{
-		Components:     components,
-		Conditions:     conditions,
-		Phase:          phase,
-		DesiredVersion: version.Version,
-		CurrentVersion: currentVersion,
+		Components:           components,
+		Conditions:           conditions,
+		Phase:                phase,
+		AvailableComponents:  summarizeComponents(components),
+		Progress:             summarizeProgress(components),
+		TotalReplicas:        totalReplicas,
+		ReadyReplicas:        readyReplicas,
+		ComponentImages:      sm.reportImages(),
+		DeployedImages:       mce.Status.DeployedImages,
+		DeprecatedComponents: reportDeprecatedComponents(mce),
+		AppliedOverrides:     reportAppliedOverrides(mce),
+		DesiredVersion:       version.Version,
+		CurrentVersion:       currentVersion,
+		Platform:             mce.Status.Platform,
+		OCPVersion:           mce.Status.OCPVersion,
+		LastReconcileTime:    mce.Status.LastReconcileTime,
+		DesiredStateHash:     mce.Status.DesiredStateHash,
+		InstalledCRDs:        mce.Status.InstalledCRDs,
+		EnabledComponents:    mce.Status.EnabledComponents,
+	}
+}
+
+// ComponentsAvailable returns true if every given StatusReporter reports its resource as available
+func (sm *StatusTracker) ComponentsAvailable(srs ...StatusReporter) bool {
+	components := []bpv1.ComponentCondition{}
+	for _, sr := range srs {
+		components = append(components, sr.Status(sm.reporterClient()))
 	}
+	return allComponentsReady(components)
 }
 
 func (sm *StatusTracker) reportComponents() []bpv1.ComponentCondition {
 	components := []bpv1.ComponentCondition{}
 	for _, c := range sm.Components {
-		components = append(components, c.Status(sm.Client))
+		components = append(components, c.Status(sm.reporterClient()))
 	}
 	return components
 }
 
+// reporterClient returns the client the StatusReporters read their resources with
+func (sm *StatusTracker) reporterClient() client.Client {
+	if sm.APIReader == nil {
+		return sm.Client
+	}
+	return podReaderClient{Client: sm.Client, apiReader: sm.APIReader}
+}
+
+// podReaderClient lists pods through apiReader and reads everything else through Client
+type podReaderClient struct {
+	client.Client
+	apiReader client.Reader
+}
+
+func (c podReaderClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*corev1.PodList); ok {
+		return c.apiReader.List(ctx, list, opts...)
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (sm *StatusTracker) reportImages() map[string]string {
+	if len(sm.Images) == 0 {
+		return nil
+	}
+	images := map[string]string{}
+	for k, v := range sm.Images {
+		images[k] = v
+	}
+	return images
+}
+
+func reportDeprecatedComponents(mce bpv1.MultiClusterEngine) []string {
+	deprecated := mce.DeprecatedComponentNames()
+	if len(deprecated) == 0 {
+		return nil
+	}
+	return deprecated
+}
+
+// reportAppliedOverrides summarizes the image overrides of the multiclusterengine, or returns nil
+// if none are set
+func reportAppliedOverrides(mce bpv1.MultiClusterEngine) *bpv1.AppliedOverrides {
+	applied := bpv1.AppliedOverrides{
+		ImageRepository:         utils.GetImageRepository(&mce),
+		ImageOverridesConfigMap: utils.GetImageOverridesConfigmap(&mce),
+	}
+	if mce.Spec.Overrides != nil {
+		applied.ImagePullPolicy = mce.Spec.Overrides.ImagePullPolicy
+		for _, c := range mce.Spec.Overrides.Components {
+			if c.Image != "" {
+				applied.ComponentImages++
+			}
+		}
+	}
+	if applied == (bpv1.AppliedOverrides{}) {
+		return nil
+	}
+	return &applied
+}
+
 func (sm *StatusTracker) reportConditions() []bpv1.MultiClusterEngineCondition {
 	return sm.Conditions
 }
@@ -95,8 +235,9 @@ func (sm *StatusTracker) reportConditions() []bpv1.MultiClusterEngineCondition {
 func (sm *StatusTracker) reportPhase(mce bpv1.MultiClusterEngine, components []bpv1.ComponentCondition, conditions []bpv1.MultiClusterEngineCondition) bpv1.PhaseType {
 	progress := getCondition(conditions, bpv1.MultiClusterEngineProgressing)
 
-	// If operator isn't progressing show error phase
-	if progress != nil && progress.Status == metav1.ConditionFalse {
+	// If operator isn't progressing show error phase. Progressing is also false once the deployed
+	// components have finished rolling out.
+	if progress != nil && progress.Status == metav1.ConditionFalse && progress.Reason != DeploySuccessReason {
 		return bpv1.MultiClusterEnginePhaseError
 	}
 
@@ -105,19 +246,175 @@ func (sm *StatusTracker) reportPhase(mce bpv1.MultiClusterEngine, components []b
 		return bpv1.MultiClusterEnginePhaseUninstalling
 	}
 
+	// Components are intentionally scaled down
+	if utils.IsMaintenanceMode(&mce) {
+		return bpv1.MultiClusterEnginePhaseMaintenance
+	}
+
 	// If status isn't tracking anything show error phase
 	if len(components) == 0 {
 		return bpv1.MultiClusterEnginePhaseError
 	}
 
-	// If a component isn't ready show progressing phase
 	if !allComponentsReady(components) {
-		return bpv1.MultiClusterEnginePhaseProgressing
+		switch mce.Status.CurrentVersion {
+		case "":
+			// Nothing has been successfully installed yet
+			if sm.installTimedOut(mce) {
+				return bpv1.MultiClusterEnginePhaseFailed
+			}
+			return bpv1.MultiClusterEnginePhaseInstalling
+		case version.Version:
+			// The current version was available before, so a component has since gone down. Brief
+			// unavailability, such as during a rolling restart, is only reported as progressing.
+			if unavailableFor(conditions) < sm.DegradedGracePeriod {
+				return bpv1.MultiClusterEnginePhaseProgressing
+			}
+			return bpv1.MultiClusterEnginePhaseDegraded
+		default:
+			// Components are rolling out a new version
+			return bpv1.MultiClusterEnginePhaseProgressing
+		}
 	}
 
 	return bpv1.MultiClusterEnginePhaseAvailable
 }
 
+// installTimedOut returns true if the multiclusterengine was created longer than the install
+// timeout ago
+func (sm *StatusTracker) installTimedOut(mce bpv1.MultiClusterEngine) bool {
+	if sm.InstallTimeout <= 0 || mce.CreationTimestamp.IsZero() {
+		return false
+	}
+	return time.Since(mce.CreationTimestamp.Time) >= sm.InstallTimeout
+}
+
+// unavailableFor returns how long the components have been unavailable, according to the last
+// transition of the Available condition
+func unavailableFor(conditions []bpv1.MultiClusterEngineCondition) time.Duration {
+	available := getCondition(conditions, bpv1.MultiClusterEngineAvailable)
+	if available == nil || available.Status != metav1.ConditionFalse || available.LastTransitionTime.IsZero() {
+		return 0
+	}
+	return time.Since(available.LastTransitionTime.Time)
+}
+
+// reportOperatorConditions sets the Progressing and Degraded conditions the way a ClusterOperator
+// reports them, so that tooling that understands those conditions can read the multiclusterengine.
+// Progressing is only rewritten once the reconciler has deployed every component: it is true while
+// the components roll out and false once they have. Any other Progressing condition is left as the
+// reconciler set it. Degraded is true in the Error and Degraded phases, and whenever a component
+// cannot pull its image, which would otherwise only show as the component not being available.
+func (sm *StatusTracker) reportOperatorConditions(phase bpv1.PhaseType, components []bpv1.ComponentCondition) {
+	progress := getCondition(sm.Conditions, bpv1.MultiClusterEngineProgressing)
+	if progress != nil && (progress.Reason == DeploySuccessReason || progress.Reason == ComponentsRollingOutReason) {
+		switch phase {
+		case bpv1.MultiClusterEnginePhaseInstalling, bpv1.MultiClusterEnginePhaseProgressing, bpv1.MultiClusterEnginePhaseFailed:
+			sm.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionTrue, ComponentsRollingOutReason, unavailableMessage(components)))
+		default:
+			sm.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionFalse, DeploySuccessReason, "All components deployed"))
+		}
+	}
+
+	blocked := progress != nil && progress.Status == metav1.ConditionFalse && progress.Reason != DeploySuccessReason
+	pullFailure := imagePullFailureMessage(components)
+	switch {
+	case phase == bpv1.MultiClusterEnginePhaseError && blocked:
+		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineDegraded, metav1.ConditionTrue, progress.Reason, progress.Message))
+	case phase == bpv1.MultiClusterEnginePhaseFailed:
+		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineDegraded, metav1.ConditionTrue, InstallTimedOutReason,
+			fmt.Sprintf("Install did not complete within %s. %s", sm.InstallTimeout, unavailableMessage(components))))
+	case pullFailure != "":
+		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineDegraded, metav1.ConditionTrue, ImagePullBackOffReason, pullFailure))
+	case phase == bpv1.MultiClusterEnginePhaseError || phase == bpv1.MultiClusterEnginePhaseDegraded:
+		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineDegraded, metav1.ConditionTrue, ComponentsUnavailableReason, unavailableMessage(components)))
+	default:
+		sm.AddCondition(NewCondition(bpv1.MultiClusterEngineDegraded, metav1.ConditionFalse, AsExpectedReason, ""))
+	}
+}
+
+// reportCrashLooping sets the ComponentCrashLooping condition while a component has a container in
+// CrashLoopBackOff, and removes it otherwise
+func (sm *StatusTracker) reportCrashLooping(components []bpv1.ComponentCondition) {
+	messages := []string{}
+	for _, c := range components {
+		if c.Reason == CrashLoopBackOffReason {
+			messages = append(messages, fmt.Sprintf("%s %s: %s", c.Kind, c.Name, c.Message))
+		}
+	}
+	if len(messages) == 0 {
+		sm.RemoveCondition(bpv1.MultiClusterEngineComponentCrashLooping)
+		return
+	}
+	sm.AddCondition(NewCondition(bpv1.MultiClusterEngineComponentCrashLooping, metav1.ConditionTrue, CrashLoopBackOffReason, strings.Join(messages, "; ")))
+}
+
+// imagePullFailureMessage returns the messages of the components whose pods cannot pull their image
+func imagePullFailureMessage(components []bpv1.ComponentCondition) string {
+	messages := []string{}
+	for _, c := range components {
+		if c.Reason == ImagePullBackOffReason {
+			messages = append(messages, fmt.Sprintf("%s %s: %s", c.Kind, c.Name, c.Message))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// unavailableMessage lists the components that are not available
+func unavailableMessage(components []bpv1.ComponentCondition) string {
+	if len(components) == 0 {
+		return "No components are being tracked"
+	}
+	unavailable := []string{}
+	for _, c := range components {
+		if !c.Available {
+			unavailable = append(unavailable, c.Name)
+		}
+	}
+	if len(unavailable) == 0 {
+		return "All components are available"
+	}
+	return fmt.Sprintf("Components not available: %s", strings.Join(unavailable, ", "))
+}
+
+// summarizeComponents returns the number of available components out of the total, e.g. "5/7"
+func summarizeComponents(components []bpv1.ComponentCondition) string {
+	available := 0
+	for _, c := range components {
+		if c.Available {
+			available++
+		}
+	}
+	return fmt.Sprintf("%d/%d", available, len(components))
+}
+
+// summarizeProgress returns the average rollout progress of the components reporting one, or nil
+// if none do
+func summarizeProgress(components []bpv1.ComponentCondition) *int32 {
+	total, count := int32(0), int32(0)
+	for _, c := range components {
+		if c.Progress != nil {
+			total += *c.Progress
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	progress := total / count
+	return &progress
+}
+
+// summarizeReplicas returns the sum of the desired and of the ready replicas of the components
+func summarizeReplicas(components []bpv1.ComponentCondition) (int32, int32) {
+	desired, ready := int32(0), int32(0)
+	for _, c := range components {
+		desired += c.DesiredReplicas
+		ready += c.ReadyReplicas
+	}
+	return desired, ready
+}
+
 func allComponentsReady(components []bpv1.ComponentCondition) bool {
 	if len(components) == 0 {
 		return false
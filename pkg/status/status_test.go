@@ -2,9 +2,13 @@
 package status
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	bpv1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/backplane-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -190,7 +194,7 @@ func TestStatusTracker_ReportStatus(t *testing.T) {
 			want: bpv1.MultiClusterEngineStatus{
 				CurrentVersion: "",
 				DesiredVersion: "9.9.9",
-				Phase:          bpv1.MultiClusterEnginePhaseProgressing,
+				Phase:          bpv1.MultiClusterEnginePhaseInstalling,
 			},
 		},
 		{
@@ -263,3 +267,312 @@ func TestStatusTracker_Reset(t *testing.T) {
 		}
 	})
 }
+
+func Test_RemoveCondition(t *testing.T) {
+	tracker := StatusTracker{}
+	tracker.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionTrue, DeploySuccessReason, "All components deployed"))
+	tracker.AddCondition(NewCondition(bpv1.MultiClusterEngineMonitoringUnavailable, metav1.ConditionTrue, CRDNotFoundReason, ""))
+
+	tracker.RemoveCondition(bpv1.MultiClusterEngineMonitoringUnavailable)
+	cond := tracker.reportConditions()
+	if len(cond) != 1 || cond[0].Type != bpv1.MultiClusterEngineProgressing {
+		t.Errorf("StatusTracker.RemoveCondition() did not remove condition. Got %v", cond)
+	}
+
+	tracker.RemoveCondition(bpv1.MultiClusterEngineMonitoringUnavailable)
+	if len(tracker.reportConditions()) != 1 {
+		t.Errorf("StatusTracker.RemoveCondition() is not idempotent")
+	}
+}
+
+func TestStatusTracker_ReportStatusPhaseTransitions(t *testing.T) {
+	available := false
+	tracker := StatusTracker{Client: fake.NewClientBuilder().Build()}
+	tracker.AddComponent(MockStatus{
+		NamespacedName: types.NamespacedName{Name: "mock-name", Namespace: "mock-ns"},
+		statusFunc: func() bpv1.ComponentCondition {
+			return bpv1.ComponentCondition{Name: "mock-name", Kind: "Deployment", Available: available}
+		},
+	})
+	tracker.AddComponent(MockStatus{
+		NamespacedName: types.NamespacedName{Name: "mock-ready", Namespace: "mock-ns"},
+		statusFunc: func() bpv1.ComponentCondition {
+			return bpv1.ComponentCondition{Name: "mock-ready", Kind: "Deployment", Available: true}
+		},
+	})
+	backplane := bpv1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseInstalling {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseInstalling)
+	}
+	if backplane.Status.AvailableComponents != "1/2" {
+		t.Errorf("StatusTracker.ReportStatus() availableComponents = %v, want %v", backplane.Status.AvailableComponents, "1/2")
+	}
+
+	available = true
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseAvailable {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseAvailable)
+	}
+	if backplane.Status.AvailableComponents != "2/2" {
+		t.Errorf("StatusTracker.ReportStatus() availableComponents = %v, want %v", backplane.Status.AvailableComponents, "2/2")
+	}
+
+	available = false
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseDegraded {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseDegraded)
+	}
+
+	backplane.Status.CurrentVersion = "1.0.0"
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseProgressing {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseProgressing)
+	}
+}
+
+func TestStatusTracker_ReportStatusDegradedGracePeriod(t *testing.T) {
+	available := true
+	tracker := StatusTracker{Client: fake.NewClientBuilder().Build(), DegradedGracePeriod: DefaultDegradedGracePeriod}
+	tracker.AddComponent(MockStatus{
+		NamespacedName: types.NamespacedName{Name: "mock-name", Namespace: "mock-ns"},
+		statusFunc: func() bpv1.ComponentCondition {
+			return bpv1.ComponentCondition{Name: "mock-name", Kind: "Deployment", Available: available}
+		},
+	})
+	backplane := bpv1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseAvailable {
+		t.Fatalf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseAvailable)
+	}
+
+	// A brief unavailability, e.g. during a rolling restart, is only progressing
+	available = false
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseProgressing {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v within the grace period", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseProgressing)
+	}
+	if degraded := getCondition(backplane.Status.Conditions, bpv1.MultiClusterEngineDegraded); degraded == nil || degraded.Status != metav1.ConditionFalse {
+		t.Errorf("Degraded condition = %v, want False within the grace period", degraded)
+	}
+
+	available = true
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseAvailable {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v after recovering", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseAvailable)
+	}
+
+	// Unavailability lasting longer than the grace period is degraded
+	available = false
+	backplane.Status = tracker.ReportStatus(backplane)
+	for i, c := range tracker.Conditions {
+		if c.Type == bpv1.MultiClusterEngineAvailable {
+			tracker.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-DefaultDegradedGracePeriod - time.Minute))
+		}
+	}
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseDegraded {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v after the grace period", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseDegraded)
+	}
+	if degraded := getCondition(backplane.Status.Conditions, bpv1.MultiClusterEngineDegraded); degraded == nil || degraded.Status != metav1.ConditionTrue {
+		t.Errorf("Degraded condition = %v, want True after the grace period", degraded)
+	}
+}
+
+func TestStatusTracker_ReportStatusInstallTimeout(t *testing.T) {
+	tracker := StatusTracker{Client: fake.NewClientBuilder().Build(), InstallTimeout: 30 * time.Minute}
+	tracker.AddComponent(MockStatus{
+		NamespacedName: types.NamespacedName{Name: "mock-name", Namespace: "mock-ns"},
+		statusFunc: func() bpv1.ComponentCondition {
+			return bpv1.ComponentCondition{Name: "mock-name", Kind: "Deployment", Available: false}
+		},
+	})
+	tracker.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionTrue, DeploySuccessReason, "All components deployed"))
+	backplane := bpv1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", CreationTimestamp: metav1.Now()},
+	}
+
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseInstalling {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v within the install timeout", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseInstalling)
+	}
+
+	// The component never becomes available, so the install fails once the timeout has passed
+	backplane.CreationTimestamp = metav1.NewTime(time.Now().Add(-31 * time.Minute))
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseFailed {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v after the install timeout", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseFailed)
+	}
+	degraded := getCondition(backplane.Status.Conditions, bpv1.MultiClusterEngineDegraded)
+	if degraded == nil || degraded.Status != metav1.ConditionTrue || degraded.Reason != InstallTimedOutReason ||
+		!strings.Contains(degraded.Message, "mock-name") {
+		t.Errorf("Degraded condition = %v, want True naming the unavailable component", degraded)
+	}
+	if progressing := getCondition(backplane.Status.Conditions, bpv1.MultiClusterEngineProgressing); progressing == nil || progressing.Status != metav1.ConditionTrue {
+		t.Errorf("Progressing condition = %v, want True while the install is retried", progressing)
+	}
+
+	// Without a timeout the install keeps reporting Installing
+	tracker.InstallTimeout = 0
+	backplane.Status = tracker.ReportStatus(backplane)
+	if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseInstalling {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v without an install timeout", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseInstalling)
+	}
+}
+
+func TestStatusTracker_ReportStatusMaintenance(t *testing.T) {
+	tracker := StatusTracker{Client: fake.NewClientBuilder().Build()}
+	tracker.AddComponent(MockStatus{
+		NamespacedName: types.NamespacedName{Name: "mock-name", Namespace: "mock-ns"},
+		statusFunc: func() bpv1.ComponentCondition {
+			return bpv1.ComponentCondition{Name: "mock-name", Kind: "Deployment", Available: false}
+		},
+	})
+	backplane := bpv1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: bpv1.MultiClusterEngineSpec{
+			Overrides: &bpv1.Overrides{MaintenanceMode: true},
+		},
+		Status: bpv1.MultiClusterEngineStatus{CurrentVersion: "9.9.9"},
+	}
+
+	got := tracker.ReportStatus(backplane)
+	if got.Phase != bpv1.MultiClusterEnginePhaseMaintenance {
+		t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", got.Phase, bpv1.MultiClusterEnginePhaseMaintenance)
+	}
+}
+
+func TestStatusTracker_ReportStatusAppliedOverrides(t *testing.T) {
+	tracker := StatusTracker{Client: fake.NewClientBuilder().Build()}
+	backplane := bpv1.MultiClusterEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       bpv1.MultiClusterEngineSpec{TargetNamespace: "mock-ns"},
+	}
+	if got := tracker.ReportStatus(backplane); got.AppliedOverrides != nil {
+		t.Errorf("StatusTracker.ReportStatus() appliedOverrides = %+v, want nil without overrides", got.AppliedOverrides)
+	}
+
+	backplane.SetAnnotations(map[string]string{
+		utils.AnnotationImageRepo:        "quay.io/mirror",
+		utils.AnnotationImageOverridesCM: "image-overrides",
+	})
+	backplane.Spec.Overrides = &bpv1.Overrides{
+		ImagePullPolicy: corev1.PullAlways,
+		Components: []bpv1.ComponentConfig{
+			{Name: bpv1.Discovery, Enabled: true, Image: "quay.io/mirror/discovery-operator@sha256:1234"},
+			{Name: bpv1.Hive, Enabled: true, Image: "quay.io/mirror/hive@sha256:5678"},
+			{Name: bpv1.ConsoleMCE, Enabled: true},
+		},
+	}
+	want := bpv1.AppliedOverrides{
+		ImageRepository:         "quay.io/mirror",
+		ImagePullPolicy:         corev1.PullAlways,
+		ImageOverridesConfigMap: "image-overrides",
+		ComponentImages:         2,
+	}
+	got := tracker.ReportStatus(backplane)
+	if got.AppliedOverrides == nil || *got.AppliedOverrides != want {
+		t.Errorf("StatusTracker.ReportStatus() appliedOverrides = %+v, want %+v", got.AppliedOverrides, want)
+	}
+}
+
+func Test_summarizeProgress(t *testing.T) {
+	progress := func(p int32) *int32 { return &p }
+	components := []bpv1.ComponentCondition{
+		{Name: "a", Progress: progress(100)},
+		{Name: "b", Progress: progress(50)},
+		{Name: "cluster-manager", Kind: "ClusterManager"},
+	}
+	if got := summarizeProgress(components); got == nil || *got != 75 {
+		t.Errorf("summarizeProgress() = %v, want 75", got)
+	}
+	if got := summarizeProgress(components[2:]); got != nil {
+		t.Errorf("summarizeProgress() = %v, want nil without components reporting progress", *got)
+	}
+}
+
+func TestStatusTracker_ReportStatusOperatorConditions(t *testing.T) {
+	available := false
+	tracker := StatusTracker{Client: fake.NewClientBuilder().Build()}
+	tracker.AddComponent(MockStatus{
+		NamespacedName: types.NamespacedName{Name: "mock-name", Namespace: "mock-ns"},
+		statusFunc: func() bpv1.ComponentCondition {
+			return bpv1.ComponentCondition{Name: "mock-name", Kind: "Deployment", Available: available}
+		},
+	})
+	backplane := bpv1.MultiClusterEngine{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+	wantConditions := func(t *testing.T, conditions []bpv1.MultiClusterEngineCondition, want map[bpv1.MultiClusterEngineConditionType]metav1.ConditionStatus) {
+		t.Helper()
+		for condType, status := range want {
+			c := getCondition(conditions, condType)
+			if c == nil {
+				t.Errorf("StatusTracker.ReportStatus() missing condition %s", condType)
+				continue
+			}
+			if c.Status != status {
+				t.Errorf("StatusTracker.ReportStatus() condition %s = %v, want %v", condType, c.Status, status)
+			}
+			if c.Reason == "" {
+				t.Errorf("StatusTracker.ReportStatus() condition %s has no reason", condType)
+			}
+		}
+	}
+
+	t.Run("Installing", func(t *testing.T) {
+		tracker.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionTrue, DeploySuccessReason, "All components deployed"))
+		backplane.Status = tracker.ReportStatus(backplane)
+		wantConditions(t, backplane.Status.Conditions, map[bpv1.MultiClusterEngineConditionType]metav1.ConditionStatus{
+			bpv1.MultiClusterEngineAvailable:   metav1.ConditionFalse,
+			bpv1.MultiClusterEngineProgressing: metav1.ConditionTrue,
+			bpv1.MultiClusterEngineDegraded:    metav1.ConditionFalse,
+		})
+		if c := getCondition(backplane.Status.Conditions, bpv1.MultiClusterEngineProgressing); c.Message != "Components not available: mock-name" {
+			t.Errorf("StatusTracker.ReportStatus() progressing message = %q", c.Message)
+		}
+	})
+
+	t.Run("Installed", func(t *testing.T) {
+		available = true
+		// The reconciler reports the components as deployed again on every loop
+		tracker.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionTrue, DeploySuccessReason, "All components deployed"))
+		backplane.Status = tracker.ReportStatus(backplane)
+		if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseAvailable {
+			t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseAvailable)
+		}
+		wantConditions(t, backplane.Status.Conditions, map[bpv1.MultiClusterEngineConditionType]metav1.ConditionStatus{
+			bpv1.MultiClusterEngineAvailable:   metav1.ConditionTrue,
+			bpv1.MultiClusterEngineProgressing: metav1.ConditionFalse,
+			bpv1.MultiClusterEngineDegraded:    metav1.ConditionFalse,
+		})
+
+		// A settled Progressing condition carried over from the last status is not an error
+		backplane.Status = tracker.ReportStatus(backplane)
+		if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseAvailable {
+			t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseAvailable)
+		}
+	})
+
+	t.Run("Degraded", func(t *testing.T) {
+		available = false
+		backplane.Status = tracker.ReportStatus(backplane)
+		wantConditions(t, backplane.Status.Conditions, map[bpv1.MultiClusterEngineConditionType]metav1.ConditionStatus{
+			bpv1.MultiClusterEngineAvailable:   metav1.ConditionFalse,
+			bpv1.MultiClusterEngineProgressing: metav1.ConditionFalse,
+			bpv1.MultiClusterEngineDegraded:    metav1.ConditionTrue,
+		})
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		tracker.AddCondition(NewCondition(bpv1.MultiClusterEngineProgressing, metav1.ConditionFalse, RequirementsNotMetReason, "Could not find imagePullSecret"))
+		backplane.Status = tracker.ReportStatus(backplane)
+		if backplane.Status.Phase != bpv1.MultiClusterEnginePhaseError {
+			t.Errorf("StatusTracker.ReportStatus() phase = %v, want %v", backplane.Status.Phase, bpv1.MultiClusterEnginePhaseError)
+		}
+		c := getCondition(backplane.Status.Conditions, bpv1.MultiClusterEngineDegraded)
+		if c == nil || c.Status != metav1.ConditionTrue || c.Reason != RequirementsNotMetReason {
+			t.Errorf("StatusTracker.ReportStatus() degraded condition = %v, want reason %s", c, RequirementsNotMetReason)
+		}
+	})
+}
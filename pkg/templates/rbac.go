@@ -36,6 +36,7 @@ const (
 //+kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=clustermanagementaddons,verbs=create;get;list;update;patch;watch;delete
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins;consolequickstarts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create;update;list;watch;delete;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;create;update;list;watch;delete;patch
 
 // cluster-proxy-addon
 //+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;create;update;list;watch;delete;patch
@@ -54,6 +55,7 @@ var resources = []string{
 	"ManagedProxyConfiguration",
 	"ManagedProxyServiceResolver",
 	"MutatingWebhookConfiguration",
+	"NetworkPolicy",
 	"Role",
 	"RoleBinding",
 	"Route",
@@ -30,6 +30,15 @@ const (
 	ServerFoundationChartDir = "pkg/templates/charts/toggle/server-foundation"
 	HyperShiftChartDir       = "pkg/templates/charts/toggle/hypershift"
 	ClusterProxyAddonDir     = "pkg/templates/charts/toggle/cluster-proxy-addon"
+	NetworkPoliciesChartDir  = "pkg/templates/charts/toggle/network-policies"
+
+	ImageBasedInstallOperatorChartDir = "pkg/templates/charts/toggle/image-based-install-operator"
+	ImageBasedInstallOperatorCRDPath  = "pkg/templates/image-based-install-operator/crds"
+
+	// CRDs installed by the operator on startup
+	ClusterManagerCRDPath = "pkg/templates/crds/cluster-manager"
+	HiveCRDPath           = "pkg/templates/crds/hive-operator"
+	DiscoveryCRDPath      = "pkg/templates/crds/discovery-operator"
 )
 
 func EnabledStatus(namespacedName types.NamespacedName) status.StatusReporter {
@@ -0,0 +1,67 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// EndpointEnvVar is the OTLP/HTTP endpoint spans are exported to, e.g. http://collector:4318.
+	// Tracing is disabled when it is unset.
+	EndpointEnvVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	serviceName = "multicluster-engine-operator"
+	tracerName  = "github.com/stolostron/backplane-operator"
+)
+
+// Span attributes
+const (
+	// MultiClusterEngineKey is the name of the multiclusterengine being reconciled
+	MultiClusterEngineKey = attribute.Key("multiclusterengine.name")
+	// ComponentKey is the name of the component being applied or removed
+	ComponentKey = attribute.Key("multiclusterengine.component")
+	// EnabledKey is whether the component is applied rather than removed
+	EnabledKey = attribute.Key("multiclusterengine.component.enabled")
+	// OutcomeKey is how the traced operation ended, one of the Outcome values
+	OutcomeKey = attribute.Key("outcome")
+)
+
+// Outcomes of a traced operation
+const (
+	OutcomeSuccess = "success"
+	OutcomeRequeue = "requeue"
+	OutcomeError   = "error"
+)
+
+// Setup installs a global tracer provider exporting spans to the endpoint in EndpointEnvVar, which is
+// configured further by the standard OTEL_EXPORTER_OTLP_* variables. Spans are not recorded when no
+// endpoint is set. The returned function flushes the pending spans and stops the exporter.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	if os.Getenv(EndpointEnvVar) == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span from the operator's tracer
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,32 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	t.Setenv(EndpointEnvVar, "")
+	previous := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background())
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if otel.GetTracerProvider() != previous {
+		t.Error("Setup() installed a tracer provider without an endpoint")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+
+	_, span := Start(context.Background(), "test")
+	if span.SpanContext().IsValid() {
+		t.Error("expected spans not to be recorded without an endpoint")
+	}
+	span.End()
+}
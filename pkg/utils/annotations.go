@@ -19,6 +19,22 @@ var (
 	// AnnotationImageOverridesCM identifies a configmap name containing an image override mapping
 	AnnotationImageOverridesCM = "imageOverridesCM"
 
+	// AnnotationRenderedManifests set to "true" has the operator write the manifests it applies to
+	// a configmap for auditing
+	AnnotationRenderedManifests = "renderedManifests"
+
+	// AnnotationRecreate names a component whose resources are deleted and recreated once, to
+	// recover a component an in-place update does not fix. The annotation is removed afterwards.
+	AnnotationRecreate = "multicluster.openshift.io/recreate"
+
+	// AnnotationUpgradeWindow restricts image upgrades of the components to a recurring time range,
+	// e.g. "Sat,Sun 02:00-06:00". Other changes are applied at any time.
+	AnnotationUpgradeWindow = "multicluster.openshift.io/upgrade-window"
+
+	// AnnotationAllowDowngrade set to "true" lets the operator reconcile a multiclusterengine last
+	// deployed by a newer operator version
+	AnnotationAllowDowngrade = "multicluster.openshift.io/allow-downgrade"
+
 	// AnnotationKubeconfig is the secret name residing in targetcontaining the kubeconfig to access the remote cluster
 	AnnotationKubeconfig = "mce-kubeconfig"
 )
@@ -37,6 +53,29 @@ func IsPaused(instance *backplanev1.MultiClusterEngine) bool {
 	return false
 }
 
+// RenderedManifestsEnabled returns true if the multiclusterengine asks for its rendered manifests
+// to be written to a configmap
+func RenderedManifestsEnabled(instance *backplanev1.MultiClusterEngine) bool {
+	return strings.EqualFold(getAnnotation(instance, AnnotationRenderedManifests), "true")
+}
+
+// GetRecreateComponent returns the component the multiclusterengine asks to be recreated, or an
+// empty string if not set
+func GetRecreateComponent(instance *backplanev1.MultiClusterEngine) string {
+	return strings.TrimSpace(getAnnotation(instance, AnnotationRecreate))
+}
+
+// GetUpgradeWindow returns the upgrade window set on the multiclusterengine, or an empty string if
+// upgrades are not restricted
+func GetUpgradeWindow(instance *backplanev1.MultiClusterEngine) string {
+	return strings.TrimSpace(getAnnotation(instance, AnnotationUpgradeWindow))
+}
+
+// DowngradeAllowed returns true if the multiclusterengine allows being downgraded
+func DowngradeAllowed(instance *backplanev1.MultiClusterEngine) bool {
+	return strings.EqualFold(getAnnotation(instance, AnnotationAllowDowngrade), "true")
+}
+
 // AnnotationsMatch returns true if all annotation values used by the operator match
 func AnnotationsMatch(old, new map[string]string) bool {
 	return old[AnnotationMCEPause] == new[AnnotationMCEPause] &&
@@ -0,0 +1,72 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// RelatedImagesPathEnvVar is the environment variable holding the path of a file listing the
+// relatedImages of the operator's ClusterServiceVersion
+const RelatedImagesPathEnvVar = "RELATED_IMAGES_PATH"
+
+// RelatedImage is an entry of the relatedImages of a ClusterServiceVersion
+type RelatedImage struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// relatedImagesCSV holds the relatedImages of a ClusterServiceVersion document
+type relatedImagesCSV struct {
+	Spec struct {
+		RelatedImages []RelatedImage `json:"relatedImages"`
+	} `json:"spec"`
+}
+
+// ParseRelatedImages parses a relatedImages list, in YAML or JSON, into a map of image keys to
+// image references. The list may be given on its own or as the ClusterServiceVersion declaring it.
+// Names are turned into image keys the way RELATED_IMAGE_ environment variables are, e.g.
+// discovery-operator becomes discovery_operator. Every image must be pinned by digest.
+func ParseRelatedImages(data []byte) (map[string]string, error) {
+	var relatedImages []RelatedImage
+	if err := yaml.Unmarshal(data, &relatedImages); err != nil {
+		csv := relatedImagesCSV{}
+		if csvErr := yaml.Unmarshal(data, &csv); csvErr != nil {
+			return nil, err
+		}
+		relatedImages = csv.Spec.RelatedImages
+	}
+
+	images := map[string]string{}
+	for _, i := range relatedImages {
+		if i.Name == "" || i.Image == "" {
+			return nil, fmt.Errorf("related image %q must have a name and an image", i.Name)
+		}
+		if !strings.Contains(i.Image, "@sha256:") {
+			return nil, fmt.Errorf("related image %s is not pinned by digest: %s", i.Name, i.Image)
+		}
+		key := strings.ToLower(strings.ReplaceAll(i.Name, "-", "_"))
+		if _, ok := images[key]; ok {
+			return nil, fmt.Errorf("related image %s is listed more than once", i.Name)
+		}
+		images[key] = i.Image
+	}
+	return images, nil
+}
+
+// LoadRelatedImages reads and parses the relatedImages list in the file at path
+func LoadRelatedImages(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read related images file: %w", err)
+	}
+	images, err := ParseRelatedImages(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse related images file %s: %w", path, err)
+	}
+	return images, nil
+}
@@ -0,0 +1,72 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRelatedImages(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "list",
+			data: `
+- name: discovery-operator
+  image: quay.io/stolostron/discovery-operator@sha256:1234
+- name: console_mce
+  image: quay.io/stolostron/console-mce@sha256:5678
+`,
+			want: map[string]string{
+				"discovery_operator": "quay.io/stolostron/discovery-operator@sha256:1234",
+				"console_mce":        "quay.io/stolostron/console-mce@sha256:5678",
+			},
+		},
+		{
+			name: "clusterserviceversion",
+			data: `
+apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+spec:
+  relatedImages:
+  - name: discovery_operator
+    image: quay.io/stolostron/discovery-operator@sha256:1234
+`,
+			want: map[string]string{
+				"discovery_operator": "quay.io/stolostron/discovery-operator@sha256:1234",
+			},
+		},
+		{
+			name:    "not pinned",
+			data:    `[{"name": "discovery_operator", "image": "quay.io/stolostron/discovery-operator:latest"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing image",
+			data:    `[{"name": "discovery_operator"}]`,
+			wantErr: true,
+		},
+		{
+			name: "duplicate",
+			data: `[{"name": "discovery-operator", "image": "quay.io/a/b@sha256:1"},
+				{"name": "discovery_operator", "image": "quay.io/a/b@sha256:2"}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRelatedImages([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRelatedImages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRelatedImages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
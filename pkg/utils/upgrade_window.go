@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// UpgradeWindow is a recurring daily time range in which image upgrades may roll out
+type UpgradeWindow struct {
+	// days the window opens on. Every day when empty.
+	days  map[time.Weekday]bool
+	start time.Duration
+	end   time.Duration
+}
+
+// ParseUpgradeWindow parses an upgrade window of the form "[days ]HH:MM-HH:MM" in UTC, where days
+// is a comma-separated list of three letter day names, e.g. "Sat,Sun 02:00-06:00". A range ending
+// before it starts spans midnight, and belongs to the day it starts on.
+func ParseUpgradeWindow(value string) (*UpgradeWindow, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("upgrade window %q must be a time range, optionally preceded by days", value)
+	}
+
+	w := &UpgradeWindow{days: map[time.Weekday]bool{}}
+	if len(fields) == 2 {
+		for _, d := range strings.Split(fields[0], ",") {
+			day, ok := weekdays[strings.ToLower(d)]
+			if !ok {
+				return nil, fmt.Errorf("upgrade window %q has an unknown day %q", value, d)
+			}
+			w.days[day] = true
+		}
+	}
+
+	times := strings.Split(fields[len(fields)-1], "-")
+	if len(times) != 2 {
+		return nil, fmt.Errorf("upgrade window %q must have a time range of the form HH:MM-HH:MM", value)
+	}
+	var err error
+	if w.start, err = parseTimeOfDay(times[0]); err != nil {
+		return nil, fmt.Errorf("upgrade window %q: %w", value, err)
+	}
+	if w.end, err = parseTimeOfDay(times[1]); err != nil {
+		return nil, fmt.Errorf("upgrade window %q: %w", value, err)
+	}
+	if w.start == w.end {
+		return nil, fmt.Errorf("upgrade window %q is empty", value)
+	}
+	return w, nil
+}
+
+// parseTimeOfDay parses HH:MM into the time since midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains returns true if the window is open at the given time
+func (w *UpgradeWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	sinceMidnight := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+
+	if w.start < w.end {
+		return w.opensOn(t.Weekday()) && sinceMidnight >= w.start && sinceMidnight < w.end
+	}
+	// The window spans midnight, so it is open late on the day it starts and early the day after
+	if sinceMidnight >= w.start {
+		return w.opensOn(t.Weekday())
+	}
+	return sinceMidnight < w.end && w.opensOn(t.AddDate(0, 0, -1).Weekday())
+}
+
+func (w *UpgradeWindow) opensOn(day time.Weekday) bool {
+	return len(w.days) == 0 || w.days[day]
+}
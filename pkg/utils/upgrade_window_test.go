@@ -0,0 +1,51 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpgradeWindow_Contains(t *testing.T) {
+	// A Saturday
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.June, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		window string
+		at     time.Time
+		want   bool
+	}{
+		{name: "inside daily window", window: "02:00-06:00", at: saturday(3, 0), want: true},
+		{name: "at start of window", window: "02:00-06:00", at: saturday(2, 0), want: true},
+		{name: "at end of window", window: "02:00-06:00", at: saturday(6, 0), want: false},
+		{name: "before daily window", window: "02:00-06:00", at: saturday(1, 59), want: false},
+		{name: "window on the day", window: "Sat,Sun 02:00-06:00", at: saturday(3, 0), want: true},
+		{name: "window on other days", window: "Mon,tue 02:00-06:00", at: saturday(3, 0), want: false},
+		{name: "late in window spanning midnight", window: "Sat 22:00-04:00", at: saturday(23, 0), want: true},
+		{name: "early in window spanning midnight", window: "Fri 22:00-04:00", at: saturday(1, 0), want: true},
+		{name: "early in window opened the day before", window: "Sat 22:00-04:00", at: saturday(1, 0), want: false},
+		{name: "other time zone", window: "02:00-06:00", at: saturday(3, 0).In(time.FixedZone("UTC+10", 10*3600)), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseUpgradeWindow(tt.window)
+			if err != nil {
+				t.Fatalf("ParseUpgradeWindow(%q) error = %v", tt.window, err)
+			}
+			if got := w.Contains(tt.at); got != tt.want {
+				t.Errorf("UpgradeWindow(%q).Contains(%v) = %v, want %v", tt.window, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUpgradeWindow_Invalid(t *testing.T) {
+	for _, window := range []string{"", "02:00", "2am-6am", "Someday 02:00-06:00", "02:00-02:00", "Sat 02:00-06:00 UTC"} {
+		if _, err := ParseUpgradeWindow(window); err == nil {
+			t.Errorf("ParseUpgradeWindow(%q) expected an error", window)
+		}
+	}
+}
@@ -4,19 +4,36 @@ package utils
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 
 	backplanev1 "github.com/stolostron/backplane-operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	UnitTestEnvVar = "UNIT_TEST"
+	// OperatorNamespaceEnvVar is the environment variable holding the namespace the operator runs in
+	OperatorNamespaceEnvVar = "POD_NAMESPACE"
+
+	// ManagedByLabel and MCENameLabel are stamped on every cluster-scoped resource the operator
+	// creates, since those cannot be owned by the multiclusterengine
+	ManagedByLabel = "backplane.managed-by"
+	MCENameLabel   = "backplane.mce-name"
+	// ManagedByValue is the value of ManagedByLabel
+	ManagedByValue = "backplane-operator"
 )
 
+// ErrOperatorNamespaceUnset is returned when the namespace of the operator cannot be determined
+var ErrOperatorNamespaceUnset = fmt.Errorf("the %s environment variable must be set to the namespace the operator runs in", OperatorNamespaceEnvVar)
+
 var onComponents = []string{
 	backplanev1.AssistedService,
 	backplanev1.ClusterLifecycle,
@@ -24,6 +41,7 @@ var onComponents = []string{
 	backplanev1.Discovery,
 	backplanev1.Hive,
 	backplanev1.ServerFoundation,
+	backplanev1.ServerFoundationProxy,
 	backplanev1.ClusterProxyAddon,
 	backplanev1.LocalCluster,
 	// backplanev1.ConsoleMCE, // determined by OCP version
@@ -33,6 +51,7 @@ var onComponents = []string{
 var offComponents = []string{
 	backplanev1.ManagedServiceAccount,
 	backplanev1.HyperShift,
+	backplanev1.ImageBasedInstallOperator,
 }
 
 // SetDefaultComponents returns true if changes are made
@@ -80,6 +99,28 @@ func AddBackplaneConfigLabels(u client.Object, name string) {
 	u.SetLabels(labels)
 }
 
+// ClusterScopedLabels returns the labels identifying the cluster-scoped resources created for the
+// named multiclusterengine
+func ClusterScopedLabels(name string) map[string]string {
+	return map[string]string{
+		ManagedByLabel: ManagedByValue,
+		MCENameLabel:   name,
+	}
+}
+
+// AddClusterScopedLabels adds the labels identifying a cluster-scoped resource created for the
+// named multiclusterengine, so tooling and the cleanup path can find it
+func AddClusterScopedLabels(u client.Object, name string) {
+	labels := make(map[string]string)
+	for key, value := range u.GetLabels() {
+		labels[key] = value
+	}
+	for key, value := range ClusterScopedLabels(name) {
+		labels[key] = value
+	}
+	u.SetLabels(labels)
+}
+
 // CoreToUnstructured converts a Core Kube resource to unstructured
 func CoreToUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
 	content, err := json.Marshal(obj)
@@ -162,6 +203,55 @@ func GetImagePullPolicy(m *backplanev1.MultiClusterEngine) corev1.PullPolicy {
 	return m.Spec.Overrides.ImagePullPolicy
 }
 
+// envVarReference matches a $(VAR) reference to an environment variable of the operator
+var envVarReference = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+// ImagePullSecretName returns the name of the image pull secret in the CR, with each $(VAR)
+// reference expanded from the operator's environment. Returns an error if a referenced variable is
+// not set or the name resolves to an empty string. An empty imagePullSecret resolves to no secret.
+func ImagePullSecretName(m *backplanev1.MultiClusterEngine) (string, error) {
+	if m.Spec.ImagePullSecret == "" {
+		return "", nil
+	}
+	var missing []string
+	name := envVarReference.ReplaceAllStringFunc(m.Spec.ImagePullSecret, func(ref string) string {
+		key := envVarReference.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			missing = append(missing, key)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("imagePullSecret %s references unset environment variables %v", m.Spec.ImagePullSecret, missing)
+	}
+	if name == "" {
+		return "", fmt.Errorf("imagePullSecret %s resolves to an empty name", m.Spec.ImagePullSecret)
+	}
+	return name, nil
+}
+
+// IsTrustBundleDisabled returns true if the trusted CA bundle has been disabled in the CR overrides
+func IsTrustBundleDisabled(m *backplanev1.MultiClusterEngine) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.DisableTrustBundle
+}
+
+// NetworkPoliciesEnabled returns true if NetworkPolicies have been requested in the CR overrides
+func NetworkPoliciesEnabled(m *backplanev1.MultiClusterEngine) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.DeployNetworkPolicies
+}
+
+// ServiceMonitorManagementDisabled returns true if the ServiceMonitors of components are left to
+// external tooling in the CR overrides
+func ServiceMonitorManagementDisabled(m *backplanev1.MultiClusterEngine) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.DisableServiceMonitorManagement
+}
+
+// IsMaintenanceMode returns true if maintenance mode has been enabled in the CR overrides
+func IsMaintenanceMode(m *backplanev1.MultiClusterEngine) bool {
+	return m.Spec.Overrides != nil && m.Spec.Overrides.MaintenanceMode
+}
+
 func GetTestImages() []string {
 	return []string{"registration_operator", "openshift_hive", "multicloud_manager",
 		"managedcluster_import_controller", "registration", "work", "discovery_operator", "cluster_curator_controller",
@@ -169,7 +259,8 @@ func GetTestImages() []string {
 		"assisted_service", "assisted_image_service", "postgresql_12", "assisted_installer_agent", "assisted_installer_controller",
 		"assisted_installer", "console_mce", "hypershift_addon_operator", "hypershift_operator",
 		"apiserver_network_proxy", "aws_encryption_provider", "cluster_api", "cluster_api_provider_agent", "cluster_api_provider_aws",
-		"cluster_api_provider_azure", "cluster_api_provider_kubevirt", "cluster_proxy_addon", "cluster_proxy"}
+		"cluster_api_provider_azure", "cluster_api_provider_kubevirt", "cluster_proxy_addon", "cluster_proxy",
+		"image_based_install_operator"}
 }
 
 func IsUnitTest() bool {
@@ -215,10 +306,35 @@ func Remove(s []string, str string) []string {
 	return s
 }
 
+// GetOperatorNamespace returns the namespace the operator runs in, read from POD_NAMESPACE. An
+// unset or empty variable is an error rather than a silent lookup in the wrong namespace.
+func GetOperatorNamespace() (string, error) {
+	namespace := strings.TrimSpace(os.Getenv(OperatorNamespaceEnvVar))
+	if namespace == "" {
+		return "", ErrOperatorNamespaceUnset
+	}
+	return namespace, nil
+}
+
+// OperatorNamespace returns the namespace the operator runs in and panics if it cannot be determined
 func OperatorNamespace() string {
-	deploymentNamespace, ok := os.LookupEnv("POD_NAMESPACE")
-	if !ok {
-		panic("Missing POD_NAMESPACE variable")
+	namespace, err := GetOperatorNamespace()
+	if err != nil {
+		panic(err)
+	}
+	return namespace
+}
+
+// PreferredGVK returns gvk at the version the API server prefers for its group and kind, as
+// discovered through the mapper. gvk is returned unchanged if the kind is not served, e.g. before
+// its CRD is installed.
+func PreferredGVK(mapper meta.RESTMapper, gvk schema.GroupVersionKind) schema.GroupVersionKind {
+	if mapper == nil {
+		return gvk
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind())
+	if err != nil {
+		return gvk
 	}
-	return deploymentNamespace
+	return mapping.GroupVersionKind
 }
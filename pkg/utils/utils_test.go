@@ -95,3 +95,34 @@ func Test_deduplicate(t *testing.T) {
 		t.Error("Removes did not work")
 	}
 }
+
+func TestImagePullSecretName(t *testing.T) {
+	t.Setenv("PULL_SECRET_NAME", "tenant-a-pull-secret")
+	t.Setenv("EMPTY_PULL_SECRET", "")
+
+	tests := []struct {
+		name    string
+		secret  string
+		want    string
+		wantErr bool
+	}{
+		{name: "no pull secret", secret: "", want: ""},
+		{name: "literal name", secret: "my-pull-secret", want: "my-pull-secret"},
+		{name: "env var reference", secret: "$(PULL_SECRET_NAME)", want: "tenant-a-pull-secret"},
+		{name: "env var reference with prefix", secret: "prod-$(PULL_SECRET_NAME)", want: "prod-tenant-a-pull-secret"},
+		{name: "unset env var", secret: "$(UNSET_PULL_SECRET_NAME)", wantErr: true},
+		{name: "empty env var", secret: "$(EMPTY_PULL_SECRET)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &backplanev1.MultiClusterEngine{Spec: backplanev1.MultiClusterEngineSpec{ImagePullSecret: tt.secret}}
+			got, err := ImagePullSecretName(m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImagePullSecretName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ImagePullSecretName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
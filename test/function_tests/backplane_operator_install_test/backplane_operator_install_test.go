@@ -458,6 +458,8 @@ var configurationTests = func() func() {
 				}
 				Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
 
+				// Components that were available before are only reported as Degraded once the
+				// degraded grace period has passed, so the recovering components show as Progressing
 				Eventually(func(g Gomega) {
 					g.Expect(k8sClient.Get(ctx, multiClusterEngine, key)).To(Succeed())
 					g.Expect(key.Status.Phase).To(Equal(backplane.MultiClusterEnginePhaseProgressing))